@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// checkpoint records which units of a run have completed, so an interrupted
+// run can resume where it left off
+type checkpoint struct {
+	path string
+
+	Completed []string `json:"completed"`
+	// Detected holds the orphans found in each completed region, so a resumed
+	// run still reports them and counts them toward -max-allowed
+	Detected  map[string][]enicleanup.OrphanedENI `json:"detected,omitempty"`
+	UpdatedAt string                              `json:"updatedAt"`
+}
+
+// loadCheckpoint reads the checkpoint at path, starting empty if it doesn't exist
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+
+	return cp, nil
+}
+
+// isComplete checks whether a unit completed in a previous attempt
+func (c *checkpoint) isComplete(unit string) bool {
+	return slices.Contains(c.Completed, unit)
+}
+
+// markComplete records a completed unit with the orphans detected in it and
+// saves the checkpoint. The file is replaced atomically so an interruption
+// never leaves it half written.
+func (c *checkpoint) markComplete(unit string, detected []enicleanup.OrphanedENI) error {
+	c.Completed = append(c.Completed, unit)
+	if len(detected) > 0 {
+		if c.Detected == nil {
+			c.Detected = make(map[string][]enicleanup.OrphanedENI)
+		}
+		c.Detected[unit] = detected
+	}
+	c.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// clear removes the checkpoint after a run completes cleanly
+func (c *checkpoint) clear() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// runWithCheckpoint runs region by region, recording each completed region in
+// the checkpoint and skipping regions completed by a previous attempt. The
+// orphans detected in skipped regions come from the checkpoint, so the reports
+// are written once for every region and -max-allowed counts them all. The
+// checkpoint is removed once every region has completed.
+func runWithCheckpoint(ctx context.Context, opts cliOptions) (runSummary, error) {
+	cp, err := loadCheckpoint(opts.CheckpointPath)
+	if err != nil {
+		return runSummary{}, err
+	}
+
+	// Expand sentinels up front so each region is checkpointed individually
+	regions, err := enicleanup.ExpandRegionsWithOptions(ctx, opts.Regions, opts.detectOptions())
+	if err != nil {
+		return runSummary{}, err
+	}
+
+	var total runSummary
+	for _, region := range regions {
+		if cp.isComplete(region) {
+			log.Printf("Skipping region %s, completed according to checkpoint %s", region, opts.CheckpointPath)
+			previous := cp.Detected[region]
+			total.add(runSummary{Detected: len(previous), ENIs: previous})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return total, fmt.Errorf("interrupted before region %s; rerun to resume from %s: %w", region, opts.CheckpointPath, err)
+		}
+
+		summary, err := runPass(ctx, opts, []string{region})
+		if err != nil {
+			return total, fmt.Errorf("region %s: %w", region, err)
+		}
+		total.add(summary)
+
+		// Leave the region to be rerun for the accounts that couldn't be scanned
+		if len(summary.FailedAccounts) > 0 {
+			continue
+		}
+		if err := cp.markComplete(region, summary.ENIs); err != nil {
+			return total, err
+		}
+	}
+
+	if err := writeReports(ctx, opts, total.ENIs); err != nil {
+		return total, err
+	}
+	if len(total.FailedAccounts) > 0 {
+		return total, nil
+	}
+	return total, cp.clear()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// daemonMetrics tracks counters exposed on the /metrics endpoint
+type daemonMetrics struct {
+	mu              sync.Mutex
+	runs            int
+	successCount    int
+	failureCount    int
+	skippedCount    int
+	runErrors       int
+	lastRunStart    time.Time
+	lastRunEnd      time.Time
+	lastRunDuration time.Duration
+}
+
+// runDaemon loops detection and cleanup on a schedule until ctx is cancelled.
+// On shutdown the region currently being processed is allowed to finish.
+func runDaemon(ctx context.Context, opts cliOptions) error {
+	metrics := &daemonMetrics{}
+	started := time.Now()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !metrics.healthy(started, opts.Interval) {
+			http.Error(w, "last successful run is stale", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.write(w)
+	})
+
+	server := &http.Server{
+		Addr:              opts.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Serving /healthz and /metrics on %s", opts.ListenAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		runCycle(ctx, opts, metrics)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Shutting down ENI cleanup daemon")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		case err := <-serverErr:
+			return fmt.Errorf("metrics server failed: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCycle runs one scheduled pass, processing one region at a time so a
+// shutdown request only takes effect between regions. The reports are
+// written once for the regions the cycle processed.
+func runCycle(ctx context.Context, opts cliOptions, metrics *daemonMetrics) {
+	metrics.startRun()
+	runCtx := context.WithoutCancel(ctx)
+
+	var cycle runSummary
+	for _, region := range opts.Regions {
+		if ctx.Err() != nil {
+			log.Printf("Shutdown requested, skipping remaining regions")
+			break
+		}
+
+		result, err := runPass(runCtx, opts, []string{region})
+		if err != nil {
+			log.Printf("Cleanup run failed for region %s: %v", region, err)
+			metrics.recordError()
+			continue
+		}
+		metrics.record(result.SuccessCount, result.FailureCount, result.SkippedCount)
+		cycle.add(result)
+	}
+
+	if err := writeReports(runCtx, opts, cycle.ENIs); err != nil {
+		log.Printf("Writing reports failed: %v", err)
+		metrics.recordError()
+	}
+
+	metrics.endRun()
+}
+
+func (m *daemonMetrics) startRun() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastRunStart = time.Now()
+}
+
+func (m *daemonMetrics) endRun() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs++
+	m.lastRunEnd = time.Now()
+	m.lastRunDuration = m.lastRunEnd.Sub(m.lastRunStart)
+}
+
+func (m *daemonMetrics) record(success, failure, skipped int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.successCount += success
+	m.failureCount += failure
+	m.skippedCount += skipped
+}
+
+func (m *daemonMetrics) recordError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runErrors++
+}
+
+// healthy reports whether a run has completed within two intervals
+func (m *daemonMetrics) healthy(started time.Time, interval time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	last := m.lastRunEnd
+	if last.IsZero() {
+		last = started
+	}
+	return time.Since(last) <= 2*interval
+}
+
+// write renders the metrics in the Prometheus text exposition format
+func (m *daemonMetrics) write(w http.ResponseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# TYPE eni_cleanup_runs_total counter\neni_cleanup_runs_total %d\n", m.runs)
+	fmt.Fprintf(w, "# TYPE eni_cleanup_run_errors_total counter\neni_cleanup_run_errors_total %d\n", m.runErrors)
+	fmt.Fprintf(w, "# TYPE eni_cleanup_enis_total counter\n")
+	fmt.Fprintf(w, "eni_cleanup_enis_total{outcome=\"success\"} %d\n", m.successCount)
+	fmt.Fprintf(w, "eni_cleanup_enis_total{outcome=\"failure\"} %d\n", m.failureCount)
+	fmt.Fprintf(w, "eni_cleanup_enis_total{outcome=\"skipped\"} %d\n", m.skippedCount)
+	fmt.Fprintf(w, "# TYPE eni_cleanup_last_run_duration_seconds gauge\neni_cleanup_last_run_duration_seconds %f\n", m.lastRunDuration.Seconds())
+	if !m.lastRunEnd.IsZero() {
+		fmt.Fprintf(w, "# TYPE eni_cleanup_last_run_timestamp_seconds gauge\neni_cleanup_last_run_timestamp_seconds %d\n", m.lastRunEnd.Unix())
+	}
+}
@@ -0,0 +1,682 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/report"
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// cliOptions holds the parsed command line flags
+type cliOptions struct {
+	Regions                    []string
+	DryRun                     bool
+	DisassociateOnly           bool
+	SecurityGroupId            string
+	DefaultSecurityGroupId     string
+	DetachGracePeriod          time.Duration
+	VerifyLoadBalancers        bool
+	VerifyNATGateways          bool
+	ASFFFile                   string
+	DOTFile                    string
+	ImportFile                 string
+	ImportLanguage             string
+	IncludeDeleteOnTermination bool
+	IncludeSharedSubnets       bool
+	IncludeRequesterManaged    bool
+	PolicyFile                 string
+	IncludeTrunkBranchENIs     bool
+	RecordAPIPath              string
+	ReplayAPIPath              string
+	RestoreSGTagKey            string
+	ManualCleanupTagKey        string
+	AttemptedCleanupTimeTagKey string
+	DeletionErrorTagKey        string
+	OmitDeletionErrorTag       bool
+	ReconcileTags              bool
+	ConfirmUnchanged           bool
+	TagOnly                    bool
+	AuditRunID                 string
+	TagConcurrency             int
+	DeleteConcurrency          int
+	DeleteAttempts             int
+	DeleteRetryBaseDelay       time.Duration
+	VerifyDeletion             bool
+	RegionGroups               map[string][]string
+	AccountRoles               []enicleanup.AccountRole
+	AccountConcurrency         int
+	CostFile                   string
+	TimingsFile                string
+	RespectRecentDeploys       bool
+	RecentDeployWindow         time.Duration
+	VerifyAttachmentOwners     bool
+	MaxVPCDeletionRatio        float64
+	VPCDeletionRatioOverrides  []string
+	EventBusName               string
+	PerENITimeout              time.Duration
+	DetachTimeout              time.Duration
+	DetectionProfile           string
+	EnrichmentConcurrency      int
+	DescribeBatchSize          int
+	RequireEmptyVPC            bool
+	LockTableName              string
+	LockTTL                    time.Duration
+	LockWaitTimeout            time.Duration
+	DecisionLog                bool
+	HandleAssociations         bool
+	RedactFields               []string
+	CostOptions                report.CostOptions
+	SecurityHub                bool
+	Daemon                     bool
+	Interval                   time.Duration
+	ListenAddr                 string
+	ExportPlan                 string
+	ApplyPlan                  string
+	NetworkInterfaceIds        []string
+	VpcIds                     []string
+	SubnetIds                  []string
+	AvailabilityZones          []string
+	IntraRegionParallelism     int
+	RegionConcurrency          int
+	DeletionStrikes            int
+	ExpectedAccountId          string
+	RoleArn                    string
+	ExternalId                 string
+	SessionName                string
+	Profile                    string
+	Endpoint                   string
+	QPS                        float64
+	ExcludeCIDRs               []string
+	SkipRequesterIds           []string
+	SkipReservedDescriptions   []string
+	IncludeTagKeys             []string
+	ExcludeTagKeys             []string
+	SkipInterfaceTypes         []string
+	OnlyInterfaceTypes         []string
+	ExcludeMacPrefixes         []string
+	Output                     string
+	MaxAllowed                 int
+	MaxDeletions               int
+	DetectOnly                 bool
+	CheckpointPath             string
+
+	// deletedENIs is shared by every run of the process, so ENIs deleted by one
+	// daemon cycle are ignored if a later describe still lists them
+	deletedENIs *enicleanup.DeletedENIs
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(enicleanup.ResultCodeFailed)
+	}
+
+	// Decision lines go to the same log as everything else
+	enicleanup.DecisionLogf = log.Printf
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := enicleanup.Preflight(ctx, enicleanup.PreflightOptions{
+		ExpectedAccountId: optionalString(opts.ExpectedAccountId),
+		RoleOptions:       opts.roleOptions(),
+		ConfigOptions:     opts.configOptions(),
+	}); err != nil {
+		exitWithError("Preflight checks failed", err)
+	}
+
+	if opts.Daemon {
+		if err := runDaemon(ctx, opts); err != nil {
+			exitWithError("Daemon exited with error", err)
+		}
+		return
+	}
+
+	if opts.ExportPlan != "" {
+		if err := exportPlan(ctx, opts); err != nil {
+			exitWithError("ENI plan export failed", err)
+		}
+		return
+	}
+
+	if opts.ApplyPlan != "" {
+		plan, err := report.ReadPlan(opts.ApplyPlan)
+		if err != nil {
+			exitWithError("Reading plan failed", err)
+		}
+		if len(plan.Candidates) == 0 {
+			log.Printf("Plan %s has no approved ENIs, nothing to do", opts.ApplyPlan)
+			return
+		}
+		opts.NetworkInterfaceIds = plan.NetworkInterfaceIds()
+		log.Printf("Applying plan %s with %d approved ENIs", opts.ApplyPlan, len(opts.NetworkInterfaceIds))
+	}
+
+	var summary runSummary
+	if opts.CheckpointPath != "" {
+		summary, err = runWithCheckpoint(ctx, opts)
+	} else {
+		summary, err = runOnce(ctx, opts, opts.Regions)
+	}
+	if err != nil {
+		exitWithError("ENI cleanup failed", err)
+	}
+	if err := writeTimings(opts.TimingsFile, summary.RegionTimings); err != nil {
+		log.Print(err)
+	}
+	if !opts.DetectOnly {
+		log.Printf("ENI cleanup completed: %d succeeded, %d failed, %d skipped",
+			summary.SuccessCount, summary.FailureCount, summary.SkippedCount)
+	}
+
+	// These failed every step and nothing in AWS records it, so list them last where they're seen
+	if len(summary.DeadLetter) > 0 {
+		log.Printf("ESCALATE: %d ENIs failed cleanup and could not be tagged for manual cleanup:", len(summary.DeadLetter))
+		for _, eni := range summary.DeadLetter {
+			log.Printf("  %s in %s (%s): %s", eni.ID, eni.Region, eni.VpcID, strings.Join(eni.Errors, "; "))
+		}
+	}
+
+	// Gate on the number detected before cleanup, so "audit and clean" still fails the build
+	if opts.MaxAllowed >= 0 && summary.Detected > opts.MaxAllowed {
+		log.Printf("Detected %d orphaned ENIs, more than the %d allowed", summary.Detected, opts.MaxAllowed)
+		os.Exit(exitThresholdExceeded)
+	}
+
+	if len(summary.VerificationFailed) > 0 {
+		log.Printf("%d deleted ENIs still exist: %v", len(summary.VerificationFailed), summary.VerificationFailed)
+	}
+	if summary.Truncated {
+		log.Printf("ENI cleanup stopped before processing every candidate")
+	}
+	os.Exit(enicleanup.ResultCode(summary.CleanupResult))
+}
+
+// exitThresholdExceeded is the exit code when more orphans are detected than
+// -max-allowed. The other exit codes are the enicleanup result codes.
+const exitThresholdExceeded = 5
+
+// exitWithError logs an error that stopped the run and exits with its result code
+func exitWithError(msg string, err error) {
+	log.Printf("%s: %v", msg, err)
+	os.Exit(enicleanup.ErrorResultCode(err))
+}
+
+// runSummary is the outcome of a single detection and cleanup pass
+type runSummary struct {
+	enicleanup.CleanupResult
+
+	// Detected is the number of orphaned ENIs found before cleanup
+	Detected int
+	// ENIs are the orphaned ENIs found, which the reports are written for
+	ENIs []enicleanup.OrphanedENI
+	// RegionTimings breaks the pass down by region
+	RegionTimings map[string]enicleanup.RegionTiming
+}
+
+// add merges the summary of a pass over other regions into s
+func (s *runSummary) add(other runSummary) {
+	s.Detected += other.Detected
+	s.ENIs = append(s.ENIs, other.ENIs...)
+	s.SuccessCount += other.SuccessCount
+	s.FailureCount += other.FailureCount
+	s.SkippedCount += other.SkippedCount
+	s.CleanedENIs = append(s.CleanedENIs, other.CleanedENIs...)
+	s.Errors = append(s.Errors, other.Errors...)
+	s.SkippedDetails = append(s.SkippedDetails, other.SkippedDetails...)
+	s.VerificationFailed = append(s.VerificationFailed, other.VerificationFailed...)
+	s.DeadLetter = append(s.DeadLetter, other.DeadLetter...)
+	s.ManualCleanup = append(s.ManualCleanup, other.ManualCleanup...)
+	s.Truncated = s.Truncated || other.Truncated
+	if len(other.RegionTimings) > 0 && s.RegionTimings == nil {
+		s.RegionTimings = make(map[string]enicleanup.RegionTiming)
+	}
+	maps.Copy(s.RegionTimings, other.RegionTimings)
+	for _, account := range other.FailedAccounts {
+		if !slices.Contains(s.FailedAccounts, account) {
+			s.FailedAccounts = append(s.FailedAccounts, account)
+		}
+	}
+}
+
+// parseFlags parses the command line arguments into cliOptions
+func parseFlags(args []string) (cliOptions, error) {
+	var opts cliOptions
+	var regions, vpcIds, subnetIds, availabilityZones, excludeCIDRs, skipRequesterIds, skipReservedDescriptions, includeTagKeys, excludeTagKeys, skipInterfaceTypes, onlyInterfaceTypes, excludeMacPrefixes, redactFields, vpcRatioOverrides string
+
+	fs := flag.NewFlagSet("eni-cleanup", flag.ContinueOnError)
+	fs.StringVar(&regions, "regions", "", "Comma-separated list of AWS regions to scan")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Only log what would be done without taking action")
+	fs.BoolVar(&opts.DisassociateOnly, "disassociate-only", false, "Only disassociate security groups, don't delete ENIs")
+	fs.StringVar(&opts.SecurityGroupId, "security-group-id", "", "Target security group ID to disassociate from ENIs")
+	fs.StringVar(&opts.DefaultSecurityGroupId, "default-security-group-id", "", "Default security group ID to assign if needed")
+	fs.DurationVar(&opts.DetachGracePeriod, "detach-grace-period", enicleanup.DefaultDetachGracePeriod, "Skip ENIs this tool detached within this period (0 disables)")
+	fs.BoolVar(&opts.VerifyLoadBalancers, "verify-load-balancers", false, "Treat load balancer ENIs as orphaned once their load balancer no longer exists")
+	fs.BoolVar(&opts.VerifyNATGateways, "verify-nat-gateways", false, "Treat available NAT gateway ENIs as orphaned once their NAT gateway no longer exists")
+	fs.StringVar(&opts.ASFFFile, "asff-file", "", "Write detected orphans as AWS Security Finding Format findings to this file")
+	fs.StringVar(&opts.DOTFile, "dot-file", "", "Write a Graphviz DOT graph linking detected orphans to their VPCs, subnets and security groups to this file")
+	fs.StringVar(&opts.ImportFile, "import-file", "", "Write Pulumi code importing detected orphans, with the equivalent pulumi import commands, to this file")
+	fs.StringVar(&opts.ImportLanguage, "import-language", report.ImportLanguageGo, "Language of the -import-file code: go or typescript")
+	fs.BoolVar(&opts.SecurityHub, "security-hub", false, "Import detected orphans into AWS Security Hub as findings")
+	fs.BoolVar(&opts.Daemon, "daemon", false, "Run detection and cleanup continuously on a schedule")
+	fs.DurationVar(&opts.Interval, "interval", 15*time.Minute, "Time between runs in daemon mode")
+	fs.StringVar(&opts.ListenAddr, "listen", ":8080", "Address for the /healthz and /metrics endpoints in daemon mode")
+	fs.IntVar(&opts.IntraRegionParallelism, "intra-region-parallelism", 0, "Scan up to this many availability zones of a region concurrently (0 or 1 scans sequentially)")
+	fs.IntVar(&opts.RegionConcurrency, "region-concurrency", enicleanup.DefaultRegionConcurrency, "Regions scanned concurrently")
+	fs.IntVar(&opts.DeletionStrikes, "deletion-strikes", 0, "Only act on an ENI once this many runs have found it orphaned, counted in a tag")
+	fs.StringVar(&opts.ExpectedAccountId, "expected-account-id", "", "Abort unless the AWS credentials belong to this account ID")
+	fs.StringVar(&opts.RoleArn, "role-arn", "", "Assume this IAM role for every AWS call, e.g. to clean up another account")
+	fs.StringVar(&opts.ExternalId, "external-id", "", "External ID to pass when assuming -role-arn")
+	fs.StringVar(&opts.Profile, "profile", "", "AWS shared config profile to use instead of the default credentials")
+	fs.StringVar(&opts.Endpoint, "endpoint", "", "Override the endpoint of every AWS service, e.g. http://localhost:4566 for LocalStack")
+	fs.Float64Var(&opts.QPS, "qps", 0, "Maximum EC2 calls per second in each region (0 disables the limit)")
+	fs.StringVar(&opts.SessionName, "session-name", "", "Session name for the -role-arn session (default "+enicleanup.DefaultSessionName+")")
+	fs.StringVar(&opts.PolicyFile, "policy-file", "", "Decide which ENIs to clean up with this Rego policy instead of the built-in filters (requires the opa CLI)")
+	fs.StringVar(&opts.RecordAPIPath, "record-api", "", "Append every EC2 request and response to this file, with account IDs redacted, for diagnosing a run")
+	fs.StringVar(&opts.ReplayAPIPath, "replay-api", "", "Answer EC2 calls from a file written by -record-api instead of calling AWS")
+	fs.StringVar(&opts.RestoreSGTagKey, "restore-sg-tag-key", enicleanup.DefaultRestoreSecurityGroupTagKey, "Tag holding the security group to assign in place of the default when an ENI's groups are removed")
+	fs.StringVar(&opts.ManualCleanupTagKey, "manual-cleanup-tag-key", enicleanup.DefaultManualCleanupTagKey, "Tag marking ENIs whose cleanup failed")
+	fs.StringVar(&opts.AttemptedCleanupTimeTagKey, "attempted-cleanup-time-tag-key", enicleanup.DefaultAttemptedCleanupTimeTagKey, "Tag recording when cleanup of a failed ENI was attempted")
+	fs.StringVar(&opts.DeletionErrorTagKey, "deletion-error-tag-key", enicleanup.DefaultDeletionErrorTagKey, "Tag recording why cleanup of an ENI failed")
+	fs.BoolVar(&opts.OmitDeletionErrorTag, "omit-deletion-error-tag", false, "Don't write the deletion error tag to ENIs whose cleanup failed")
+	fs.BoolVar(&opts.ReconcileTags, "reconcile-tags", false, "Remove the manual cleanup tags from ENIs that are no longer orphaned")
+	fs.BoolVar(&opts.ConfirmUnchanged, "confirm-unchanged", false, "Re-describe each ENI before acting on it and skip it if it changed since detection")
+	fs.BoolVar(&opts.TagOnly, "tag-only", false, "Tag candidate ENIs with "+enicleanup.AuditTagKey+" instead of cleaning them up")
+	fs.StringVar(&opts.AuditRunID, "audit-run-id", "", "Tag value for -tag-only (default: current UTC date)")
+	fs.IntVar(&opts.TagConcurrency, "tag-concurrency", enicleanup.DefaultTagConcurrency, "Concurrent CreateTags calls for -tag-only")
+	fs.IntVar(&opts.DeleteConcurrency, "delete-concurrency", enicleanup.DefaultDeleteConcurrency, "ENIs cleaned up concurrently per region")
+	fs.IntVar(&opts.DeleteAttempts, "delete-attempts", enicleanup.DefaultDeleteAttempts, "Times deleting an ENI is attempted before it is tagged for manual cleanup")
+	fs.DurationVar(&opts.DeleteRetryBaseDelay, "delete-retry-base-delay", enicleanup.DefaultDeleteRetryBaseDelay, "Wait before the first delete retry, doubling with each further retry")
+	fs.Func("region-group", "Define a region group usable in -regions as name=region,region (repeatable)", func(value string) error {
+		name, regions, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("expected name=region,region, got %q", value)
+		}
+		if opts.RegionGroups == nil {
+			opts.RegionGroups = make(map[string][]string)
+		}
+		opts.RegionGroups[name] = splitList(regions)
+		return nil
+	})
+	fs.Func("account-role", "Scan an account by assuming a role in it, as account-id=role-arn (repeatable)", func(value string) error {
+		accountID, roleArn, ok := strings.Cut(value, "=")
+		if !ok || accountID == "" || roleArn == "" {
+			return fmt.Errorf("expected account-id=role-arn, got %q", value)
+		}
+		opts.AccountRoles = append(opts.AccountRoles, enicleanup.AccountRole{AccountID: accountID, RoleArn: roleArn})
+		return nil
+	})
+	fs.IntVar(&opts.AccountConcurrency, "account-concurrency", enicleanup.DefaultAccountConcurrency, "Accounts from -account-role scanned concurrently")
+	fs.BoolVar(&opts.RespectRecentDeploys, "respect-recent-deploys", false, "Skip ENIs in VPCs whose "+enicleanup.DefaultDeployTimestampTagKey+" tag is within -recent-deploy-window")
+	fs.DurationVar(&opts.RecentDeployWindow, "recent-deploy-window", enicleanup.DefaultRecentDeployWindow, "Quiet period after a deployment for -respect-recent-deploys")
+	fs.StringVar(&opts.DetectionProfile, "detection-profile", "", "Only consider ENIs matching this built-in detection profile (lambda-leftovers, eks-sg-for-pods)")
+	fs.IntVar(&opts.DescribeBatchSize, "describe-batch-size", 0, "ENIs per DescribeNetworkInterfaces page, clamped to 5-1000 (0 uses the EC2 default)")
+	fs.IntVar(&opts.EnrichmentConcurrency, "enrichment-concurrency", enicleanup.DefaultEnrichmentConcurrency, "Concurrent lookups per region for -respect-recent-deploys and -verify-attachment-owners")
+	fs.BoolVar(&opts.VerifyAttachmentOwners, "verify-attachment-owners", false, "Look up the instances ENIs are attached to and flag ENIs whose instance no longer exists")
+	fs.Float64Var(&opts.MaxVPCDeletionRatio, "max-vpc-deletion-ratio", 0, "Withhold cleanup in VPCs where more than this fraction of ENIs are candidates (0 disables)")
+	fs.StringVar(&vpcRatioOverrides, "vpc-deletion-ratio-overrides", "", "Comma-separated VPC IDs exempt from -max-vpc-deletion-ratio")
+	fs.StringVar(&redactFields, "redact-fields", "", "Comma-separated fields to mask in plan, report and ndjson output (privateIpAddress, accountId, tags, description, vpcId, subnetId, securityGroups, macAddress)")
+	fs.StringVar(&opts.CostFile, "cost-file", "", "Write the estimated monthly cost of idle Elastic IPs held by detected orphans to this JSON file")
+	fs.StringVar(&opts.TimingsFile, "timings-file", "", "Write how long each region took to scan and clean up, with its ENI counts, to this JSON file")
+	fs.Float64Var(&opts.CostOptions.EIPMonthlyRateUSD, "eip-monthly-rate", report.DefaultEIPMonthlyRateUSD, "Monthly charge in USD per idle Elastic IP for the cost estimate")
+	fs.Func("eip-monthly-rate-region", "Override the Elastic IP rate for a region as region=rate (repeatable)", func(value string) error {
+		region, rate, ok := strings.Cut(value, "=")
+		if !ok || region == "" {
+			return fmt.Errorf("expected region=rate, got %q", value)
+		}
+		usd, err := strconv.ParseFloat(rate, 64)
+		if err != nil || usd < 0 {
+			return fmt.Errorf("invalid rate %q for region %s", rate, region)
+		}
+		if opts.CostOptions.EIPMonthlyRateUSDByRegion == nil {
+			opts.CostOptions.EIPMonthlyRateUSDByRegion = make(map[string]float64)
+		}
+		opts.CostOptions.EIPMonthlyRateUSDByRegion[region] = usd
+		return nil
+	})
+	fs.StringVar(&opts.EventBusName, "event-bus-name", "", "Put a "+enicleanup.EventDetailTypeCleanupCompleted+" event with the run summary on this EventBridge bus after cleanup")
+	fs.DurationVar(&opts.DetachTimeout, "detach-timeout", enicleanup.DefaultDetachWait, "Wait this long for a detached ENI to become available before tagging it for manual cleanup instead of deleting it")
+	fs.DurationVar(&opts.PerENITimeout, "per-eni-timeout", 0, "Give up on an ENI whose modify, detach, wait and delete sequence takes longer than this, tagging it for manual cleanup (0 disables)")
+	fs.BoolVar(&opts.RequireEmptyVPC, "require-empty-vpc", false, "Only clean up ENIs in VPCs without any instances that haven't terminated")
+	fs.BoolVar(&opts.HandleAssociations, "handle-associations", false, "Disassociate an ENI's Elastic IPs before deleting it, keeping the addresses")
+	fs.BoolVar(&opts.DecisionLog, "decision-log", false, "Log one line per ENI with its decision: <eni-id> <region> <decision> <reason>")
+	fs.StringVar(&opts.LockTableName, "lock-table", "", "DynamoDB table holding an advisory lock so only one cleanup run per account-region proceeds at a time")
+	fs.DurationVar(&opts.LockTTL, "lock-ttl", enicleanup.DefaultLockTTL, "How long a cleanup lock is held before another run may take it over")
+	fs.DurationVar(&opts.LockWaitTimeout, "lock-wait", 0, "How long to wait for a cleanup lock held by another run before skipping the region")
+	fs.BoolVar(&opts.VerifyDeletion, "verify-deletion", false, "Re-describe deleted ENIs to confirm they are gone")
+	fs.BoolVar(&opts.IncludeTrunkBranchENIs, "include-trunk-branch-enis", false, "Also consider the trunk and branch ENIs the VPC CNI manages for EKS security groups for pods")
+	fs.BoolVar(&opts.IncludeDeleteOnTermination, "include-delete-on-termination", false, "Also consider ENIs that AWS deletes along with their instance")
+	fs.BoolVar(&opts.IncludeSharedSubnets, "include-shared-subnets", false, "Also consider ENIs in RAM-shared subnets owned by another account")
+	fs.BoolVar(&opts.IncludeRequesterManaged, "include-requester-managed", false, "Also consider ENIs AWS manages on the requester's behalf")
+	fs.StringVar(&excludeMacPrefixes, "exclude-mac-prefixes", "", "Comma-separated MAC address prefixes (e.g. 0a:1b:2c); matching ENIs are never touched")
+	fs.StringVar(&excludeCIDRs, "exclude-cidrs", "", "Comma-separated CIDR ranges; ENIs whose primary private IP is in one are never touched")
+	fs.StringVar(&vpcIds, "vpc-ids", "", "Comma-separated VPC IDs to limit detection to")
+	fs.StringVar(&subnetIds, "subnet-ids", "", "Comma-separated subnet IDs to limit detection to")
+	fs.StringVar(&availabilityZones, "availability-zones", "", "Comma-separated availability zones to limit detection to; each must be in one of the scanned regions")
+	fs.StringVar(&skipRequesterIds, "skip-requester-ids", "", "Comma-separated requester IDs whose ENIs are skipped, in addition to the default AWS service requesters")
+	fs.StringVar(&skipReservedDescriptions, "skip-reserved-descriptions", "", "Comma-separated description substrings whose ENIs are skipped, in addition to the default reserved descriptions")
+	fs.StringVar(&includeTagKeys, "include-tag-keys", "", "Comma-separated tag keys; only ENIs with at least one of them are considered")
+	fs.StringVar(&excludeTagKeys, "exclude-tag-keys", "", "Comma-separated tag keys; ENIs with any of them are never touched")
+	fs.StringVar(&skipInterfaceTypes, "skip-interface-types", "", "Comma-separated interface types whose ENIs are skipped, in addition to the AWS-managed types skipped by default")
+	fs.StringVar(&onlyInterfaceTypes, "only-interface-types", "", "Comma-separated interface types to consider, e.g. interface; listing an AWS-managed type opts in to it")
+	fs.StringVar(&opts.Output, "output", "text", "Output format: text, or ndjson to stream one JSON object per processed ENI to stdout")
+	fs.IntVar(&opts.MaxAllowed, "max-allowed", -1, "Exit with status 5 if more orphaned ENIs than this are detected (-1 disables)")
+	fs.IntVar(&opts.MaxDeletions, "max-deletions", -1, "Stop deleting after this many ENIs and skip the rest (-1 disables)")
+	fs.BoolVar(&opts.DetectOnly, "detect-only", false, "Only detect and report orphaned ENIs, without cleaning them up")
+	fs.StringVar(&opts.CheckpointPath, "checkpoint", "", "Record completed regions in this JSON file and skip them when rerun after an interruption")
+	fs.StringVar(&opts.ExportPlan, "export-plan", "", "Only detect, writing the candidates to this plan file for review, then exit")
+	fs.StringVar(&opts.ApplyPlan, "apply-plan", "", "Only clean up ENIs approved in this plan file that are still orphaned")
+
+	if err := fs.Parse(args); err != nil {
+		return cliOptions{}, err
+	}
+
+	opts.deletedENIs = enicleanup.NewDeletedENIs()
+	opts.Regions = splitList(regions)
+	opts.ExcludeCIDRs = splitList(excludeCIDRs)
+	opts.SkipRequesterIds = splitList(skipRequesterIds)
+	opts.SkipReservedDescriptions = splitList(skipReservedDescriptions)
+	opts.IncludeTagKeys = splitList(includeTagKeys)
+	opts.ExcludeTagKeys = splitList(excludeTagKeys)
+	opts.VpcIds = splitList(vpcIds)
+	opts.SubnetIds = splitList(subnetIds)
+	opts.AvailabilityZones = splitList(availabilityZones)
+	opts.SkipInterfaceTypes = splitList(skipInterfaceTypes)
+	opts.OnlyInterfaceTypes = splitList(onlyInterfaceTypes)
+	opts.ExcludeMacPrefixes = splitList(excludeMacPrefixes)
+	opts.RedactFields = splitList(redactFields)
+	opts.VPCDeletionRatioOverrides = splitList(vpcRatioOverrides)
+	if err := report.ValidateRedactFields(opts.RedactFields); err != nil {
+		return cliOptions{}, fmt.Errorf("-redact-fields: %w", err)
+	}
+	if len(opts.Regions) == 0 {
+		return cliOptions{}, fmt.Errorf("at least one region must be specified with -regions")
+	}
+	if opts.Output != "text" && opts.Output != "ndjson" {
+		return cliOptions{}, fmt.Errorf("-output must be text or ndjson, got %q", opts.Output)
+	}
+	if opts.ImportLanguage != report.ImportLanguageGo && opts.ImportLanguage != report.ImportLanguageTypeScript {
+		return cliOptions{}, fmt.Errorf("-import-language must be go or typescript, got %q", opts.ImportLanguage)
+	}
+	if opts.Daemon && opts.Interval <= 0 {
+		return cliOptions{}, fmt.Errorf("-interval must be positive")
+	}
+	if opts.ExportPlan != "" && opts.ApplyPlan != "" {
+		return cliOptions{}, fmt.Errorf("-export-plan and -apply-plan are mutually exclusive")
+	}
+	if opts.Daemon && opts.CheckpointPath != "" {
+		return cliOptions{}, fmt.Errorf("-checkpoint cannot be used with -daemon")
+	}
+	if opts.RecordAPIPath != "" && opts.ReplayAPIPath != "" {
+		return cliOptions{}, fmt.Errorf("-record-api and -replay-api are mutually exclusive")
+	}
+	if opts.Daemon && opts.MaxAllowed >= 0 {
+		return cliOptions{}, fmt.Errorf("-max-allowed cannot be used with -daemon")
+	}
+	if len(opts.AccountRoles) > 0 && (opts.ExportPlan != "" || opts.ApplyPlan != "") {
+		return cliOptions{}, fmt.Errorf("-export-plan and -apply-plan cannot be used with -account-role")
+	}
+	if opts.Daemon && (opts.ExportPlan != "" || opts.ApplyPlan != "") {
+		return cliOptions{}, fmt.Errorf("-export-plan and -apply-plan cannot be used with -daemon")
+	}
+	if err := opts.detectOptions().Validate(); err != nil {
+		return cliOptions{}, fmt.Errorf("invalid options: %w", err)
+	}
+
+	return opts, nil
+}
+
+// runOnce performs a single detection and cleanup pass over the given
+// regions and writes the reports for it
+func runOnce(ctx context.Context, opts cliOptions, regions []string) (runSummary, error) {
+	summary, err := runPass(ctx, opts, regions)
+	if err != nil {
+		return runSummary{}, err
+	}
+	return summary, writeReports(ctx, opts, summary.ENIs)
+}
+
+// runPass performs a single detection and cleanup pass over the given
+// regions without writing reports, so passes over several sets of regions
+// can be combined into one report
+func runPass(ctx context.Context, opts cliOptions, regions []string) (runSummary, error) {
+	if len(opts.AccountRoles) > 0 {
+		return runAccounts(ctx, opts, regions)
+	}
+
+	detected, err := enicleanup.DetectOrphanedENIsWithDetails(ctx, regions, opts.detectOptions())
+	if err != nil {
+		return runSummary{}, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+	}
+	orphanedENIs := detected.ENIs
+	logDetection(detected, regions)
+
+	summary := runSummary{Detected: len(orphanedENIs), ENIs: orphanedENIs}
+	summary.SkippedDetails = detected.Skipped
+	if opts.DetectOnly {
+		summary.RegionTimings = enicleanup.RegionTimings(detected, enicleanup.CleanupResult{})
+		if opts.DecisionLog {
+			for _, eni := range orphanedENIs {
+				enicleanup.LogDecision(eni.ID, eni.Region, enicleanup.DecisionCandidate, eni.Reason)
+			}
+		}
+		return summary, nil
+	}
+
+	summary.CleanupResult = enicleanup.CleanupOrphanedENIsWithOptions(ctx, orphanedENIs, opts.cleanupOptions())
+	enicleanup.ReconcileManualCleanupTags(ctx, detected, opts.cleanupOptions(), &summary.CleanupResult)
+	summary.SkippedDetails = detected.Skipped
+	summary.RegionTimings = enicleanup.RegionTimings(detected, summary.CleanupResult)
+	for _, errMsg := range summary.Errors {
+		log.Print(errMsg)
+	}
+
+	return summary, nil
+}
+
+// runAccounts performs a single detection and cleanup pass in each
+// -account-role account
+func runAccounts(ctx context.Context, opts cliOptions, regions []string) (runSummary, error) {
+	var cleanupOptions *enicleanup.CleanupOptions
+	if !opts.DetectOnly {
+		options := opts.cleanupOptions()
+		cleanupOptions = &options
+	}
+	results := enicleanup.RunAccounts(ctx, regions, opts.AccountRoles, opts.detectOptions(), cleanupOptions, opts.AccountConcurrency)
+	detected, cleanup := enicleanup.CombineAccountResults(results)
+	if len(cleanup.FailedAccounts) == len(opts.AccountRoles) {
+		return runSummary{}, fmt.Errorf("failed to scan every AWS account: %s", strings.Join(cleanup.Errors, "; "))
+	}
+	logDetection(detected, regions)
+
+	summary := runSummary{Detected: len(detected.ENIs), ENIs: detected.ENIs}
+	summary.CleanupResult = cleanup
+	summary.SkippedDetails = detected.Skipped
+	summary.RegionTimings = enicleanup.RegionTimings(detected, cleanup)
+	if opts.DetectOnly && opts.DecisionLog {
+		for _, eni := range detected.ENIs {
+			enicleanup.LogDecision(eni.ID, eni.Region, enicleanup.DecisionCandidate, eni.Reason)
+		}
+	}
+	for _, errMsg := range summary.Errors {
+		log.Print(errMsg)
+	}
+
+	return summary, nil
+}
+
+// logDetection logs the outcome of detection
+func logDetection(detected enicleanup.DetectResult, regions []string) {
+	log.Printf("Detected %d orphaned ENIs in %s, spared %d", len(detected.ENIs), strings.Join(regions, ", "), len(detected.Skipped))
+	for _, skipped := range detected.Skipped {
+		log.Printf("Spared ENI %s in %s: %s (%s)", skipped.ID, skipped.Region, skipped.Reason, skipped.Detail)
+	}
+	for _, aborted := range detected.AbortedVPCs {
+		log.Printf("Withheld cleanup in %s in %s: %d of %d ENIs are candidates", aborted.VpcID, aborted.Region, aborted.Candidates, aborted.Total)
+	}
+	for _, regionErr := range detected.Errors {
+		log.Printf("Failed to scan %v", regionErr)
+	}
+}
+
+// exportPlan detects orphaned ENIs and writes them to a plan file without taking action
+func exportPlan(ctx context.Context, opts cliOptions) error {
+	orphanedENIs, err := enicleanup.DetectOrphanedENIs(ctx, opts.Regions, opts.detectOptions())
+	if err != nil {
+		return fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+	}
+
+	data, err := report.MarshalPlan(report.FormatPlan(report.RedactENIs(orphanedENIs, opts.RedactFields)))
+	if err != nil {
+		return fmt.Errorf("failed to render plan: %w", err)
+	}
+	if err := os.WriteFile(opts.ExportPlan, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write plan to %s: %w", opts.ExportPlan, err)
+	}
+
+	log.Printf("Wrote plan with %d candidate ENIs to %s", len(orphanedENIs), opts.ExportPlan)
+	return nil
+}
+
+// roleOptions returns the role to assume in place of the default credentials
+func (opts cliOptions) roleOptions() enicleanup.RoleOptions {
+	return enicleanup.RoleOptions{RoleArn: opts.RoleArn, ExternalId: opts.ExternalId, SessionName: opts.SessionName}
+}
+
+// configOptions returns the AWS profile and endpoint to load config from and
+// the EC2 call rate limit
+func (opts cliOptions) configOptions() enicleanup.ConfigOptions {
+	return enicleanup.ConfigOptions{Profile: optionalString(opts.Profile), Endpoint: optionalString(opts.Endpoint), QPS: opts.QPS}
+}
+
+// detectOptions builds the engine detection options from the flags
+func (opts cliOptions) detectOptions() enicleanup.DetectOptions {
+	return enicleanup.DetectOptions{
+		LogLevel:                   "info",
+		SecurityGroupId:            optionalString(opts.SecurityGroupId),
+		DetachGracePeriod:          opts.DetachGracePeriod,
+		VerifyLoadBalancers:        opts.VerifyLoadBalancers,
+		VerifyNATGateways:          opts.VerifyNATGateways,
+		NetworkInterfaceIds:        opts.NetworkInterfaceIds,
+		VpcIds:                     opts.VpcIds,
+		SubnetIds:                  opts.SubnetIds,
+		AvailabilityZones:          opts.AvailabilityZones,
+		IntraRegionParallelism:     opts.IntraRegionParallelism,
+		RegionConcurrency:          opts.RegionConcurrency,
+		ExcludeCIDRs:               opts.ExcludeCIDRs,
+		SkipRequesterIds:           opts.SkipRequesterIds,
+		SkipReservedDescriptions:   opts.SkipReservedDescriptions,
+		IncludeTagKeys:             opts.IncludeTagKeys,
+		ExcludeTagKeys:             opts.ExcludeTagKeys,
+		SkipInterfaceTypes:         opts.SkipInterfaceTypes,
+		OnlyInterfaceTypes:         opts.OnlyInterfaceTypes,
+		ExcludeMacPrefixes:         opts.ExcludeMacPrefixes,
+		IncludeDeleteOnTermination: opts.IncludeDeleteOnTermination,
+		IncludeSharedSubnets:       opts.IncludeSharedSubnets,
+		IncludeRequesterManaged:    opts.IncludeRequesterManaged,
+		PolicyFile:                 optionalString(opts.PolicyFile),
+		IncludeTrunkBranchENIs:     opts.IncludeTrunkBranchENIs,
+		RecordAPIPath:              optionalString(opts.RecordAPIPath),
+		ReplayAPIPath:              optionalString(opts.ReplayAPIPath),
+		RegionGroups:               opts.RegionGroups,
+		RespectRecentDeploys:       opts.RespectRecentDeploys,
+		RecentDeployWindow:         opts.RecentDeployWindow,
+		VerifyAttachmentOwners:     opts.VerifyAttachmentOwners,
+		MaxVPCDeletionRatio:        opts.MaxVPCDeletionRatio,
+		VPCDeletionRatioOverrides:  opts.VPCDeletionRatioOverrides,
+		DetectionProfile:           opts.DetectionProfile,
+		EnrichmentConcurrency:      opts.EnrichmentConcurrency,
+		DescribeBatchSize:          opts.DescribeBatchSize,
+		DecisionLog:                opts.DecisionLog,
+		DeletedENIs:                opts.deletedENIs,
+		RoleOptions:                opts.roleOptions(),
+		ConfigOptions:              opts.configOptions(),
+	}
+}
+
+// cleanupOptions builds the engine cleanup options from the flags
+func (opts cliOptions) cleanupOptions() enicleanup.CleanupOptions {
+	options := enicleanup.CleanupOptions{
+		DryRun:                     opts.DryRun,
+		DisassociateOnly:           opts.DisassociateOnly,
+		DefaultSecurityGroupId:     optionalString(opts.DefaultSecurityGroupId),
+		TargetSecurityGroupId:      optionalString(opts.SecurityGroupId),
+		DeletionStrikesRequired:    opts.DeletionStrikes,
+		RestoreSecurityGroupTagKey: opts.RestoreSGTagKey,
+		ManualCleanupTagKey:        opts.ManualCleanupTagKey,
+		AttemptedCleanupTimeTagKey: opts.AttemptedCleanupTimeTagKey,
+		DeletionErrorTagKey:        opts.DeletionErrorTagKey,
+		OmitDeletionErrorTag:       opts.OmitDeletionErrorTag,
+		ReconcileTags:              opts.ReconcileTags,
+		ConfirmUnchanged:           opts.ConfirmUnchanged,
+		TagOnly:                    opts.TagOnly,
+		AuditRunID:                 opts.AuditRunID,
+		TagConcurrency:             opts.TagConcurrency,
+		DeleteConcurrency:          opts.DeleteConcurrency,
+		DeleteAttempts:             opts.DeleteAttempts,
+		DeleteRetryBaseDelay:       opts.DeleteRetryBaseDelay,
+		VerifyDeletion:             opts.VerifyDeletion,
+		EventBusName:               optionalString(opts.EventBusName),
+		PerENITimeout:              opts.PerENITimeout,
+		DetachTimeout:              opts.DetachTimeout,
+		RequireEmptyVPC:            opts.RequireEmptyVPC,
+		LockTableName:              optionalString(opts.LockTableName),
+		LockTTL:                    opts.LockTTL,
+		LockWaitTimeout:            opts.LockWaitTimeout,
+		DecisionLog:                opts.DecisionLog,
+		HandleAssociations:         opts.HandleAssociations,
+		RecordAPIPath:              optionalString(opts.RecordAPIPath),
+		ReplayAPIPath:              optionalString(opts.ReplayAPIPath),
+		DeletedENIs:                opts.deletedENIs,
+		RoleOptions:                opts.roleOptions(),
+		ConfigOptions:              opts.configOptions(),
+	}
+
+	if opts.MaxDeletions >= 0 {
+		maxDeletions := opts.MaxDeletions
+		options.MaxDeletions = &maxDeletions
+	}
+
+	if opts.Output == "ndjson" {
+		writer := report.NewNDJSONWriter(os.Stdout)
+		writer.RedactFields = opts.RedactFields
+		options.OnENIResult = func(result enicleanup.ENIResult) {
+			if err := writer.Write(result); err != nil {
+				log.Printf("Failed to write result for ENI %s: %v", result.ENI.ID, err)
+			}
+		}
+	}
+
+	return options
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// optionalString converts an empty flag value to nil
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
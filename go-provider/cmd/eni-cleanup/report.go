@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/securityhub"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/report"
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// writeReports renders the detected ENIs in the requested report formats.
+// Files are written with -redact-fields applied, except the import code,
+// which must match the ENIs; Security Hub gets the complete findings.
+func writeReports(ctx context.Context, opts cliOptions, enis []enicleanup.OrphanedENI) error {
+	redacted := report.RedactENIs(enis, opts.RedactFields)
+
+	if opts.DOTFile != "" {
+		if err := os.WriteFile(opts.DOTFile, report.FormatDOT(redacted), 0o644); err != nil {
+			return fmt.Errorf("failed to write DOT graph to %s: %w", opts.DOTFile, err)
+		}
+		log.Printf("Wrote DOT graph of %d ENIs to %s", len(enis), opts.DOTFile)
+	}
+
+	if opts.ImportFile != "" {
+		code, err := report.FormatImport(enis, opts.ImportLanguage)
+		if err != nil {
+			return fmt.Errorf("failed to render import code: %w", err)
+		}
+		if err := os.WriteFile(opts.ImportFile, code, 0o644); err != nil {
+			return fmt.Errorf("failed to write import code to %s: %w", opts.ImportFile, err)
+		}
+		log.Printf("Wrote import code for %d ENIs to %s", len(enis), opts.ImportFile)
+	}
+
+	estimate := report.EstimateCost(enis, opts.CostOptions)
+	if estimate.IdleElasticIPs > 0 {
+		log.Printf("Detected orphans hold %d idle Elastic IPs, an estimated $%.2f per month", estimate.IdleElasticIPs, estimate.EstimatedMonthlyWasteUSD)
+	}
+	if opts.CostFile != "" {
+		data, err := report.MarshalCostEstimate(estimate)
+		if err != nil {
+			return fmt.Errorf("failed to render cost estimate: %w", err)
+		}
+		if err := os.WriteFile(opts.CostFile, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write cost estimate to %s: %w", opts.CostFile, err)
+		}
+		log.Printf("Wrote cost estimate to %s", opts.CostFile)
+	}
+
+	if opts.ASFFFile != "" {
+		findings := report.FormatASFF(redacted, report.ASFFOptions{})
+		data, err := report.MarshalASFF(findings)
+		if err != nil {
+			return fmt.Errorf("failed to render ASFF findings: %w", err)
+		}
+		if err := os.WriteFile(opts.ASFFFile, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write ASFF findings to %s: %w", opts.ASFFFile, err)
+		}
+		log.Printf("Wrote %d ASFF findings to %s", len(findings), opts.ASFFFile)
+	}
+
+	if opts.SecurityHub {
+		findings := report.FormatASFF(enis, report.ASFFOptions{})
+
+		// Findings must be imported into Security Hub in the region of the resource
+		findingsByRegion := make(map[string][]report.ASFFFinding)
+		for _, finding := range findings {
+			region := finding.Resources[0].Region
+			findingsByRegion[region] = append(findingsByRegion[region], finding)
+		}
+
+		for region, regionFindings := range findingsByRegion {
+			cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+			if err != nil {
+				return fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+			}
+
+			imported, err := report.SubmitSecurityHubFindings(ctx, securityhub.NewFromConfig(cfg), regionFindings)
+			log.Printf("Imported %d findings into Security Hub in %s", imported, region)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeTimings writes the per-region timing breakdown to path, if set
+func writeTimings(path string, timings map[string]enicleanup.RegionTiming) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(timings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to render region timings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write region timings to %s: %w", path, err)
+	}
+	log.Printf("Wrote timings of %d regions to %s", len(timings), path)
+	return nil
+}
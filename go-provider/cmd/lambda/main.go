@@ -0,0 +1,193 @@
+// Command lambda runs ENI detection and cleanup as an AWS Lambda function,
+// e.g. on an EventBridge schedule.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// Event configures a single invocation. Unset fields fall back to the
+// ENI_CLEANUP_* environment variables, so a scheduled rule can send an empty
+// or unrelated event. EventBridge event fields are ignored.
+type Event struct {
+	Regions                []string `json:"regions,omitempty"`
+	DryRun                 *bool    `json:"dryRun,omitempty"`
+	DetectOnly             *bool    `json:"detectOnly,omitempty"`
+	DisassociateOnly       *bool    `json:"disassociateOnly,omitempty"`
+	SecurityGroupId        string   `json:"securityGroupId,omitempty"`
+	DefaultSecurityGroupId string   `json:"defaultSecurityGroupId,omitempty"`
+	IncludeTagKeys         []string `json:"includeTagKeys,omitempty"`
+	ExcludeTagKeys         []string `json:"excludeTagKeys,omitempty"`
+	ExcludeCidrs           []string `json:"excludeCidrs,omitempty"`
+	ExpectedAccountId      string   `json:"expectedAccountId,omitempty"`
+	EventBusName           string   `json:"eventBusName,omitempty"`
+	LockTableName          string   `json:"lockTableName,omitempty"`
+	DecisionLog            *bool    `json:"decisionLog,omitempty"`
+}
+
+// Response is the JSON result of an invocation
+type Response struct {
+	Detected       int                     `json:"detected"`
+	SuccessCount   int                     `json:"successCount"`
+	FailureCount   int                     `json:"failureCount"`
+	SkippedCount   int                     `json:"skippedCount"`
+	CleanedENIs    []enicleanup.CleanedENI `json:"cleanedEnis"`
+	SkippedDetails []enicleanup.SkippedENI `json:"skippedDetails"`
+	DeadLetter     []enicleanup.CleanedENI `json:"deadLetter,omitempty"`
+	Errors         []string                `json:"errors"`
+	// ResultCode summarises the outcome as one of the enicleanup.ResultCode* values
+	ResultCode int `json:"resultCode"`
+}
+
+// deletedENIs is shared by invocations in the same warm execution
+// environment, so a stale describe doesn't reprocess ENIs already deleted
+var deletedENIs = enicleanup.NewDeletedENIs()
+
+func main() {
+	enicleanup.DecisionLogf = log.Printf
+	lambda.Start(HandleRequest)
+}
+
+// HandleRequest detects orphaned ENIs in the configured regions and, unless
+// detectOnly or dryRun is set, cleans them up
+func HandleRequest(ctx context.Context, event Event) (Response, error) {
+	event = event.withDefaults()
+	if len(event.Regions) == 0 {
+		return Response{}, fmt.Errorf("no regions configured; set regions in the event or ENI_CLEANUP_REGIONS")
+	}
+
+	if err := enicleanup.Preflight(ctx, enicleanup.PreflightOptions{
+		ExpectedAccountId: optionalString(event.ExpectedAccountId),
+	}); err != nil {
+		return Response{}, err
+	}
+
+	detected, err := enicleanup.DetectOrphanedENIsWithDetails(ctx, event.Regions, enicleanup.DetectOptions{
+		IncludeTagKeys:    event.IncludeTagKeys,
+		ExcludeTagKeys:    event.ExcludeTagKeys,
+		ExcludeCIDRs:      event.ExcludeCidrs,
+		SecurityGroupId:   optionalString(event.SecurityGroupId),
+		DetachGracePeriod: enicleanup.DefaultDetachGracePeriod,
+		DecisionLog:       *event.DecisionLog,
+		DeletedENIs:       deletedENIs,
+	})
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+	}
+	log.Printf("Detected %d orphaned ENIs in %v, spared %d", len(detected.ENIs), event.Regions, len(detected.Skipped))
+
+	response := Response{
+		Detected:       len(detected.ENIs),
+		CleanedENIs:    []enicleanup.CleanedENI{},
+		SkippedDetails: detected.Skipped,
+		Errors:         []string{},
+	}
+	if *event.DetectOnly {
+		return response, nil
+	}
+
+	result := enicleanup.CleanupOrphanedENIsWithOptions(ctx, detected.ENIs, enicleanup.CleanupOptions{
+		DryRun:                 *event.DryRun,
+		DisassociateOnly:       *event.DisassociateOnly,
+		TargetSecurityGroupId:  optionalString(event.SecurityGroupId),
+		DefaultSecurityGroupId: optionalString(event.DefaultSecurityGroupId),
+		EventBusName:           optionalString(event.EventBusName),
+		LockTableName:          optionalString(event.LockTableName),
+		DecisionLog:            *event.DecisionLog,
+		DeletedENIs:            deletedENIs,
+	})
+	log.Printf("ENI cleanup completed: %d succeeded, %d failed, %d skipped", result.SuccessCount, result.FailureCount, result.SkippedCount)
+
+	response.SuccessCount = result.SuccessCount
+	response.FailureCount = result.FailureCount
+	response.SkippedCount = result.SkippedCount
+	response.CleanedENIs = append(response.CleanedENIs, result.CleanedENIs...)
+	response.Errors = append(response.Errors, result.Errors...)
+	response.DeadLetter = result.DeadLetter
+	response.ResultCode = enicleanup.ResultCode(result)
+	for _, eni := range result.DeadLetter {
+		log.Printf("ESCALATE: ENI %s in %s failed cleanup and could not be tagged: %v", eni.ID, eni.Region, eni.Errors)
+	}
+	return response, nil
+}
+
+// withDefaults fills unset event fields from the environment
+func (e Event) withDefaults() Event {
+	if len(e.Regions) == 0 {
+		e.Regions = splitList(os.Getenv("ENI_CLEANUP_REGIONS"))
+	}
+	if e.DryRun == nil {
+		e.DryRun = envBool("ENI_CLEANUP_DRY_RUN")
+	}
+	if e.DetectOnly == nil {
+		e.DetectOnly = envBool("ENI_CLEANUP_DETECT_ONLY")
+	}
+	if e.DisassociateOnly == nil {
+		e.DisassociateOnly = envBool("ENI_CLEANUP_DISASSOCIATE_ONLY")
+	}
+	if e.SecurityGroupId == "" {
+		e.SecurityGroupId = os.Getenv("ENI_CLEANUP_SECURITY_GROUP_ID")
+	}
+	if e.DefaultSecurityGroupId == "" {
+		e.DefaultSecurityGroupId = os.Getenv("ENI_CLEANUP_DEFAULT_SECURITY_GROUP_ID")
+	}
+	if len(e.IncludeTagKeys) == 0 {
+		e.IncludeTagKeys = splitList(os.Getenv("ENI_CLEANUP_INCLUDE_TAG_KEYS"))
+	}
+	if len(e.ExcludeTagKeys) == 0 {
+		e.ExcludeTagKeys = splitList(os.Getenv("ENI_CLEANUP_EXCLUDE_TAG_KEYS"))
+	}
+	if len(e.ExcludeCidrs) == 0 {
+		e.ExcludeCidrs = splitList(os.Getenv("ENI_CLEANUP_EXCLUDE_CIDRS"))
+	}
+	if e.ExpectedAccountId == "" {
+		e.ExpectedAccountId = os.Getenv("ENI_CLEANUP_EXPECTED_ACCOUNT_ID")
+	}
+	if e.EventBusName == "" {
+		e.EventBusName = os.Getenv("ENI_CLEANUP_EVENT_BUS_NAME")
+	}
+	if e.DecisionLog == nil {
+		e.DecisionLog = envBool("ENI_CLEANUP_DECISION_LOG")
+	}
+	if e.LockTableName == "" {
+		e.LockTableName = os.Getenv("ENI_CLEANUP_LOCK_TABLE_NAME")
+	}
+	return e
+}
+
+// envBool reads a boolean environment variable, treating unset or invalid values as false
+func envBool(name string) *bool {
+	value, err := strconv.ParseBool(os.Getenv(name))
+	if err != nil {
+		value = false
+	}
+	return &value
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -11,6 +11,11 @@ func NewProvider() provider.Provider {
 	return infer.Provider(infer.Options{
 		Resources: []infer.InferredResource{
 			infer.Resource[enicleanup.Resource, enicleanup.ResourceArgs, enicleanup.ResourceState](),
+			infer.Resource[enicleanup.OnDestroyResource, enicleanup.ResourceArgs, enicleanup.OnDestroyState](),
+		},
+		Functions: []infer.InferredFunction{
+			infer.Function[enicleanup.OrphanCountsByVPC, enicleanup.OrphanCountsArgs, enicleanup.OrphanCounts](),
+			infer.Function[enicleanup.GetOrphanedENIs, enicleanup.GetOrphanedENIsArgs, enicleanup.GetOrphanedENIsResult](),
 		},
 	})
 }
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// ASFFSchemaVersion is the AWS Security Finding Format schema version emitted
+const ASFFSchemaVersion = "2018-10-08"
+
+// ASFFGeneratorID identifies this tool as the source of the findings
+const ASFFGeneratorID = "aws-eni-cleanup/orphaned-eni"
+
+// ASFFFinding is an AWS Security Finding Format finding for an orphaned ENI.
+// It contains the subset of ASFF fields required by BatchImportFindings.
+type ASFFFinding struct {
+	SchemaVersion string            `json:"SchemaVersion"`
+	ID            string            `json:"Id"`
+	ProductArn    string            `json:"ProductArn"`
+	GeneratorID   string            `json:"GeneratorId"`
+	AwsAccountID  string            `json:"AwsAccountId"`
+	Types         []string          `json:"Types"`
+	CreatedAt     string            `json:"CreatedAt"`
+	UpdatedAt     string            `json:"UpdatedAt"`
+	Severity      ASFFSeverity      `json:"Severity"`
+	Title         string            `json:"Title"`
+	Description   string            `json:"Description"`
+	Remediation   ASFFRemediation   `json:"Remediation"`
+	Resources     []ASFFResource    `json:"Resources"`
+	RecordState   string            `json:"RecordState"`
+	Workflow      ASFFWorkflow      `json:"Workflow"`
+	ProductFields map[string]string `json:"ProductFields,omitempty"`
+}
+
+// ASFFSeverity is the severity of a finding
+type ASFFSeverity struct {
+	Label string `json:"Label"`
+}
+
+// ASFFRemediation holds the remediation guidance of a finding
+type ASFFRemediation struct {
+	Recommendation ASFFRecommendation `json:"Recommendation"`
+}
+
+// ASFFRecommendation is the recommended remediation text and reference
+type ASFFRecommendation struct {
+	Text string `json:"Text"`
+	URL  string `json:"Url,omitempty"`
+}
+
+// ASFFResource is the AWS resource a finding applies to
+type ASFFResource struct {
+	Type      string            `json:"Type"`
+	ID        string            `json:"Id"`
+	Partition string            `json:"Partition"`
+	Region    string            `json:"Region"`
+	Tags      map[string]string `json:"Tags,omitempty"`
+}
+
+// ASFFWorkflow is the workflow state of a finding
+type ASFFWorkflow struct {
+	Status string `json:"Status"`
+}
+
+// ASFFOptions controls how findings are rendered
+type ASFFOptions struct {
+	// AccountID is used for ENIs whose owner account is unknown and for the
+	// product ARN when set
+	AccountID string
+	// Severity label for the findings; defaults to LOW
+	Severity string
+	// Now overrides the finding timestamps, for deterministic output
+	Now time.Time
+}
+
+// FormatASFF renders detected orphaned ENIs as ASFF findings suitable for
+// Security Hub's BatchImportFindings
+func FormatASFF(enis []enicleanup.OrphanedENI, options ASFFOptions) []ASFFFinding {
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	timestamp := now.UTC().Format(time.RFC3339)
+
+	severity := options.Severity
+	if severity == "" {
+		severity = "LOW"
+	}
+
+	findings := make([]ASFFFinding, 0, len(enis))
+	for _, eni := range enis {
+		accountID := eni.OwnerID
+		if accountID == "" {
+			accountID = options.AccountID
+		}
+		partition := Partition(eni.Region)
+		eniArn := NetworkInterfaceARN(partition, eni.Region, accountID, eni.ID)
+
+		findings = append(findings, ASFFFinding{
+			SchemaVersion: ASFFSchemaVersion,
+			ID:            fmt.Sprintf("%s/%s", eni.Region, eni.ID),
+			ProductArn:    fmt.Sprintf("arn:%s:securityhub:%s:%s:product/%s/default", partition, eni.Region, accountID, accountID),
+			GeneratorID:   ASFFGeneratorID,
+			AwsAccountID:  accountID,
+			Types:         []string{"Software and Configuration Checks/AWS Security Best Practices"},
+			CreatedAt:     timestamp,
+			UpdatedAt:     timestamp,
+			Severity:      ASFFSeverity{Label: severity},
+			Title:         fmt.Sprintf("Orphaned network interface %s", eni.ID),
+			Description:   describeENI(eni),
+			Remediation: ASFFRemediation{
+				Recommendation: ASFFRecommendation{
+					Text: "Verify the network interface is no longer used, then detach and delete it, or remove it from its security groups so dependent resources can be destroyed.",
+					URL:  "https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-eni.html#delete_eni",
+				},
+			},
+			Resources: []ASFFResource{
+				{
+					Type:      "AwsEc2NetworkInterface",
+					ID:        eniArn,
+					Partition: partition,
+					Region:    eni.Region,
+					Tags:      eni.Tags,
+				},
+			},
+			RecordState: "ACTIVE",
+			Workflow:    ASFFWorkflow{Status: "NEW"},
+			ProductFields: map[string]string{
+				"VpcId":    eni.VPCID,
+				"SubnetId": eni.SubnetID,
+			},
+		})
+		if eni.Reason != "" {
+			findings[len(findings)-1].ProductFields["Reason"] = eni.Reason
+		}
+	}
+
+	return findings
+}
+
+// MarshalASFF renders findings as an indented JSON array
+func MarshalASFF(findings []ASFFFinding) ([]byte, error) {
+	return json.MarshalIndent(findings, "", "  ")
+}
+
+// Partition returns the AWS partition for a region
+func Partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+// NetworkInterfaceARN builds the ARN of a network interface
+func NetworkInterfaceARN(partition, region, accountID, eniID string) string {
+	return fmt.Sprintf("arn:%s:ec2:%s:%s:network-interface/%s", partition, region, accountID, eniID)
+}
+
+// describeENI summarizes where an orphaned ENI lives for the finding description
+func describeENI(eni enicleanup.OrphanedENI) string {
+	description := fmt.Sprintf("Network interface %s in VPC %s (subnet %s) appears to be orphaned.", eni.ID, eni.VPCID, eni.SubnetID)
+	if eni.Description != "" {
+		description += fmt.Sprintf(" Description: %q.", eni.Description)
+	}
+	if len(eni.SecurityGroups) > 0 {
+		description += fmt.Sprintf(" Security groups: %s.", strings.Join(eni.SecurityGroups, ", "))
+	}
+	// ASFF limits descriptions to 1024 characters
+	if len(description) > 1024 {
+		description = description[:1024]
+	}
+	return description
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestFormatASFF(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	enis := []enicleanup.OrphanedENI{
+		{ID: "eni-123", Region: "us-east-1", VPCID: "vpc-1", SubnetID: "subnet-1", OwnerID: "111111111111"},
+		{ID: "eni-456", Region: "cn-north-1", VPCID: "vpc-2", SubnetID: "subnet-2"},
+	}
+
+	findings := FormatASFF(enis, ASFFOptions{AccountID: "222222222222", Now: now})
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	first := findings[0]
+	if first.AwsAccountID != "111111111111" {
+		t.Errorf("expected ENI owner account, got %s", first.AwsAccountID)
+	}
+	if got, want := first.Resources[0].ID, "arn:aws:ec2:us-east-1:111111111111:network-interface/eni-123"; got != want {
+		t.Errorf("expected resource ARN %s, got %s", want, got)
+	}
+	if first.CreatedAt != "2024-05-01T12:00:00Z" {
+		t.Errorf("unexpected CreatedAt %s", first.CreatedAt)
+	}
+	if first.Severity.Label != "LOW" {
+		t.Errorf("expected default severity LOW, got %s", first.Severity.Label)
+	}
+	if first.Remediation.Recommendation.Text == "" {
+		t.Error("expected remediation text")
+	}
+
+	second := findings[1]
+	if second.AwsAccountID != "222222222222" {
+		t.Errorf("expected fallback account, got %s", second.AwsAccountID)
+	}
+	if second.Resources[0].Partition != "aws-cn" {
+		t.Errorf("expected aws-cn partition, got %s", second.Resources[0].Partition)
+	}
+
+	data, err := MarshalASFF(findings)
+	if err != nil {
+		t.Fatalf("failed to marshal findings: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode findings: %v", err)
+	}
+	for _, field := range []string{"SchemaVersion", "Id", "ProductArn", "GeneratorId", "AwsAccountId", "Resources"} {
+		if _, ok := decoded[0][field]; !ok {
+			t.Errorf("expected ASFF field %s in output", field)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// DefaultEIPMonthlyRateUSD is the monthly charge for an idle Elastic IP in
+// the commercial partition: $0.005 per hour for 730 hours
+const DefaultEIPMonthlyRateUSD = 3.65
+
+// CostOptions configures the waste estimate
+type CostOptions struct {
+	// EIPMonthlyRateUSD is the monthly charge per idle Elastic IP; defaults to
+	// DefaultEIPMonthlyRateUSD
+	EIPMonthlyRateUSD float64
+	// EIPMonthlyRateUSDByRegion overrides the rate for specific regions, e.g.
+	// GovCloud or China regions
+	EIPMonthlyRateUSDByRegion map[string]float64
+}
+
+// CostEstimate is the estimated monthly cost of leaving orphaned ENIs in place
+type CostEstimate struct {
+	// IdleElasticIPs is the number of Elastic IPs held by detached orphans
+	IdleElasticIPs int `json:"idleElasticIps"`
+	// EstimatedMonthlyWasteUSD is the total monthly charge for those Elastic IPs
+	EstimatedMonthlyWasteUSD float64 `json:"estimatedMonthlyWasteUsd"`
+	// ByRegion is the monthly charge in each region with idle Elastic IPs
+	ByRegion map[string]float64 `json:"byRegion"`
+}
+
+// EstimateCost estimates the monthly charge for the Elastic IPs held by
+// orphaned ENIs that aren't attached to anything. Each Elastic IP is counted
+// once, even if it appears on more than one ENI.
+func EstimateCost(enis []enicleanup.OrphanedENI, options CostOptions) CostEstimate {
+	estimate := CostEstimate{ByRegion: map[string]float64{}}
+
+	seen := make(map[string]bool)
+	for _, eni := range enis {
+		if eni.AttachmentState != "" && eni.AttachmentState != "detached" {
+			continue
+		}
+
+		for _, allocationID := range eni.ElasticIPAllocationIDs {
+			if seen[allocationID] {
+				continue
+			}
+			seen[allocationID] = true
+
+			rate := options.rate(eni.Region)
+			estimate.IdleElasticIPs++
+			estimate.ByRegion[eni.Region] = roundCents(estimate.ByRegion[eni.Region] + rate)
+			estimate.EstimatedMonthlyWasteUSD = roundCents(estimate.EstimatedMonthlyWasteUSD + rate)
+		}
+	}
+
+	return estimate
+}
+
+// MarshalCostEstimate renders an estimate as indented JSON with a trailing newline
+func MarshalCostEstimate(estimate CostEstimate) ([]byte, error) {
+	data, err := json.MarshalIndent(estimate, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// rate returns the monthly Elastic IP rate for a region
+func (o CostOptions) rate(region string) float64 {
+	if rate, ok := o.EIPMonthlyRateUSDByRegion[region]; ok {
+		return rate
+	}
+	if o.EIPMonthlyRateUSD > 0 {
+		return o.EIPMonthlyRateUSD
+	}
+	return DefaultEIPMonthlyRateUSD
+}
+
+// roundCents rounds a dollar amount to whole cents
+func roundCents(usd float64) float64 {
+	return math.Round(usd*100) / 100
+}
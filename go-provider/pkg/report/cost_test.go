@@ -0,0 +1,33 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestEstimateCost(t *testing.T) {
+	enis := []enicleanup.OrphanedENI{
+		{ID: "eni-1", Region: "us-east-1", ElasticIPAllocationIDs: []string{"eipalloc-1", "eipalloc-2"}},
+		{ID: "eni-2", Region: "us-gov-west-1", AttachmentState: "detached", ElasticIPAllocationIDs: []string{"eipalloc-3"}},
+		// Elastic IPs of attached ENIs aren't idle
+		{ID: "eni-3", Region: "us-east-1", AttachmentState: "attached", ElasticIPAllocationIDs: []string{"eipalloc-4"}},
+		// Each Elastic IP is only charged once
+		{ID: "eni-4", Region: "us-east-1", ElasticIPAllocationIDs: []string{"eipalloc-1"}},
+		{ID: "eni-5", Region: "us-east-1"},
+	}
+
+	estimate := EstimateCost(enis, CostOptions{
+		EIPMonthlyRateUSDByRegion: map[string]float64{"us-gov-west-1": 4.38},
+	})
+
+	if estimate.IdleElasticIPs != 3 {
+		t.Errorf("expected 3 idle Elastic IPs, got %d", estimate.IdleElasticIPs)
+	}
+	if estimate.EstimatedMonthlyWasteUSD != 11.68 {
+		t.Errorf("expected $11.68 per month, got %v", estimate.EstimatedMonthlyWasteUSD)
+	}
+	if estimate.ByRegion["us-east-1"] != 7.3 || estimate.ByRegion["us-gov-west-1"] != 4.38 {
+		t.Errorf("unexpected regional estimate %v", estimate.ByRegion)
+	}
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// FormatDOT renders detected orphaned ENIs as a Graphviz DOT graph linking each
+// ENI to its VPC, subnet, security groups and, where known, owning resource.
+// Output is sorted so the same ENIs always render the same graph.
+func FormatDOT(enis []enicleanup.OrphanedENI) []byte {
+	nodes := make(map[string]string)
+	edges := make(map[string]struct{})
+
+	node := func(id, attrs string) {
+		nodes[dotQuote(id)] = attrs
+	}
+	edge := func(from, to string) {
+		edges[dotQuote(from)+" -> "+dotQuote(to)] = struct{}{}
+	}
+
+	for _, eni := range enis {
+		label := eni.ID
+		if eni.Description != "" {
+			label += "\n" + eni.Description
+		}
+		node(eni.ID, fmt.Sprintf("shape=box, style=filled, fillcolor=lightsalmon, label=%s", dotQuote(label)))
+
+		// VPCs and subnets are scoped by region since IDs are only unique per region
+		vpc := eni.Region + "/" + eni.VPCID
+		if eni.VPCID != "" {
+			node(vpc, fmt.Sprintf("shape=folder, label=%s", dotQuote(eni.VPCID+"\n"+eni.Region)))
+		}
+		if eni.SubnetID != "" {
+			subnet := eni.Region + "/" + eni.SubnetID
+			node(subnet, fmt.Sprintf("shape=folder, label=%s", dotQuote(eni.SubnetID+"\n"+eni.AvailabilityZone)))
+			if eni.VPCID != "" {
+				edge(vpc, subnet)
+			}
+			edge(subnet, eni.ID)
+		} else if eni.VPCID != "" {
+			edge(vpc, eni.ID)
+		}
+
+		for _, group := range eni.SecurityGroups {
+			sg := eni.Region + "/" + group
+			node(sg, fmt.Sprintf("shape=ellipse, label=%s", dotQuote(group)))
+			edge(eni.ID, sg)
+		}
+
+		if eni.LoadBalancerARN != "" {
+			node(eni.LoadBalancerARN, fmt.Sprintf("shape=component, style=dashed, label=%s", dotQuote(loadBalancerName(eni.LoadBalancerARN)+"\n(deleted)")))
+			edge(eni.LoadBalancerARN, eni.ID)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph orphaned_enis {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	for _, id := range sortedKeys(nodes) {
+		fmt.Fprintf(&buf, "  %s [%s];\n", id, nodes[id])
+	}
+	for _, e := range sortedKeys(edges) {
+		fmt.Fprintf(&buf, "  %s;\n", e)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// dotQuote quotes a string as a DOT ID, escaping quotes and line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// loadBalancerName extracts the "app/name/id" part of a load balancer ARN
+func loadBalancerName(arn string) string {
+	if _, name, ok := strings.Cut(arn, ":loadbalancer/"); ok {
+		return name
+	}
+	return arn
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestFormatDOT(t *testing.T) {
+	enis := []enicleanup.OrphanedENI{
+		{
+			ID:              "eni-1",
+			Region:          "us-east-1",
+			VPCID:           "vpc-1",
+			SubnetID:        "subnet-1",
+			Description:     `ELB "app/web"`,
+			SecurityGroups:  []string{"sg-1", "sg-2"},
+			LoadBalancerARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/abc",
+		},
+		{ID: "eni-2", Region: "us-east-1", VPCID: "vpc-1", SubnetID: "subnet-1", SecurityGroups: []string{"sg-1"}},
+	}
+
+	graph := string(FormatDOT(enis))
+
+	for _, want := range []string{
+		`"us-east-1/vpc-1" -> "us-east-1/subnet-1";`,
+		`"us-east-1/subnet-1" -> "eni-1";`,
+		`"eni-2" -> "us-east-1/sg-1";`,
+		`"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/abc" -> "eni-1";`,
+		`label="eni-1\nELB \"app/web\""`,
+	} {
+		if !strings.Contains(graph, want) {
+			t.Errorf("expected graph to contain %s, got:\n%s", want, graph)
+		}
+	}
+
+	// Shared nodes and edges appear once
+	if n := strings.Count(graph, `"us-east-1/vpc-1" -> "us-east-1/subnet-1";`); n != 1 {
+		t.Errorf("expected the VPC to subnet edge once, got %d", n)
+	}
+
+	reversed := []enicleanup.OrphanedENI{enis[1], enis[0]}
+	if string(FormatDOT(reversed)) != graph {
+		t.Error("graph depends on detection order")
+	}
+}
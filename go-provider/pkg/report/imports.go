@@ -0,0 +1,211 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// Languages FormatImport can generate
+const (
+	ImportLanguageGo         = "go"
+	ImportLanguageTypeScript = "typescript"
+)
+
+// importResourceType is the Pulumi type token of an ENI
+const importResourceType = "aws:ec2/networkInterface:NetworkInterface"
+
+// importResource is the resource definition generated for one ENI
+type importResource struct {
+	name           string
+	id             string
+	region         string
+	subnetID       string
+	description    string
+	securityGroups []string
+	privateIPs     []string
+	tags           [][2]string
+}
+
+// FormatImport renders Pulumi code that brings detected orphaned ENIs under
+// management instead of deleting them. Each ENI becomes an
+// aws.ec2.NetworkInterface with the import resource option, using an AWS
+// provider per region, so the next `pulumi up` adopts them all. A leading
+// comment lists the equivalent `pulumi import` commands. language is
+// ImportLanguageGo or ImportLanguageTypeScript.
+func FormatImport(enis []enicleanup.OrphanedENI, language string) ([]byte, error) {
+	resources := make([]importResource, 0, len(enis))
+	for _, eni := range enis {
+		resources = append(resources, newImportResource(eni))
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].region != resources[j].region {
+			return resources[i].region < resources[j].region
+		}
+		return resources[i].id < resources[j].id
+	})
+
+	switch language {
+	case ImportLanguageGo:
+		return formatImportGo(resources)
+	case ImportLanguageTypeScript:
+		return formatImportTypeScript(resources), nil
+	default:
+		return nil, fmt.Errorf("unsupported import language %q, expected %s or %s", language, ImportLanguageGo, ImportLanguageTypeScript)
+	}
+}
+
+// newImportResource builds the definition matching an ENI's current
+// configuration, so importing it doesn't produce a diff. Tags under the
+// reserved aws: prefix can't be managed and are left out.
+func newImportResource(eni enicleanup.OrphanedENI) importResource {
+	resource := importResource{
+		name:           eni.ID,
+		id:             eni.ID,
+		region:         eni.Region,
+		subnetID:       eni.SubnetID,
+		description:    eni.Description,
+		securityGroups: slices.Sorted(slices.Values(eni.SecurityGroups)),
+	}
+	if eni.PrivateIPAddress != "" {
+		resource.privateIPs = []string{eni.PrivateIPAddress}
+	}
+	for _, key := range sortedKeys(eni.Tags) {
+		if !strings.HasPrefix(key, "aws:") {
+			resource.tags = append(resource.tags, [2]string{key, eni.Tags[key]})
+		}
+	}
+	return resource
+}
+
+// importRegions returns the distinct regions of the resources in order
+func importRegions(resources []importResource) []string {
+	var regions []string
+	for _, resource := range resources {
+		if len(regions) == 0 || regions[len(regions)-1] != resource.region {
+			regions = append(regions, resource.region)
+		}
+	}
+	return regions
+}
+
+// providerVariable names the provider variable of a region, e.g. useast1
+func providerVariable(region string) string {
+	return strings.ReplaceAll(region, "-", "")
+}
+
+// writeImportCommands writes the `pulumi import` commands as comment lines
+func writeImportCommands(buf *bytes.Buffer, resources []importResource) {
+	fmt.Fprintf(buf, "// Generated by eni-cleanup to manage %d orphaned ENIs with Pulumi.\n", len(resources))
+	buf.WriteString("// Review the definitions before running `pulumi up`, which imports each ENI.\n")
+	buf.WriteString("// Alternatively, import them with the Pulumi CLI:\n//\n")
+	for _, resource := range resources {
+		fmt.Fprintf(buf, "//\tAWS_REGION=%s pulumi import %s %s %s\n", resource.region, importResourceType, resource.name, resource.id)
+	}
+	buf.WriteString("\n")
+}
+
+// formatImportGo renders the resources as a gofmt'd Go function
+func formatImportGo(resources []importResource) ([]byte, error) {
+	var buf bytes.Buffer
+	writeImportCommands(&buf, resources)
+	buf.WriteString("package main\n\n")
+	buf.WriteString("import (\n")
+	buf.WriteString("\t\"github.com/pulumi/pulumi-aws/sdk/v6/go/aws\"\n")
+	buf.WriteString("\t\"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ec2\"\n")
+	buf.WriteString("\t\"github.com/pulumi/pulumi/sdk/v3/go/pulumi\"\n")
+	buf.WriteString(")\n\n")
+	buf.WriteString("// importOrphanedENIs adopts the orphaned ENIs found by eni-cleanup\n")
+	buf.WriteString("func importOrphanedENIs(ctx *pulumi.Context) error {\n")
+
+	for _, region := range importRegions(resources) {
+		fmt.Fprintf(&buf, "%s, err := aws.NewProvider(ctx, %s, &aws.ProviderArgs{Region: pulumi.String(%s)})\n",
+			providerVariable(region), strconv.Quote("aws-"+region), strconv.Quote(region))
+		buf.WriteString("if err != nil {\nreturn err\n}\n")
+	}
+
+	for _, resource := range resources {
+		fmt.Fprintf(&buf, "\nif _, err := ec2.NewNetworkInterface(ctx, %s, &ec2.NetworkInterfaceArgs{\n", strconv.Quote(resource.name))
+		fmt.Fprintf(&buf, "SubnetId: pulumi.String(%s),\n", strconv.Quote(resource.subnetID))
+		if resource.description != "" {
+			fmt.Fprintf(&buf, "Description: pulumi.String(%s),\n", strconv.Quote(resource.description))
+		}
+		if len(resource.securityGroups) > 0 {
+			fmt.Fprintf(&buf, "SecurityGroups: pulumi.StringArray{%s},\n", goStrings(resource.securityGroups))
+		}
+		if len(resource.privateIPs) > 0 {
+			fmt.Fprintf(&buf, "PrivateIps: pulumi.StringArray{%s},\n", goStrings(resource.privateIPs))
+		}
+		if len(resource.tags) > 0 {
+			buf.WriteString("Tags: pulumi.StringMap{\n")
+			for _, tag := range resource.tags {
+				fmt.Fprintf(&buf, "%s: pulumi.String(%s),\n", strconv.Quote(tag[0]), strconv.Quote(tag[1]))
+			}
+			buf.WriteString("},\n")
+		}
+		fmt.Fprintf(&buf, "}, pulumi.Provider(%s), pulumi.Import(pulumi.ID(%s))); err != nil {\nreturn err\n}\n",
+			providerVariable(resource.region), strconv.Quote(resource.id))
+	}
+
+	buf.WriteString("\nreturn nil\n}\n")
+	return format.Source(buf.Bytes())
+}
+
+// goStrings renders values as pulumi.String elements
+func goStrings(values []string) string {
+	elements := make([]string, len(values))
+	for i, value := range values {
+		elements[i] = "pulumi.String(" + strconv.Quote(value) + ")"
+	}
+	return strings.Join(elements, ", ")
+}
+
+// formatImportTypeScript renders the resources as a TypeScript module
+func formatImportTypeScript(resources []importResource) []byte {
+	var buf bytes.Buffer
+	writeImportCommands(&buf, resources)
+	buf.WriteString("import * as aws from \"@pulumi/aws\";\n\n")
+
+	for _, region := range importRegions(resources) {
+		fmt.Fprintf(&buf, "const %s = new aws.Provider(%s, { region: %s });\n",
+			providerVariable(region), strconv.Quote("aws-"+region), strconv.Quote(region))
+	}
+
+	for _, resource := range resources {
+		fmt.Fprintf(&buf, "\nnew aws.ec2.NetworkInterface(%s, {\n", strconv.Quote(resource.name))
+		fmt.Fprintf(&buf, "    subnetId: %s,\n", strconv.Quote(resource.subnetID))
+		if resource.description != "" {
+			fmt.Fprintf(&buf, "    description: %s,\n", strconv.Quote(resource.description))
+		}
+		if len(resource.securityGroups) > 0 {
+			fmt.Fprintf(&buf, "    securityGroups: [%s],\n", tsStrings(resource.securityGroups))
+		}
+		if len(resource.privateIPs) > 0 {
+			fmt.Fprintf(&buf, "    privateIps: [%s],\n", tsStrings(resource.privateIPs))
+		}
+		if len(resource.tags) > 0 {
+			buf.WriteString("    tags: {\n")
+			for _, tag := range resource.tags {
+				fmt.Fprintf(&buf, "        %s: %s,\n", strconv.Quote(tag[0]), strconv.Quote(tag[1]))
+			}
+			buf.WriteString("    },\n")
+		}
+		fmt.Fprintf(&buf, "}, { provider: %s, import: %s });\n", providerVariable(resource.region), strconv.Quote(resource.id))
+	}
+	return buf.Bytes()
+}
+
+// tsStrings renders values as TypeScript string literals
+func tsStrings(values []string) string {
+	elements := make([]string, len(values))
+	for i, value := range values {
+		elements[i] = strconv.Quote(value)
+	}
+	return strings.Join(elements, ", ")
+}
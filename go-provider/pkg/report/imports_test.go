@@ -0,0 +1,77 @@
+package report
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestFormatImport(t *testing.T) {
+	enis := []enicleanup.OrphanedENI{
+		{
+			ID:               "eni-2",
+			Region:           "us-west-2",
+			SubnetID:         "subnet-2",
+			PrivateIPAddress: "10.1.0.5",
+		},
+		{
+			ID:               "eni-1",
+			Region:           "us-east-1",
+			SubnetID:         "subnet-1",
+			Description:      `ELB "app/web"`,
+			SecurityGroups:   []string{"sg-2", "sg-1"},
+			PrivateIPAddress: "10.0.0.5",
+			Tags:             map[string]string{"team": "web", "aws:cloudformation:stack-name": "web"},
+		},
+	}
+
+	code, err := FormatImport(enis, ImportLanguageGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(code)
+	if _, err := parser.ParseFile(token.NewFileSet(), "import.go", code, 0); err != nil {
+		t.Fatalf("expected valid Go, got %v:\n%s", err, generated)
+	}
+	for _, want := range []string{
+		"//\tAWS_REGION=us-east-1 pulumi import aws:ec2/networkInterface:NetworkInterface eni-1 eni-1",
+		`useast1, err := aws.NewProvider(ctx, "aws-us-east-1", &aws.ProviderArgs{Region: pulumi.String("us-east-1")})`,
+		`Description:    pulumi.String("ELB \"app/web\""),`,
+		`SecurityGroups: pulumi.StringArray{pulumi.String("sg-1"), pulumi.String("sg-2")},`,
+		`"team": pulumi.String("web"),`,
+		`pulumi.Provider(uswest2), pulumi.Import(pulumi.ID("eni-2"))`,
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("expected Go code to contain %s, got:\n%s", want, generated)
+		}
+	}
+	if strings.Contains(generated, "aws:cloudformation") {
+		t.Errorf("expected reserved aws: tags to be left out, got:\n%s", generated)
+	}
+	if strings.Index(generated, `"eni-1"`) > strings.Index(generated, `"eni-2"`) {
+		t.Errorf("expected resources ordered by region, got:\n%s", generated)
+	}
+
+	code, err = FormatImport(enis, ImportLanguageTypeScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated = string(code)
+	for _, want := range []string{
+		`const useast1 = new aws.Provider("aws-us-east-1", { region: "us-east-1" });`,
+		`securityGroups: ["sg-1", "sg-2"],`,
+		`privateIps: ["10.1.0.5"],`,
+		`}, { provider: uswest2, import: "eni-2" });`,
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("expected TypeScript code to contain %s, got:\n%s", want, generated)
+		}
+	}
+
+	if _, err := FormatImport(enis, "python"); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+}
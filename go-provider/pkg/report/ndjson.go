@@ -0,0 +1,59 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// NDJSONRecord is a single line of newline-delimited JSON output
+type NDJSONRecord struct {
+	Time        string `json:"time"`
+	ID          string `json:"id"`
+	Region      string `json:"region"`
+	VpcID       string `json:"vpcId"`
+	Description string `json:"description,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Outcome     string `json:"outcome"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NDJSONWriter streams cleanup results as newline-delimited JSON, one object
+// per ENI, so progress can be followed with tools like jq. It is safe for
+// concurrent use.
+type NDJSONWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+
+	// RedactFields masks the named ENI fields in each record
+	RedactFields []string
+}
+
+// NewNDJSONWriter creates a writer that writes records to w
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// Write writes a single cleanup result as one line of JSON
+func (w *NDJSONWriter) Write(result enicleanup.ENIResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result.ENI = RedactENI(result.ENI, w.RedactFields)
+	return w.encoder.Encode(NDJSONRecord{
+		Time:        w.now().UTC().Format(time.RFC3339),
+		ID:          result.ENI.ID,
+		Region:      result.ENI.Region,
+		VpcID:       result.ENI.VPCID,
+		Description: result.ENI.Description,
+		Reason:      result.ENI.Reason,
+		Action:      result.Action,
+		Outcome:     result.Outcome,
+		Error:       result.Error,
+	})
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewNDJSONWriter(&buf)
+	writer.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	results := []enicleanup.ENIResult{
+		{ENI: enicleanup.OrphanedENI{ID: "eni-1", Region: "us-east-1"}, Action: "deleted", Outcome: enicleanup.OutcomeCleaned},
+		{ENI: enicleanup.OrphanedENI{ID: "eni-2", Region: "us-east-1"}, Outcome: enicleanup.OutcomeFailed, Error: "boom"},
+	}
+	for _, result := range results {
+		if err := writer.Write(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var records []NDJSONRecord
+	for scanner.Scan() {
+		var record NDJSONRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(records))
+	}
+	if records[0].ID != "eni-1" || records[0].Outcome != "cleaned" || records[0].Time != "2024-05-01T12:00:00Z" {
+		t.Errorf("unexpected first record %+v", records[0])
+	}
+	if records[1].Error != "boom" {
+		t.Errorf("expected error in second record, got %+v", records[1])
+	}
+}
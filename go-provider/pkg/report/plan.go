@@ -0,0 +1,123 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// PlanVersion is the version of the plan file format written by MarshalPlan
+const PlanVersion = 1
+
+// Plan is a reviewable list of cleanup candidates. It is written by a
+// detect-only run, reviewed and committed like any other change, and then
+// consumed by an apply run that only acts on the approved ENIs.
+type Plan struct {
+	Version    int             `json:"version"`
+	Candidates []PlanCandidate `json:"candidates"`
+}
+
+// PlanCandidate is a single ENI in a plan
+type PlanCandidate struct {
+	ID               string            `json:"id"`
+	Region           string            `json:"region"`
+	VpcID            string            `json:"vpcId"`
+	SubnetID         string            `json:"subnetId"`
+	AvailabilityZone string            `json:"availabilityZone"`
+	Description      string            `json:"description"`
+	InterfaceType    string            `json:"interfaceType,omitempty"`
+	OwnerID          string            `json:"ownerId,omitempty"`
+	RequesterID      string            `json:"requesterId,omitempty"`
+	PrivateIPAddress string            `json:"privateIpAddress,omitempty"`
+	SecurityGroups   []string          `json:"securityGroups"`
+	Tags             map[string]string `json:"tags"`
+	LoadBalancerARN  string            `json:"loadBalancerArn,omitempty"`
+	HasPublicIP      bool              `json:"hasPublicIp"`
+	Reason           string            `json:"reason,omitempty"`
+}
+
+// FormatPlan converts detected ENIs to a plan. Candidates and their security
+// groups are sorted, and nothing time-dependent is included, so detecting the
+// same ENIs always produces the same plan.
+func FormatPlan(enis []enicleanup.OrphanedENI) Plan {
+	candidates := make([]PlanCandidate, 0, len(enis))
+	for _, eni := range enis {
+		securityGroups := slices.Clone(eni.SecurityGroups)
+		sort.Strings(securityGroups)
+		if securityGroups == nil {
+			securityGroups = []string{}
+		}
+
+		tags := eni.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+
+		candidates = append(candidates, PlanCandidate{
+			ID:               eni.ID,
+			Region:           eni.Region,
+			VpcID:            eni.VPCID,
+			SubnetID:         eni.SubnetID,
+			AvailabilityZone: eni.AvailabilityZone,
+			Description:      eni.Description,
+			InterfaceType:    eni.InterfaceType,
+			OwnerID:          eni.OwnerID,
+			RequesterID:      eni.RequesterID,
+			PrivateIPAddress: eni.PrivateIPAddress,
+			SecurityGroups:   securityGroups,
+			Tags:             tags,
+			LoadBalancerARN:  eni.LoadBalancerARN,
+			HasPublicIP:      eni.HasPublicIP,
+			Reason:           eni.Reason,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Region != candidates[j].Region {
+			return candidates[i].Region < candidates[j].Region
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	return Plan{Version: PlanVersion, Candidates: candidates}
+}
+
+// MarshalPlan renders a plan as indented JSON with a trailing newline
+func MarshalPlan(plan Plan) ([]byte, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ReadPlan reads a plan file written by MarshalPlan
+func ReadPlan(path string) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to read plan %s: %w", path, err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return Plan{}, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.Version != PlanVersion {
+		return Plan{}, fmt.Errorf("unsupported plan version %d in %s", plan.Version, path)
+	}
+
+	return plan, nil
+}
+
+// NetworkInterfaceIds returns the IDs of the ENIs approved in the plan
+func (p Plan) NetworkInterfaceIds() []string {
+	ids := make([]string, 0, len(p.Candidates))
+	for _, candidate := range p.Candidates {
+		ids = append(ids, candidate.ID)
+	}
+	return ids
+}
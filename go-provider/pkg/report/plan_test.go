@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestPlanIsDeterministic(t *testing.T) {
+	enis := []enicleanup.OrphanedENI{
+		{ID: "eni-b", Region: "us-west-2", SecurityGroups: []string{"sg-2", "sg-1"}, Tags: map[string]string{"b": "2", "a": "1"}},
+		{ID: "eni-c", Region: "us-east-1"},
+		{ID: "eni-a", Region: "us-west-2"},
+	}
+	reversed := []enicleanup.OrphanedENI{enis[2], enis[1], enis[0]}
+
+	first, err := MarshalPlan(FormatPlan(enis))
+	if err != nil {
+		t.Fatalf("failed to marshal plan: %v", err)
+	}
+	second, err := MarshalPlan(FormatPlan(reversed))
+	if err != nil {
+		t.Fatalf("failed to marshal plan: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("plan depends on detection order:\n%s\n%s", first, second)
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, first, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ReadPlan(path)
+	if err != nil {
+		t.Fatalf("failed to read plan: %v", err)
+	}
+
+	ids := plan.NetworkInterfaceIds()
+	want := []string{"eni-c", "eni-a", "eni-b"}
+	if len(ids) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, ids)
+		}
+	}
+	if got := plan.Candidates[2].SecurityGroups; got[0] != "sg-1" || got[1] != "sg-2" {
+		t.Errorf("expected sorted security groups, got %v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+// Redacted replaces the value of a redacted field
+const Redacted = "REDACTED"
+
+// Fields that can be redacted, named like the report JSON fields. ENI IDs,
+// regions and counts are never redacted.
+const (
+	// RedactPrivateIP masks the primary private IP address
+	RedactPrivateIP = "privateIpAddress"
+	// RedactAccountID masks the owner account ID, including inside ARNs
+	RedactAccountID = "accountId"
+	// RedactTags masks tag values, keeping the keys
+	RedactTags = "tags"
+	// RedactDescription masks the ENI description
+	RedactDescription = "description"
+	// RedactVpcID masks the VPC ID
+	RedactVpcID = "vpcId"
+	// RedactSubnetID masks the subnet ID
+	RedactSubnetID = "subnetId"
+	// RedactSecurityGroups masks the security group IDs
+	RedactSecurityGroups = "securityGroups"
+	// RedactMacAddress masks the MAC address
+	RedactMacAddress = "macAddress"
+)
+
+// redactableFields lists the accepted RedactFields values
+var redactableFields = []string{
+	RedactPrivateIP, RedactAccountID, RedactTags, RedactDescription,
+	RedactVpcID, RedactSubnetID, RedactSecurityGroups, RedactMacAddress,
+}
+
+// accountIDPattern matches the account ID segment of an ARN
+var accountIDPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+// ValidateRedactFields checks that every field can be redacted
+func ValidateRedactFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(redactableFields, field) {
+			return fmt.Errorf("cannot redact %q; must be one of %v", field, redactableFields)
+		}
+	}
+	return nil
+}
+
+// RedactENIs returns copies of the ENIs with the named fields masked, for
+// rendering reports shared outside the team. The input is left unchanged.
+func RedactENIs(enis []enicleanup.OrphanedENI, fields []string) []enicleanup.OrphanedENI {
+	if len(fields) == 0 {
+		return enis
+	}
+
+	redacted := make([]enicleanup.OrphanedENI, 0, len(enis))
+	for _, eni := range enis {
+		redacted = append(redacted, RedactENI(eni, fields))
+	}
+	return redacted
+}
+
+// RedactENI returns a copy of the ENI with the named fields masked
+func RedactENI(eni enicleanup.OrphanedENI, fields []string) enicleanup.OrphanedENI {
+	for _, field := range fields {
+		switch field {
+		case RedactPrivateIP:
+			eni.PrivateIPAddress = redactString(eni.PrivateIPAddress)
+		case RedactAccountID:
+			eni.OwnerID = redactString(eni.OwnerID)
+			eni.RequesterID = accountIDPattern.ReplaceAllString(eni.RequesterID, Redacted)
+			eni.LoadBalancerARN = accountIDPattern.ReplaceAllString(eni.LoadBalancerARN, Redacted)
+		case RedactTags:
+			tags := maps.Clone(eni.Tags)
+			for key := range tags {
+				tags[key] = Redacted
+			}
+			eni.Tags = tags
+		case RedactDescription:
+			eni.Description = redactString(eni.Description)
+		case RedactVpcID:
+			eni.VPCID = redactString(eni.VPCID)
+		case RedactSubnetID:
+			eni.SubnetID = redactString(eni.SubnetID)
+		case RedactSecurityGroups:
+			groups := make([]string, len(eni.SecurityGroups))
+			for i := range groups {
+				groups[i] = Redacted
+			}
+			eni.SecurityGroups = groups
+		case RedactMacAddress:
+			eni.MacAddress = redactString(eni.MacAddress)
+		}
+	}
+	return eni
+}
+
+// redactString masks a non-empty value, leaving empty values empty
+func redactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return Redacted
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+)
+
+func TestRedactENIs(t *testing.T) {
+	enis := []enicleanup.OrphanedENI{
+		{
+			ID:               "eni-1",
+			Region:           "us-east-1",
+			VPCID:            "vpc-1",
+			OwnerID:          "123456789012",
+			PrivateIPAddress: "10.0.0.5",
+			Tags:             map[string]string{"Owner": "alice"},
+			SecurityGroups:   []string{"sg-1"},
+			LoadBalancerARN:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/abc",
+		},
+	}
+
+	redacted := RedactENIs(enis, []string{RedactPrivateIP, RedactAccountID, RedactTags})
+	eni := redacted[0]
+
+	if eni.ID != "eni-1" || eni.Region != "us-east-1" || eni.VPCID != "vpc-1" {
+		t.Errorf("expected IDs and unnamed fields to be kept, got %+v", eni)
+	}
+	if eni.PrivateIPAddress != Redacted || eni.OwnerID != Redacted || eni.Tags["Owner"] != Redacted {
+		t.Errorf("expected named fields to be redacted, got %+v", eni)
+	}
+	if strings.Contains(eni.LoadBalancerARN, "123456789012") {
+		t.Errorf("expected the account ID to be redacted from the ARN, got %s", eni.LoadBalancerARN)
+	}
+	if !slices.Equal(eni.SecurityGroups, []string{"sg-1"}) {
+		t.Errorf("expected security groups to be kept, got %v", eni.SecurityGroups)
+	}
+
+	// The core result stays complete
+	if enis[0].PrivateIPAddress != "10.0.0.5" || enis[0].Tags["Owner"] != "alice" {
+		t.Errorf("expected the input to be unchanged, got %+v", enis[0])
+	}
+}
+
+func TestValidateRedactFields(t *testing.T) {
+	if err := ValidateRedactFields([]string{RedactTags, RedactAccountID}); err != nil {
+		t.Errorf("expected known fields to be valid, got %v", err)
+	}
+	if err := ValidateRedactFields([]string{"id"}); err == nil {
+		t.Error("expected IDs not to be redactable")
+	}
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/securityhub"
+	shtypes "github.com/aws/aws-sdk-go-v2/service/securityhub/types"
+)
+
+// securityHubBatchSize is the maximum number of findings per BatchImportFindings call
+const securityHubBatchSize = 100
+
+// SecurityHubAPI is the subset of the Security Hub client used to import findings
+type SecurityHubAPI interface {
+	BatchImportFindings(ctx context.Context, params *securityhub.BatchImportFindingsInput, optFns ...func(*securityhub.Options)) (*securityhub.BatchImportFindingsOutput, error)
+}
+
+// SubmitSecurityHubFindings imports ASFF findings into Security Hub in batches.
+// It returns the number of findings imported and an error describing any failures.
+func SubmitSecurityHubFindings(ctx context.Context, client SecurityHubAPI, findings []ASFFFinding) (int, error) {
+	imported := 0
+	var failures []string
+
+	for start := 0; start < len(findings); start += securityHubBatchSize {
+		end := start + securityHubBatchSize
+		if end > len(findings) {
+			end = len(findings)
+		}
+
+		batch := make([]shtypes.AwsSecurityFinding, 0, end-start)
+		for _, finding := range findings[start:end] {
+			batch = append(batch, toSecurityHubFinding(finding))
+		}
+
+		resp, err := client.BatchImportFindings(ctx, &securityhub.BatchImportFindingsInput{
+			Findings: batch,
+		})
+		if err != nil {
+			return imported, fmt.Errorf("failed to import findings into Security Hub: %w", err)
+		}
+
+		imported += len(batch) - len(resp.FailedFindings)
+		for _, failed := range resp.FailedFindings {
+			failures = append(failures, fmt.Sprintf("%s: %s", aws.ToString(failed.Id), aws.ToString(failed.ErrorMessage)))
+		}
+	}
+
+	if len(failures) > 0 {
+		return imported, fmt.Errorf("%d findings were rejected by Security Hub: %v", len(failures), failures)
+	}
+
+	return imported, nil
+}
+
+// toSecurityHubFinding converts an ASFF finding to the Security Hub SDK type
+func toSecurityHubFinding(finding ASFFFinding) shtypes.AwsSecurityFinding {
+	resources := make([]shtypes.Resource, 0, len(finding.Resources))
+	for _, resource := range finding.Resources {
+		resources = append(resources, shtypes.Resource{
+			Id:        aws.String(resource.ID),
+			Type:      aws.String(resource.Type),
+			Partition: shtypes.Partition(resource.Partition),
+			Region:    aws.String(resource.Region),
+			Tags:      resource.Tags,
+		})
+	}
+
+	return shtypes.AwsSecurityFinding{
+		SchemaVersion: aws.String(finding.SchemaVersion),
+		Id:            aws.String(finding.ID),
+		ProductArn:    aws.String(finding.ProductArn),
+		GeneratorId:   aws.String(finding.GeneratorID),
+		AwsAccountId:  aws.String(finding.AwsAccountID),
+		Types:         finding.Types,
+		CreatedAt:     aws.String(finding.CreatedAt),
+		UpdatedAt:     aws.String(finding.UpdatedAt),
+		Severity:      &shtypes.Severity{Label: shtypes.SeverityLabel(finding.Severity.Label)},
+		Title:         aws.String(finding.Title),
+		Description:   aws.String(finding.Description),
+		Remediation: &shtypes.Remediation{
+			Recommendation: &shtypes.Recommendation{
+				Text: aws.String(finding.Remediation.Recommendation.Text),
+				Url:  aws.String(finding.Remediation.Recommendation.URL),
+			},
+		},
+		Resources:     resources,
+		RecordState:   shtypes.RecordState(finding.RecordState),
+		Workflow:      &shtypes.Workflow{Status: shtypes.WorkflowStatus(finding.Workflow.Status)},
+		ProductFields: finding.ProductFields,
+	}
+}
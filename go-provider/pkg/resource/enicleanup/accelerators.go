@@ -0,0 +1,75 @@
+package enicleanup
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// ENI interface types created for Gateway Load Balancer endpoints and Global Accelerator
+const (
+	gatewayLoadBalancerEndpointInterfaceType = "gateway_load_balancer_endpoint"
+	globalAcceleratorInterfaceType           = "global_accelerator_managed"
+)
+
+// vpcEndpointPattern matches the VPC endpoint ID in a GWLB endpoint ENI's description
+var vpcEndpointPattern = regexp.MustCompile(`\bvpce-[0-9a-f]+\b`)
+
+// acceleratorIDPattern matches the accelerator ID, a UUID, in a Global
+// Accelerator ENI's description or requester
+var acceleratorIDPattern = regexp.MustCompile(`\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+// isGatewayLoadBalancerEndpointENI checks whether an ENI belongs to a Gateway
+// Load Balancer endpoint. Deleting one breaks traffic inspection for the VPC,
+// so these are always skipped.
+func isGatewayLoadBalancerEndpointENI(eni types.NetworkInterface) bool {
+	return string(eni.InterfaceType) == gatewayLoadBalancerEndpointInterfaceType
+}
+
+// isGlobalAcceleratorENI checks whether an ENI belongs to a Global Accelerator
+// endpoint. Their descriptions vary, so the interface type and requester are
+// checked as well, and these are always skipped.
+func isGlobalAcceleratorENI(eni types.NetworkInterface) bool {
+	if string(eni.InterfaceType) == globalAcceleratorInterfaceType {
+		return true
+	}
+	if strings.Contains(strings.ToLower(aws.ToString(eni.RequesterId)), "globalaccelerator") {
+		return true
+	}
+	return strings.Contains(strings.ToLower(aws.ToString(eni.Description)), "global accelerator")
+}
+
+// protectedEndpoint reports whether an ENI belongs to a GWLB endpoint or
+// Global Accelerator, returning the skip reason and the endpoint or
+// accelerator it belongs to
+func protectedEndpoint(eni types.NetworkInterface) (string, string, bool) {
+	var reason, unknown string
+	switch {
+	case isGatewayLoadBalancerEndpointENI(eni):
+		reason, unknown = SkipReasonGatewayLoadBalancerEndpoint, "unknown endpoint"
+	case isGlobalAcceleratorENI(eni):
+		reason, unknown = SkipReasonGlobalAccelerator, "unknown accelerator"
+	default:
+		return "", "", false
+	}
+
+	owner := endpointOwner(eni)
+	if owner == "" {
+		owner = unknown
+	}
+	return reason, owner, true
+}
+
+// endpointOwner returns the VPC endpoint or accelerator an ENI belongs to, if
+// its description or requester names one
+func endpointOwner(eni types.NetworkInterface) string {
+	if id := vpcEndpointPattern.FindString(aws.ToString(eni.Description)); id != "" {
+		return id
+	}
+	if id := acceleratorIDPattern.FindString(aws.ToString(eni.Description)); id != "" {
+		return id
+	}
+	return acceleratorIDPattern.FindString(aws.ToString(eni.RequesterId))
+}
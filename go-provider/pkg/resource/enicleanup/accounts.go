@@ -0,0 +1,198 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultAccountConcurrency is the default bound on accounts processed at once
+const DefaultAccountConcurrency = 4
+
+// AccountRole is an AWS account to scan by assuming a role in it
+type AccountRole struct {
+	AccountID string `pulumi:"accountId"`
+	RoleArn   string `pulumi:"roleArn"`
+}
+
+// AccountResult is the outcome of detection and cleanup in one account
+type AccountResult struct {
+	AccountID string
+	Detected  DetectResult
+	Cleanup   CleanupResult
+	// Err is set when the account couldn't be scanned at all, for example
+	// because its role couldn't be assumed
+	Err error
+}
+
+// newSTSClient creates the STS client roles are assumed with. Tests replace
+// it to mock STS.
+var newSTSClient = func(cfg aws.Config) stscreds.AssumeRoleAPIClient {
+	return sts.NewFromConfig(cfg)
+}
+
+// newAssumeRoleCredentials returns credentials for a role assumed with the
+// base config's credentials. Tests replace it to avoid calling STS.
+var newAssumeRoleCredentials = func(cfg aws.Config, roleArn string, optFns ...func(*stscreds.AssumeRoleOptions)) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(newSTSClient(cfg), roleArn, optFns...))
+}
+
+// RunAccounts runs detection, and cleanup unless cleanupOptions is nil, in
+// each account by assuming its role. Up to concurrency accounts run at once
+// (DefaultAccountConcurrency when not positive). A failure in one account is
+// recorded in its result and doesn't stop the others. Results are in the
+// order of accounts, with every ENI tagged with its account ID.
+func RunAccounts(ctx context.Context, regions []string, accounts []AccountRole, detectOptions DetectOptions, cleanupOptions *CleanupOptions, concurrency int) []AccountResult {
+	if concurrency <= 0 {
+		concurrency = DefaultAccountConcurrency
+	}
+
+	// Callbacks aren't called concurrently within an account, so serialize
+	// them across accounts too
+	var callbackMu sync.Mutex
+	if onRegionComplete := detectOptions.OnRegionComplete; onRegionComplete != nil {
+		detectOptions.OnRegionComplete = func(region string, result RegionResult) {
+			callbackMu.Lock()
+			defer callbackMu.Unlock()
+			onRegionComplete(region, result)
+		}
+	}
+	if cleanupOptions != nil && cleanupOptions.OnENIResult != nil {
+		options := *cleanupOptions
+		onENIResult := options.OnENIResult
+		options.OnENIResult = func(result ENIResult) {
+			callbackMu.Lock()
+			defer callbackMu.Unlock()
+			onENIResult(result)
+		}
+		cleanupOptions = &options
+	}
+
+	results := make([]AccountResult, len(accounts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = runAccount(ctx, regions, account, detectOptions, cleanupOptions)
+			if err := results[i].Err; err != nil {
+				logging.Warningf("Skipping AWS account %s: %v", account.AccountID, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runAccount runs detection and cleanup in a single account
+func runAccount(ctx context.Context, regions []string, account AccountRole, detectOptions DetectOptions, cleanupOptions *CleanupOptions) AccountResult {
+	result := AccountResult{AccountID: account.AccountID}
+	if account.AccountID == "" || account.RoleArn == "" {
+		result.Err = fmt.Errorf("account roles need both an account ID and a role ARN")
+		return result
+	}
+
+	// With RoleArn, each account's role is assumed from that role
+	base, err := detectOptions.assume(ctx, detectOptions.ConfigOptions)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	cfg, err := loadConfig(ctx, "", detectOptions.ConfigOptions, base)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load AWS config: %w", err)
+		return result
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+	credentials := newAssumeRoleCredentials(cfg, account.RoleArn)
+
+	// Never act on an account other than the one the role was listed for
+	cfg.Credentials = credentials
+	actual, err := lookupAccountID(ctx, cfg)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to assume role %s: %w", account.RoleArn, err)
+		return result
+	}
+	if actual != account.AccountID {
+		result.Err = fmt.Errorf("role %s belongs to AWS account %s, not %s", account.RoleArn, actual, account.AccountID)
+		return result
+	}
+
+	detectOptions.credentials = credentials
+	result.Detected, err = DetectOrphanedENIsWithDetails(ctx, regions, detectOptions)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+		return result
+	}
+	for i := range result.Detected.ENIs {
+		result.Detected.ENIs[i].AccountID = account.AccountID
+	}
+	for i := range result.Detected.Skipped {
+		result.Detected.Skipped[i].AccountID = account.AccountID
+	}
+
+	if cleanupOptions == nil {
+		return result
+	}
+	options := *cleanupOptions
+	options.credentials = credentials
+	result.Cleanup = CleanupOrphanedENIsWithOptions(ctx, result.Detected.ENIs, options)
+	ReconcileManualCleanupTags(ctx, result.Detected, options, &result.Cleanup)
+	for i := range result.Cleanup.CleanedENIs {
+		result.Cleanup.CleanedENIs[i].AccountID = account.AccountID
+	}
+	for i := range result.Cleanup.DeadLetter {
+		result.Cleanup.DeadLetter[i].AccountID = account.AccountID
+	}
+	return result
+}
+
+// CombineAccountResults merges per-account results into a single detection
+// and cleanup result. Accounts that failed are listed in FailedAccounts, with
+// their errors in Errors, regions that failed in detection Errors with their
+// account, and region timings are keyed "<account>/<region>".
+func CombineAccountResults(results []AccountResult) (DetectResult, CleanupResult) {
+	var detected DetectResult
+	var cleanup CleanupResult
+	for _, result := range results {
+		if result.Err != nil {
+			cleanup.FailedAccounts = append(cleanup.FailedAccounts, result.AccountID)
+			cleanup.Errors = append(cleanup.Errors, fmt.Sprintf("AWS account %s: %v", result.AccountID, result.Err))
+			continue
+		}
+
+		detected.ENIs = append(detected.ENIs, result.Detected.ENIs...)
+		detected.Skipped = append(detected.Skipped, result.Detected.Skipped...)
+		detected.AbortedVPCs = append(detected.AbortedVPCs, result.Detected.AbortedVPCs...)
+		for _, regionErr := range result.Detected.Errors {
+			regionErr.AccountID = result.AccountID
+			detected.Errors = append(detected.Errors, regionErr)
+		}
+		detected.RegionTimings = addAccountTimings(detected.RegionTimings, result.AccountID, result.Detected.RegionTimings)
+		cleanup.RegionTimings = addAccountTimings(cleanup.RegionTimings, result.AccountID, result.Cleanup.RegionTimings)
+
+		cleanup.SuccessCount += result.Cleanup.SuccessCount
+		cleanup.FailureCount += result.Cleanup.FailureCount
+		cleanup.SkippedCount += result.Cleanup.SkippedCount
+		cleanup.CleanedENIs = append(cleanup.CleanedENIs, result.Cleanup.CleanedENIs...)
+		cleanup.Errors = append(cleanup.Errors, result.Cleanup.Errors...)
+		cleanup.VerificationFailed = append(cleanup.VerificationFailed, result.Cleanup.VerificationFailed...)
+		cleanup.DeadLetter = append(cleanup.DeadLetter, result.Cleanup.DeadLetter...)
+		cleanup.ManualCleanup = append(cleanup.ManualCleanup, result.Cleanup.ManualCleanup...)
+		cleanup.ReconciledENIs = append(cleanup.ReconciledENIs, result.Cleanup.ReconciledENIs...)
+		cleanup.Truncated = cleanup.Truncated || result.Cleanup.Truncated
+	}
+	cleanup.SkippedDetails = detected.Skipped
+	return detected, cleanup
+}
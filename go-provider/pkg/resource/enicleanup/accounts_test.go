@@ -0,0 +1,90 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+// fakeRoleCredentials stands in for assumed role credentials
+type fakeRoleCredentials struct {
+	roleArn string
+}
+
+func (c fakeRoleCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return aws.Credentials{}, nil
+}
+
+// useFakeRoles makes each role ARN resolve to the account it maps to
+func useFakeRoles(t *testing.T, accounts map[string]string) {
+	t.Helper()
+	originalCredentials, originalAccount := newAssumeRoleCredentials, lookupAccountID
+	newAssumeRoleCredentials = func(cfg aws.Config, roleArn string, optFns ...func(*stscreds.AssumeRoleOptions)) aws.CredentialsProvider {
+		return fakeRoleCredentials{roleArn: roleArn}
+	}
+	lookupAccountID = func(ctx context.Context, cfg aws.Config) (string, error) {
+		return accounts[cfg.Credentials.(fakeRoleCredentials).roleArn], nil
+	}
+	t.Cleanup(func() { newAssumeRoleCredentials, lookupAccountID = originalCredentials, originalAccount })
+}
+
+func TestRunAccountsIsolatesFailedAccounts(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"))
+	useFakeEC2(t, fake)
+	useFakeRoles(t, map[string]string{
+		"arn:aws:iam::111111111111:role/cleanup": "111111111111",
+		"arn:aws:iam::333333333333:role/cleanup": "333333333333",
+	})
+
+	accounts := []AccountRole{
+		{AccountID: "222222222222", RoleArn: "arn:aws:iam::333333333333:role/cleanup"},
+		{AccountID: "111111111111", RoleArn: "arn:aws:iam::111111111111:role/cleanup"},
+	}
+	results := RunAccounts(context.Background(), []string{"us-east-1"}, accounts, DetectOptions{}, &CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+	}, 2)
+
+	if len(results) != 2 {
+		t.Fatalf("expected a result per account, got %+v", results)
+	}
+	if err := results[0].Err; err == nil || !strings.Contains(err.Error(), "belongs to AWS account 333333333333") {
+		t.Errorf("expected the mismatched role to be refused, got %v", err)
+	}
+	if results[1].Err != nil || len(results[1].Cleanup.CleanedENIs) != 1 {
+		t.Fatalf("expected the other account to be cleaned up, got %+v", results[1])
+	}
+	if got := results[1].Cleanup.CleanedENIs[0].AccountID; got != "111111111111" {
+		t.Errorf("expected the cleaned ENI to be tagged with its account, got %q", got)
+	}
+
+	detected, result := CombineAccountResults(results)
+	if len(detected.ENIs) != 1 || detected.ENIs[0].AccountID != "111111111111" {
+		t.Errorf("expected one detected ENI tagged with its account, got %+v", detected.ENIs)
+	}
+	if !slices.Equal(result.FailedAccounts, []string{"222222222222"}) || result.SuccessCount != 1 {
+		t.Errorf("expected one failed account and one cleaned ENI, got %+v", result)
+	}
+	if ResultCode(result) != ResultCodeFailed {
+		t.Errorf("expected a failed account to fail the run, got %d", ResultCode(result))
+	}
+}
+
+func TestRunAccountsDetectOnly(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"))
+	useFakeEC2(t, fake)
+	useFakeRoles(t, map[string]string{"arn:aws:iam::111111111111:role/cleanup": "111111111111"})
+
+	accounts := []AccountRole{{AccountID: "111111111111", RoleArn: "arn:aws:iam::111111111111:role/cleanup"}}
+	results := RunAccounts(context.Background(), []string{"us-east-1"}, accounts, DetectOptions{}, nil, 0)
+
+	if len(results) != 1 || len(results[0].Detected.ENIs) != 1 {
+		t.Fatalf("expected one detected ENI, got %+v", results)
+	}
+	if fake.deleted["eni-1"] != 0 {
+		t.Error("expected no cleanup without cleanup options")
+	}
+}
@@ -0,0 +1,49 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// AddressAssociation is an Elastic IP associated with one of an ENI's private IPs
+type AddressAssociation struct {
+	AssociationID string
+	PublicIP      string
+}
+
+// newAddressAssociation returns the Elastic IP association of an ENI or
+// private IP. Auto-assigned public IPs have no association ID and don't block
+// deletion, so ok is false for them.
+func newAddressAssociation(association *types.NetworkInterfaceAssociation) (AddressAssociation, bool) {
+	if association == nil || aws.ToString(association.AssociationId) == "" {
+		return AddressAssociation{}, false
+	}
+	return AddressAssociation{
+		AssociationID: *association.AssociationId,
+		PublicIP:      aws.ToString(association.PublicIp),
+	}, true
+}
+
+// disassociateAddresses removes an ENI's Elastic IP associations so it can be
+// deleted, returning the public IPs disassociated. Failures are recorded and
+// the delete is still attempted.
+func disassociateAddresses(ctx context.Context, client ENIClient, eni OrphanedENI, results *resultAccumulator) []string {
+	var disassociated []string
+	for _, association := range eni.AddressAssociations {
+		logging.V(5).Infof("Disassociating address %s (%s) from ENI %s", association.PublicIP, association.AssociationID, eni.ID)
+		_, err := client.DisassociateAddress(ctx, &ec2.DisassociateAddressInput{
+			AssociationId: aws.String(association.AssociationID),
+		})
+		if err != nil {
+			results.addError(fmt.Sprintf("Failed to disassociate address %s from ENI %s: %v", association.PublicIP, eni.ID, err))
+			continue
+		}
+		disassociated = append(disassociated, association.PublicIP)
+	}
+	return disassociated
+}
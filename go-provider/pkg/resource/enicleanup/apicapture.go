@@ -0,0 +1,228 @@
+package enicleanup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// apiRecord is a captured EC2 call, stored one JSON object per line
+type apiRecord struct {
+	Operation string          `json:"operation"`
+	Region    string          `json:"region"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Output    json.RawMessage `json:"output,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// accountIDPattern matches AWS account IDs in captured requests and responses
+var accountIDPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+// redactedAccountID replaces account IDs in captures
+const redactedAccountID = "000000000000"
+
+// apiOutputs creates an empty output for each EC2 operation that can be
+// replayed. Captures happen before signing, so they never contain credentials.
+var apiOutputs = map[string]func() any{
+	"DescribeNetworkInterfaces":       func() any { return &ec2.DescribeNetworkInterfacesOutput{} },
+	"DescribeAvailabilityZones":       func() any { return &ec2.DescribeAvailabilityZonesOutput{} },
+	"DescribeSecurityGroups":          func() any { return &ec2.DescribeSecurityGroupsOutput{} },
+	"DescribeVpcs":                    func() any { return &ec2.DescribeVpcsOutput{} },
+	"DescribeSubnets":                 func() any { return &ec2.DescribeSubnetsOutput{} },
+	"DescribeNatGateways":             func() any { return &ec2.DescribeNatGatewaysOutput{} },
+	"DescribeInstances":               func() any { return &ec2.DescribeInstancesOutput{} },
+	"ModifyNetworkInterfaceAttribute": func() any { return &ec2.ModifyNetworkInterfaceAttributeOutput{} },
+	"DetachNetworkInterface":          func() any { return &ec2.DetachNetworkInterfaceOutput{} },
+	"DeleteNetworkInterface":          func() any { return &ec2.DeleteNetworkInterfaceOutput{} },
+	"CreateTags":                      func() any { return &ec2.CreateTagsOutput{} },
+	"DeleteTags":                      func() any { return &ec2.DeleteTagsOutput{} },
+}
+
+// apiCaptureOptions returns the EC2 client options that record calls to
+// recordPath or replay them from replayPath, if either is set
+func apiCaptureOptions(recordPath, replayPath *string) ([]func(*ec2.Options), error) {
+	switch {
+	case replayPath != nil && *replayPath != "":
+		replay, err := loadAPIReplay(*replayPath)
+		if err != nil {
+			return nil, err
+		}
+		return []func(*ec2.Options){withInitializeMiddleware(replay.middleware())}, nil
+	case recordPath != nil && *recordPath != "":
+		return []func(*ec2.Options){withInitializeMiddleware(recordAPIMiddleware(*recordPath))}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// withInitializeMiddleware adds a middleware to the end of the client's
+// initialize step, after the operation's service metadata middleware has put
+// the operation name and region in the context
+func withInitializeMiddleware(m middleware.InitializeMiddleware) func(*ec2.Options) {
+	return func(o *ec2.Options) {
+		o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Initialize.Add(m, middleware.After)
+		})
+	}
+}
+
+// redactAccountIDs marshals a value to JSON with account IDs redacted
+func redactAccountIDs(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return accountIDPattern.ReplaceAll(data, []byte(redactedAccountID))
+}
+
+// recordMu serializes appends to capture files
+var recordMu sync.Mutex
+
+// recordAPIMiddleware appends each call's sanitized request and response to path
+func recordAPIMiddleware(path string) middleware.InitializeMiddleware {
+	return middleware.InitializeMiddlewareFunc("ENICleanupRecordAPI", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+
+		record := apiRecord{
+			Operation: awsmiddleware.GetOperationName(ctx),
+			Region:    awsmiddleware.GetRegion(ctx),
+			Input:     redactAccountIDs(in.Parameters),
+		}
+		if err != nil {
+			record.Error = accountIDPattern.ReplaceAllString(err.Error(), redactedAccountID)
+		} else {
+			record.Output = redactAccountIDs(out.Result)
+		}
+		if recordErr := appendAPIRecord(path, record); recordErr != nil {
+			logging.V(5).Infof("Failed to record %s call: %v", record.Operation, recordErr)
+		}
+
+		return out, metadata, err
+	})
+}
+
+// appendAPIRecord appends a record to a capture file
+func appendAPIRecord(path string, record apiRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// apiReplay serves captured responses in the order they were recorded, per
+// operation and region
+type apiReplay struct {
+	mu      sync.Mutex
+	records map[string][]apiRecord
+}
+
+// apiReplays caches loaded captures by path, so detection and cleanup in the
+// same process consume a single sequence of responses
+var apiReplays = struct {
+	mu     sync.Mutex
+	byPath map[string]*apiReplay
+}{byPath: make(map[string]*apiReplay)}
+
+// loadAPIReplay loads a capture file, or returns the already loaded replay
+func loadAPIReplay(path string) (*apiReplay, error) {
+	apiReplays.mu.Lock()
+	defer apiReplays.mu.Unlock()
+
+	if replay, ok := apiReplays.byPath[path]; ok {
+		return replay, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open API capture: %w", err)
+	}
+	defer f.Close()
+
+	replay := &apiReplay{records: make(map[string][]apiRecord)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var record apiRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse API capture %s: %w", path, err)
+		}
+		key := record.Operation + "/" + record.Region
+		replay.records[key] = append(replay.records[key], record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API capture %s: %w", path, err)
+	}
+
+	apiReplays.byPath[path] = replay
+	return replay, nil
+}
+
+// next returns the next recorded call for an operation in a region
+func (r *apiReplay) next(operation, region string) (apiRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := operation + "/" + region
+	if len(r.records[key]) == 0 {
+		return apiRecord{}, false
+	}
+	record := r.records[key][0]
+	r.records[key] = r.records[key][1:]
+	return record, true
+}
+
+// middleware answers each call from the capture instead of calling AWS
+func (r *apiReplay) middleware() middleware.InitializeMiddleware {
+	return middleware.InitializeMiddlewareFunc("ENICleanupReplayAPI", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		return r.respond(awsmiddleware.GetOperationName(ctx), awsmiddleware.GetRegion(ctx))
+	})
+}
+
+// respond builds the recorded response to a call
+func (r *apiReplay) respond(operation, region string) (middleware.InitializeOutput, middleware.Metadata, error) {
+	record, ok := r.next(operation, region)
+	if !ok {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("no recorded response for %s in %s", operation, region)
+	}
+	if record.Error != "" {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, errors.New(record.Error)
+	}
+
+	newOutput, ok := apiOutputs[operation]
+	if !ok {
+		return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("replaying %s is not supported", operation)
+	}
+	output := newOutput()
+	if len(record.Output) > 0 {
+		if err := json.Unmarshal(record.Output, output); err != nil {
+			return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("failed to decode recorded %s response: %w", operation, err)
+		}
+	}
+	return middleware.InitializeOutput{Result: output}, middleware.Metadata{}, nil
+}
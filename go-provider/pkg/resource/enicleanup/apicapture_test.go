@@ -0,0 +1,94 @@
+package enicleanup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// stubTransport answers every request with a fixed EC2 XML response
+type stubTransport struct {
+	body  string
+	calls int
+}
+
+func (s *stubTransport) Do(req *http.Request) (*http.Response, error) {
+	s.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(bytes.NewBufferString(s.body)),
+		Request:    req,
+	}, nil
+}
+
+// captureClient creates an EC2 client in us-east-1 sending requests to
+// transport, with the capture options for recordPath or replayPath
+func captureClient(t *testing.T, transport *stubTransport, recordPath, replayPath *string) *ec2.Client {
+	t.Helper()
+	captureOptions, err := apiCaptureOptions(recordPath, replayPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := ec2.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  transport,
+	}
+	return ec2.New(options, captureOptions...)
+}
+
+func TestAPICaptureRecordsAndReplays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.ndjson")
+
+	transport := &stubTransport{body: `<DescribeNetworkInterfacesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+	<requestId>req-1</requestId>
+	<networkInterfaceSet>
+		<item>
+			<networkInterfaceId>eni-1</networkInterfaceId>
+			<ownerId>123456789012</ownerId>
+		</item>
+	</networkInterfaceSet>
+</DescribeNetworkInterfacesResponse>`}
+	recorder := captureClient(t, transport, &path, nil)
+	if _, err := recorder.DescribeNetworkInterfaces(context.Background(), &ec2.DescribeNetworkInterfacesInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"operation":"DescribeNetworkInterfaces","region":"us-east-1"`) {
+		t.Errorf("expected the call to be recorded with its operation and region, got %s", data)
+	}
+	if strings.Contains(string(data), "123456789012") || !strings.Contains(string(data), redactedAccountID) {
+		t.Errorf("expected the account ID to be redacted, got %s", data)
+	}
+
+	// The replay answers without sending a request
+	unreachable := &stubTransport{}
+	replay := captureClient(t, unreachable, nil, &path)
+	out, err := replay.DescribeNetworkInterfaces(context.Background(), &ec2.DescribeNetworkInterfacesInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.NetworkInterfaces) != 1 || aws.ToString(out.NetworkInterfaces[0].OwnerId) != redactedAccountID {
+		t.Errorf("expected the recorded response, got %+v", out)
+	}
+
+	if _, err := replay.DescribeNetworkInterfaces(context.Background(), &ec2.DescribeNetworkInterfacesInput{}); err == nil {
+		t.Error("expected an error once the recorded responses are used up")
+	}
+	if unreachable.calls != 0 {
+		t.Errorf("expected the replay not to call AWS, got %d requests", unreachable.calls)
+	}
+}
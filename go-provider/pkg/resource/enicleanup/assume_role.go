@@ -0,0 +1,61 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+// DefaultSessionName is the session name used when assuming RoleOptions.RoleArn
+const DefaultSessionName = "eni-cleanup"
+
+// RoleOptions assume an IAM role for every AWS call, to clean up ENIs in
+// another account from a central tooling account. The role is assumed with
+// the credentials of ConfigOptions.Profile, AWS_PROFILE or the environment,
+// which are then only used to call STS; once RoleArn is set, it takes
+// precedence for everything else.
+type RoleOptions struct {
+	// RoleArn is the IAM role to assume; empty uses the default credentials
+	RoleArn string
+	// ExternalId is passed when assuming RoleArn, for roles that require one
+	ExternalId string
+	// SessionName names the assumed role session; defaults to DefaultSessionName
+	SessionName string
+}
+
+// validate checks ExternalId and SessionName only come with a role
+func (r RoleOptions) validate() []error {
+	if r.RoleArn == "" && (r.ExternalId != "" || r.SessionName != "") {
+		return []error{invalidOption("roleArn", "externalId and sessionName require roleArn")}
+	}
+	return nil
+}
+
+// assume returns credentials for RoleArn, assumed with the credentials
+// source loads, or nil without a role to keep using those credentials
+func (r RoleOptions) assume(ctx context.Context, source ConfigOptions) (aws.CredentialsProvider, error) {
+	if r.RoleArn == "" {
+		return nil, nil
+	}
+
+	cfg, err := loadConfig(ctx, "", source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config to assume role %s: %w", r.RoleArn, err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+
+	sessionName := r.SessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+	return newAssumeRoleCredentials(cfg, r.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		if r.ExternalId != "" {
+			o.ExternalID = aws.String(r.ExternalId)
+		}
+	}), nil
+}
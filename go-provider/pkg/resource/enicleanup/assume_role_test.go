@@ -0,0 +1,130 @@
+package enicleanup
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// fakeSTS records the AssumeRole calls it answers
+type fakeSTS struct {
+	mu    sync.Mutex
+	calls []*sts.AssumeRoleInput
+}
+
+func (f *fakeSTS) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, params)
+	return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+		AccessKeyId:     aws.String("ASIAROLE"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+// useFakeSTS makes roles be assumed with the fake for the rest of the test
+func useFakeSTS(t *testing.T, fake *fakeSTS) {
+	t.Helper()
+	original := newSTSClient
+	newSTSClient = func(cfg aws.Config) stscreds.AssumeRoleAPIClient { return fake }
+	t.Cleanup(func() { newSTSClient = original })
+}
+
+// credentialsFactory returns a ClientFactory handing out the stub and
+// recording the credentials each client was created with
+func credentialsFactory(stub ENIClient, credentials *[]aws.CredentialsProvider) ClientFactory {
+	var mu sync.Mutex
+	return func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient {
+		mu.Lock()
+		defer mu.Unlock()
+		*credentials = append(*credentials, cfg.Credentials)
+		return stub
+	}
+}
+
+func TestAssumeRole(t *testing.T) {
+	tests := []struct {
+		name            string
+		role            RoleOptions
+		wantSessionName string
+		wantExternalID  string
+		run             func(role RoleOptions, factory ClientFactory) error
+	}{
+		{
+			name:            "detection",
+			role:            RoleOptions{RoleArn: "arn:aws:iam::222222222222:role/cleanup", ExternalId: "tooling", SessionName: "nightly"},
+			wantSessionName: "nightly",
+			wantExternalID:  "tooling",
+			run: func(role RoleOptions, factory ClientFactory) error {
+				_, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, DetectOptions{RoleOptions: role, ClientFactory: factory})
+				return err
+			},
+		},
+		{
+			name:            "cleanup",
+			role:            RoleOptions{RoleArn: "arn:aws:iam::222222222222:role/cleanup"},
+			wantSessionName: DefaultSessionName,
+			run: func(role RoleOptions, factory ClientFactory) error {
+				eni := OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}}
+				CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{eni}, CleanupOptions{
+					RoleOptions:            role,
+					ClientFactory:          factory,
+					DefaultSecurityGroupId: aws.String("sg-default"),
+				})
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSTS{}
+			useFakeSTS(t, fake)
+			var credentials []aws.CredentialsProvider
+			if err := tt.run(tt.role, credentialsFactory(newFakeEC2(availableENI("eni-1")), &credentials)); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(credentials) == 0 {
+				t.Fatal("expected an EC2 client to be created")
+			}
+			for _, provider := range credentials {
+				if provider == nil {
+					t.Fatal("expected the EC2 client to use the assumed role")
+				}
+				creds, err := provider.Retrieve(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if creds.AccessKeyID != "ASIAROLE" {
+					t.Errorf("expected the role's credentials, got %q", creds.AccessKeyID)
+				}
+			}
+
+			call := fake.calls[0]
+			if aws.ToString(call.RoleArn) != tt.role.RoleArn || aws.ToString(call.RoleSessionName) != tt.wantSessionName || aws.ToString(call.ExternalId) != tt.wantExternalID {
+				t.Errorf("expected role %s as session %q with external ID %q, got %+v", tt.role.RoleArn, tt.wantSessionName, tt.wantExternalID, call)
+			}
+		})
+	}
+}
+
+func TestRoleOptionsValidation(t *testing.T) {
+	if err := (DetectOptions{RoleOptions: RoleOptions{ExternalId: "tooling"}}).Validate(); err == nil {
+		t.Error("expected externalId without roleArn to be rejected")
+	}
+	options := DetectOptions{PerRegionOptions: map[string]DetectOptions{
+		"us-east-1": {RoleOptions: RoleOptions{RoleArn: "arn:aws:iam::222222222222:role/cleanup"}},
+	}}
+	if err := options.Validate(); err == nil {
+		t.Error("expected a per-region roleArn to be rejected")
+	}
+}
@@ -0,0 +1,162 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// AuditTagKey is the tag TagOnly cleanup sets on candidate ENIs. Its value is
+// the audit run ID.
+const AuditTagKey = "eni-cleanup:audit-candidate"
+
+// DefaultTagConcurrency is the number of concurrent CreateTags calls in TagOnly mode
+const DefaultTagConcurrency = 4
+
+// maxTagResources is the most resources a single CreateTags call accepts
+const maxTagResources = 1000
+
+// auditActionTagged is the action reported for ENIs tagged in TagOnly mode
+const auditActionTagged = "tagged for audit"
+
+// auditRunID returns the configured audit run ID, defaulting to the current UTC date
+func (o CleanupOptions) auditRunID() string {
+	if o.AuditRunID != "" {
+		return o.AuditRunID
+	}
+	return time.Now().UTC().Format(time.DateOnly)
+}
+
+// tagConcurrency returns the configured tagging concurrency or the default
+func (o CleanupOptions) tagConcurrency() int {
+	if o.TagConcurrency > 0 {
+		return o.TagConcurrency
+	}
+	return DefaultTagConcurrency
+}
+
+// auditBatch is a set of ENIs in one region tagged with a single CreateTags call
+type auditBatch struct {
+	client ENIClient
+	enis   []OrphanedENI
+}
+
+// tagAuditCandidates tags candidate ENIs with AuditTagKey instead of cleaning
+// them up. ENIs already tagged for this run are skipped, and the rest are
+// tagged in batches of up to maxTagResources per region, with at most
+// TagConcurrency calls in flight.
+func tagAuditCandidates(ctx context.Context, enisByRegion map[string][]OrphanedENI, options CleanupOptions, results *resultAccumulator) {
+	runID := options.auditRunID()
+
+	var batches []auditBatch
+	for region, regionENIs := range enisByRegion {
+		var pending []OrphanedENI
+		for _, eni := range regionENIs {
+			if options.DryRun {
+				logging.V(5).Infof("[DRY RUN] Would tag ENI %s in region %s for audit run %s", eni.ID, region, runID)
+				results.skip()
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped})
+				continue
+			}
+			if eni.Tags[AuditTagKey] == runID {
+				logging.V(9).Infof("ENI %s is already tagged for audit run %s", eni.ID, runID)
+				results.skip()
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped})
+				continue
+			}
+			pending = append(pending, eni)
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		client, err := regionEC2Client(ctx, region, options.ConfigOptions, options.credentials, options.RecordAPIPath, options.ReplayAPIPath, options.ClientFactory)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error tagging ENIs in region %s: %v", region, err)
+			results.fail(len(pending), errMsg)
+			for _, eni := range pending {
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+			}
+			continue
+		}
+
+		for start := 0; start < len(pending); start += maxTagResources {
+			end := min(start+maxTagResources, len(pending))
+			batches = append(batches, auditBatch{client: client, enis: pending[start:end]})
+		}
+	}
+
+	sem := make(chan struct{}, options.tagConcurrency())
+	var wg sync.WaitGroup
+	for _, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			tagAuditBatch(ctx, batch, runID, results)
+		}()
+	}
+	wg.Wait()
+}
+
+// tagAuditBatch tags a batch of ENIs with a single CreateTags call
+func tagAuditBatch(ctx context.Context, batch auditBatch, runID string, results *resultAccumulator) {
+	ids := make([]string, 0, len(batch.enis))
+	for _, eni := range batch.enis {
+		ids = append(ids, eni.ID)
+	}
+
+	_, err := batch.client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: ids,
+		Tags: []types.Tag{
+			{
+				Key:   aws.String(AuditTagKey),
+				Value: aws.String(runID),
+			},
+		},
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to tag %d ENIs for audit: %v", len(ids), err)
+		results.fail(len(batch.enis), errMsg)
+		for _, eni := range batch.enis {
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+		}
+		return
+	}
+
+	for _, eni := range batch.enis {
+		results.clean(CleanedENI{
+			ID:          eni.ID,
+			Region:      eni.Region,
+			VpcID:       eni.VPCID,
+			Description: eni.Description,
+			ActionTaken: auditActionTagged,
+			Reason:      eni.Reason,
+		})
+		results.report(ENIResult{ENI: eni, Action: auditActionTagged, Outcome: OutcomeCleaned})
+	}
+}
+
+// regionEC2Client creates an EC2 client for a region, recording or replaying
+// its calls if configured
+func regionEC2Client(ctx context.Context, region string, source ConfigOptions, credentials aws.CredentialsProvider, recordPath, replayPath *string, factory ClientFactory) (ENIClient, error) {
+	cfg, err := loadConfig(ctx, region, source, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+
+	captureOptions, err := apiCaptureOptions(recordPath, replayPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.newClient(cfg, source, captureOptions...), nil
+}
@@ -0,0 +1,39 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestTagOnlyBatchesAndSkipsTaggedENIs(t *testing.T) {
+	fake := newFakeEC2()
+	useFakeEC2(t, fake)
+
+	var enis []OrphanedENI
+	for i := 0; i < 2500; i++ {
+		enis = append(enis, OrphanedENI{ID: fmt.Sprintf("eni-%d", i), Region: "us-east-1"})
+	}
+	enis = append(enis, OrphanedENI{ID: "eni-west", Region: "us-west-2"})
+	enis = append(enis, OrphanedENI{ID: "eni-tagged", Region: "us-east-1", Tags: map[string]string{AuditTagKey: "run-1"}})
+
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		TagOnly:        true,
+		AuditRunID:     "run-1",
+		TagConcurrency: 2,
+	})
+
+	if result.SuccessCount != 2501 || result.SkippedCount != 1 || result.FailureCount != 0 {
+		t.Errorf("expected 2501 tagged and 1 skipped, got %d/%d/%d", result.SuccessCount, result.SkippedCount, result.FailureCount)
+	}
+	// 2500 ENIs in us-east-1 take three batches, plus one for us-west-2
+	if fake.createTagsCalls != 4 {
+		t.Errorf("expected 4 CreateTags calls, got %d", fake.createTagsCalls)
+	}
+	if fake.tags["eni-2499"][AuditTagKey] != "run-1" {
+		t.Errorf("expected eni-2499 to be tagged, got %v", fake.tags["eni-2499"])
+	}
+	if len(fake.modified) != 0 || len(fake.deleted) != 0 {
+		t.Error("expected tag-only mode not to modify or delete ENIs")
+	}
+}
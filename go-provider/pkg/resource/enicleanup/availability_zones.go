@@ -0,0 +1,57 @@
+package enicleanup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// availabilityZoneFilter is the describe filter AvailabilityZones is passed as
+const availabilityZoneFilter = "availability-zone"
+
+// zoneInRegion reports whether an availability zone name, such as
+// "us-east-1a" or the Local Zone "us-east-1-bos-1a", belongs to a region
+func zoneInRegion(zone string, region string) bool {
+	suffix, ok := strings.CutPrefix(zone, region)
+	return ok && suffix != "" && !unicode.IsDigit(rune(suffix[0]))
+}
+
+// validateAvailabilityZones checks that every zone in AvailabilityZones
+// belongs to one of the regions being scanned, so a typo or a zone of an
+// unscanned region doesn't silently match nothing
+func validateAvailabilityZones(options DetectOptions, regions []string) error {
+	var unknown []string
+	for _, zone := range options.AvailabilityZones {
+		if !slices.ContainsFunc(regions, func(region string) bool { return zoneInRegion(zone, region) }) {
+			unknown = append(unknown, zone)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("availabilityZones has zones outside the regions being scanned: %v", unknown)
+	}
+	return nil
+}
+
+// inAvailabilityZones reports whether an ENI is in one of the zones
+// AvailabilityZones selects, or true when it selects none
+func (o DetectOptions) inAvailabilityZones(eni types.NetworkInterface) bool {
+	return len(o.AvailabilityZones) == 0 || slices.Contains(o.AvailabilityZones, aws.ToString(eni.AvailabilityZone))
+}
+
+// zoneScope returns the zones an availability-zone filter among filters
+// allows, and the filters without it. ok is false if there is no such filter.
+func zoneScope(filters []types.Filter) (zones []string, rest []types.Filter, ok bool) {
+	for _, filter := range filters {
+		if aws.ToString(filter.Name) == availabilityZoneFilter {
+			zones = filter.Values
+			ok = true
+			continue
+		}
+		rest = append(rest, filter)
+	}
+	return zones, rest, ok
+}
@@ -2,17 +2,45 @@ package enicleanup
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// DetachedAtTagKey is the tag recording when this tool force-detached an ENI
+const DetachedAtTagKey = "eni-cleanup:detached-at"
+
+// StackTagKey and ProjectTagKey record which Pulumi stack's cleanup touched an ENI
+const (
+	StackTagKey   = "eni-cleanup:stack"
+	ProjectTagKey = "eni-cleanup:project"
+)
+
+// DefaultDetachGracePeriod is how long ENIs recently detached by this tool are left alone
+const DefaultDetachGracePeriod = 15 * time.Minute
+
+// DefaultDetachWait is the longest cleanup waits for a detached ENI to become
+// available before deleting it, unless overridden by DetachTimeout or for its
+// interface type
+const DefaultDetachWait = 30 * time.Second
+
+// defaultDetachWaitByType holds built-in waits for interface types known to
+// release slowly after detachment
+var defaultDetachWaitByType = map[string]time.Duration{
+	"lambda": 5 * time.Minute,
+}
+
 // OrphanedENI represents a potentially orphaned ENI discovered during detection
 type OrphanedENI struct {
 	ID               string
@@ -21,372 +49,1721 @@ type OrphanedENI struct {
 	SubnetID         string
 	AvailabilityZone string
 	Description      string
+	InterfaceType    string
 	AttachmentState  string
 	CreatedTime      time.Time
 	Tags             map[string]string
 	AttachmentID     string
 	SecurityGroups   []string
+	OwnerID          string
+	// RequesterID is the principal or service that created the ENI, e.g.
+	// "amazon-elb", when it wasn't created by the owner directly
+	RequesterID string
+	// LoadBalancerARN is the deleted load balancer that created this ENI, if any
+	LoadBalancerARN string
+	// NATGatewayID is the deleted NAT gateway that left this ENI behind, if any
+	NATGatewayID string
+	// HasPublicIP is true when the ENI has a public IP or Elastic IP associated
+	HasPublicIP bool
+	// ElasticIPAllocationIDs are the Elastic IPs associated with any of the
+	// ENI's private IPs
+	ElasticIPAllocationIDs []string
+	// AddressAssociations are the Elastic IP associations on any of the
+	// ENI's private IPs, which HandleAssociations removes before deleting
+	AddressAssociations []AddressAssociation
+	// Reason classifies why the ENI was selected as a candidate, e.g. ReasonStale
+	Reason string
+	// MacAddress is the ENI's MAC address
+	MacAddress string
+	// PrivateIPAddress is the ENI's primary private IP address
+	PrivateIPAddress string
+	// DeleteOnTermination is true when AWS deletes the ENI along with the
+	// instance it is attached to
+	DeleteOnTermination bool
+	// AccountID is the account the ENI was found in by RunAccounts
+	AccountID string
+	// Fingerprint summarizes the ENI's status, attachment and security groups
+	// at detection, for CleanupOptions.ConfirmUnchanged
+	Fingerprint string
+	// Tier is the escalation tier DetectOptions.EscalationPolicy classified
+	// the ENI into, one of the Tier constants
+	Tier string
 }
 
 // DetectOptions contains options for the ENI detection process
 type DetectOptions struct {
 	SkipReservedDescriptions []string
-	IncludeTagKeys           []string
-	ExcludeTagKeys           []string
-	OlderThanDays            *float64
-	LogLevel                 string
-	SecurityGroupId          *string
+	// SkipRequesterIds lists requester IDs whose ENIs are always skipped, in
+	// addition to DefaultSkipRequesterIDs
+	SkipRequesterIds []string
+	IncludeTagKeys   []string
+	ExcludeTagKeys   []string
+	OlderThanDays    *float64
+	LogLevel         string
+	SecurityGroupId  *string
+	// SkipInterfaceTypes lists interface types whose ENIs are always skipped,
+	// in addition to DefaultSkipInterfaceTypes
+	SkipInterfaceTypes []string
+	// OnlyInterfaceTypes only considers ENIs of these interface types, e.g.
+	// "interface". Listing a type from DefaultSkipInterfaceTypes opts in to it.
+	OnlyInterfaceTypes []string
+	// CreatedTagKey names a tag holding the ENI's creation time in RFC3339 format.
+	// When set, ENIs whose tag is missing or unparseable are skipped and the
+	// CreatedAfter/CreatedBefore bounds are applied to the tag value.
+	CreatedTagKey *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// UseCloudTrailForAge takes the creation time of ENIs without a
+	// CreatedTagKey tag from their CreateNetworkInterface event in CloudTrail,
+	// so OlderThanDays, CreatedAfter and CreatedBefore work without creation
+	// tags. Adds a LookupEvents call per candidate, cached across runs. ENIs
+	// without an event are taken to be older than CloudTrail's 90 days.
+	UseCloudTrailForAge bool
+	// DetachGracePeriod skips ENIs carrying a DetachedAtTagKey tag newer than
+	// this duration. Zero disables the cooldown.
+	DetachGracePeriod time.Duration
+	// VerifyLoadBalancers checks ENIs created by Elastic Load Balancing against the
+	// ELBv2 API and only treats them as orphaned once their load balancer is gone.
+	// When false, load balancer ENIs are always skipped.
+	VerifyLoadBalancers bool
+	// VerifyNATGateways looks up the NAT gateway of available ENIs described
+	// as "NAT Gateway" ENIs and treats them as orphaned, overriding the
+	// reserved description, once DescribeNatGateways confirms it is gone.
+	// When false, NAT gateway ENIs are always skipped.
+	VerifyNATGateways bool
+	// ExcludePublicIP skips ENIs with a public IP or Elastic IP associated
+	ExcludePublicIP bool
+	// OnlyPublicIP only considers ENIs with a public IP or Elastic IP associated
+	OnlyPublicIP bool
+	// NetworkInterfaceIds restricts detection to an allowlist of ENI IDs, such
+	// as the approved candidates from a plan file
+	NetworkInterfaceIds []string
+	// VpcIds and SubnetIds only consider ENIs in these VPCs and subnets.
+	// They are passed to the describe as filters, ANDed with each other and
+	// with SecurityGroupId.
+	VpcIds    []string
+	SubnetIds []string
+	// AvailabilityZones only considers ENIs in these zones, e.g. "us-east-1a".
+	// Each zone must belong to one of the regions scanned; regions without
+	// any of the zones find no ENIs.
+	AvailabilityZones []string
+	// IntraRegionParallelism partitions each region's scan by availability zone
+	// and describes up to this many zones concurrently. Zero or one scans the
+	// region with a single paginated describe.
+	IntraRegionParallelism int
+	// RegionConcurrency bounds the regions scanned at once; defaults to
+	// DefaultRegionConcurrency. A failed region doesn't stop the others.
+	RegionConcurrency int
+	// ExcludeCIDRs skips ENIs whose primary private IP is within any of these CIDR ranges
+	ExcludeCIDRs []string
+	// ReservedDescriptionPatterns are regular expressions matched against the
+	// ENI description, e.g. "(?i)^elasticache", skipping matching ENIs like
+	// SkipReservedDescriptions does for substrings
+	ReservedDescriptionPatterns []string
+	// ExcludeMacPrefixes skips ENIs whose MAC address starts with any of these
+	// prefixes, e.g. "0a:1b:2c". Case and separators are ignored.
+	ExcludeMacPrefixes []string
+	// IncludeDeleteOnTermination also considers ENIs whose attachment has
+	// DeleteOnTermination set. By default they are skipped, since AWS deletes
+	// them itself when the instance terminates.
+	IncludeDeleteOnTermination bool
+	// IncludeSharedSubnets also considers ENIs in RAM-shared subnets owned by
+	// another account than the ENI. By default they are skipped, since
+	// deleting them can disrupt the other account of the share.
+	IncludeSharedSubnets bool
+	// IncludeRequesterManaged also considers ENIs with RequesterManaged set.
+	// By default they are skipped, since AWS manages them and refuses to
+	// delete them, unless their interface type is one OnlyInterfaceTypes or
+	// the detection profile asks for.
+	IncludeRequesterManaged bool
+	// EscalationPolicy, if set, classifies each candidate into an escalation
+	// tier, recorded in OrphanedENI.Tier. Pass the same policy to
+	// CleanupOptions.EscalationPolicy to act on the tiers. Candidates
+	// selected by PolicyFile aren't classified.
+	EscalationPolicy *EscalationPolicy
+	// PolicyFile is a Rego policy whose eni_cleanup.decision rule returns
+	// {"allow": bool, "reason": string} for each ENI. When set, its decisions
+	// replace the built-in filters, evaluated with the opa CLI.
+	PolicyFile *string
+	// IncludeTrunkBranchENIs also considers the trunk and branch ENIs the VPC
+	// CNI manages for EKS security groups for pods. By default they are
+	// skipped; when included, cleanup removes branches before their trunk.
+	IncludeTrunkBranchENIs bool
+	// RecordAPIPath appends every EC2 request and response to this file, one
+	// JSON object per line, with account IDs redacted, for diagnosing a run
+	RecordAPIPath *string
+	// ReplayAPIPath answers EC2 calls from a file written by RecordAPIPath
+	// instead of calling AWS
+	ReplayAPIPath *string
+	// DeletedENIs excludes ENIs already deleted during the run that a describe
+	// may still return
+	DeletedENIs *DeletedENIs
+	// OnRegionComplete, if set, is called as each region finishes scanning, for
+	// incremental results rather than waiting for the whole run. Calls are
+	// serialized, never concurrent.
+	OnRegionComplete func(region string, result RegionResult)
+	// PerRegionOptions overrides the options for specific regions, e.g. to
+	// target a different security group in each region. Fields set in a
+	// region's entry replace the base values when that region is scanned.
+	PerRegionOptions map[string]DetectOptions
+	// RegionGroups defines region groups usable in the regions list, e.g.
+	// {"core": {"us-east-1", "eu-west-1"}}. Entries extend DefaultRegionGroups
+	// and replace built-in groups of the same name.
+	RegionGroups map[string][]string
+	// RespectRecentDeploys skips ENIs in VPCs whose DeployTimestampTagKey tag
+	// is within RecentDeployWindow, so cleanup doesn't interfere with
+	// in-progress deployments. Costs an extra DescribeVpcs lookup per region.
+	RespectRecentDeploys bool
+	// DeployTimestampTagKey is the VPC tag holding the latest deployment time;
+	// defaults to DefaultDeployTimestampTagKey
+	DeployTimestampTagKey string
+	// RecentDeployWindow is the quiet period after a deployment; defaults to
+	// DefaultRecentDeployWindow
+	RecentDeployWindow time.Duration
+	// VerifyAttachmentOwners looks up the instances ENIs are attached to and
+	// classifies ENIs whose instance no longer exists or has terminated as
+	// ReasonDeadAttachmentOwner. Costs DescribeInstances lookups per region.
+	VerifyAttachmentOwners bool
+	// MaxVPCDeletionRatio withholds every candidate in a VPC when more than
+	// this fraction of the VPC's ENIs would be cleaned up, e.g. 0.5, to catch
+	// filters that accidentally match everything. 0 disables the guard.
+	MaxVPCDeletionRatio float64
+	// VPCDeletionRatioOverrides lists VPC IDs exempt from MaxVPCDeletionRatio,
+	// for explicitly approving a large cleanup
+	VPCDeletionRatioOverrides []string
+	// DescribeBatchSize is the number of ENIs requested per
+	// DescribeNetworkInterfaces page, to tune memory use and fetch
+	// granularity. Values outside EC2's range of 5 to 1000 are clamped with a
+	// warning. Zero uses EC2's default page size.
+	DescribeBatchSize int
+	// EnrichmentConcurrency bounds the lookups made concurrently per region
+	// after the initial describe, such as RespectRecentDeploys and
+	// VerifyAttachmentOwners; defaults to DefaultEnrichmentConcurrency
+	EnrichmentConcurrency int
+	// DetectionProfile selects a named profile from DetectionProfiles or
+	// DefaultDetectionProfiles. Only ENIs matching the profile are
+	// candidates, in addition to the other filters.
+	DetectionProfile string
+	// DetectionProfiles defines profiles selectable by DetectionProfile.
+	// Entries extend DefaultDetectionProfiles and replace built-in profiles
+	// of the same name.
+	DetectionProfiles map[string]DetectionProfile
+	// DecisionLog logs one line per spared ENI at info level, independent of
+	// LogLevel, in the form "<eni-id> <region> skip <reason>". Candidates
+	// get their line from CleanupOptions.DecisionLog.
+	DecisionLog bool
+	// ClientFactory creates the EC2 client for each region, for injecting a
+	// stub ENIClient; defaults to ec2.NewFromConfig
+	ClientFactory ClientFactory
+	// RoleOptions assume a role in place of the default credentials
+	RoleOptions
+	// ConfigOptions select the AWS profile and endpoint in place of the
+	// default config
+	ConfigOptions
+
+	// credentials replace the default credential chain; RunAccounts sets
+	// them to the role assumed in each account
+	credentials aws.CredentialsProvider
+}
+
+// RegionResult is the outcome of scanning a single region
+type RegionResult struct {
+	// ENIs are the orphaned ENIs detected in the region
+	ENIs []OrphanedENI
+	// Skipped are the ENIs in the region that were deliberately spared
+	Skipped []SkippedENI
+	// AbortedVPCs are the VPCs whose candidates were withheld by MaxVPCDeletionRatio
+	AbortedVPCs []AbortedVPC
+	// Err is set if the region could not be scanned; its ENIs are then omitted from the run
+	Err error
+}
+
+// RegionError is a region that could not be scanned
+type RegionError struct {
+	// Region is the region whose scan failed
+	Region string
+	// AccountID is the account scanned, when scanning several accounts
+	AccountID string
+	// Err is why the scan failed
+	Err error
+}
+
+// Error implements error
+func (e RegionError) Error() string {
+	if e.AccountID != "" {
+		return fmt.Sprintf("AWS account %s region %s: %v", e.AccountID, e.Region, e.Err)
+	}
+	return fmt.Sprintf("region %s: %v", e.Region, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e RegionError) Unwrap() error {
+	return e.Err
 }
 
 // CleanupResult captures the results of the cleanup operation
 type CleanupResult struct {
-	SuccessCount int
-	FailureCount int
-	SkippedCount int
-	CleanedENIs  []CleanedENI
-	Errors       []string
+	SuccessCount int          `json:"successCount"`
+	FailureCount int          `json:"failureCount"`
+	SkippedCount int          `json:"skippedCount"`
+	CleanedENIs  []CleanedENI `json:"cleanedEnis"`
+	Errors       []string     `json:"errors"`
+	// SkippedDetails lists ENIs detection deliberately spared. Cleanup doesn't
+	// fill it; callers copy DetectResult.Skipped into it.
+	SkippedDetails []SkippedENI `json:"skippedDetails,omitempty"`
+	// VerificationFailed lists deleted ENIs that VerifyDeletion found still exist
+	VerificationFailed []string `json:"verificationFailed,omitempty"`
+	// DeadLetter lists ENIs that failed cleanup and could not even be tagged
+	// for manual cleanup, with every error encountered. Nothing in AWS
+	// records these failures, so they need escalating.
+	DeadLetter []CleanedENI `json:"deadLetter,omitempty"`
+	// ManualCleanup lists the ENIs tagged NeedsManualCleanup because a
+	// cleanup step failed
+	ManualCleanup []string `json:"manualCleanup,omitempty"`
+	// Truncated is set when the run stopped, for example on cancellation,
+	// before processing every candidate
+	Truncated bool `json:"truncated,omitempty"`
+	// FailedAccounts lists the accounts RunAccounts couldn't scan
+	FailedAccounts []string `json:"failedAccounts,omitempty"`
+	// RegionTimings is how long cleanup took in each region
+	RegionTimings map[string]time.Duration `json:"regionTimings,omitempty"`
+	// ReconciledENIs lists the ENIs ReconcileTags removed stale manual
+	// cleanup tags from
+	ReconciledENIs []string `json:"reconciledEnis,omitempty"`
+}
+
+// CleanupOptions contains options for the ENI cleanup process
+type CleanupOptions struct {
+	DryRun                 bool
+	DisassociateOnly       bool
+	DefaultSecurityGroupId *string
+	TargetSecurityGroupId  *string
+	// RequireDefaultOnEmpty prevents auto-discovery of the VPC's default security
+	// group when removing groups would leave an ENI with none. An explicit
+	// DefaultSecurityGroupId must be supplied instead.
+	RequireDefaultOnEmpty bool
+	// TagWithStackInfo tags every ENI the cleanup acts on with the Pulumi stack
+	// and project names before modifying it, for auditing in shared accounts
+	TagWithStackInfo bool
+	StackName        string
+	ProjectName      string
+	// DetachWaitByType caps how long to wait for a detached ENI to become
+	// available before deleting it, keyed by interface type (e.g. "lambda").
+	// Types without an entry use DetachTimeout.
+	DetachWaitByType map[string]time.Duration
+	// DetachTimeout is how long to wait for a detached ENI to become
+	// available before deleting it; defaults to DefaultDetachWait. An ENI
+	// still not available is tagged for manual cleanup and counted as a
+	// failure instead of being deleted.
+	DetachTimeout time.Duration
+	// DeletionStrikesRequired stages cleanup across runs. Each run that finds an
+	// ENI orphaned increments its StrikesTagKey tag, and the ENI is only acted on
+	// once the count reaches this threshold. Values of one or less act immediately.
+	DeletionStrikesRequired int
+	// OnENIResult, if set, is called as soon as each ENI has been processed,
+	// for streaming progress rather than waiting for the CleanupResult
+	OnENIResult func(ENIResult)
+	// DeletedENIs records the ENIs deleted by cleanup, and ENIs it already
+	// contains are ignored. Share it with DetectOptions.DeletedENIs to exclude
+	// them from later scans in the same run. A tracker scoped to the call is
+	// used when nil.
+	DeletedENIs *DeletedENIs
+	// RecordAPIPath and ReplayAPIPath record or replay the EC2 calls made
+	// during cleanup, as for DetectOptions
+	RecordAPIPath *string
+	ReplayAPIPath *string
+	// RestoreSecurityGroupTagKey names the tag holding the security group to
+	// assign when an ENI's groups are removed, in place of the default group.
+	// Defaults to DefaultRestoreSecurityGroupTagKey.
+	RestoreSecurityGroupTagKey string
+	// ManualCleanupTagKey, AttemptedCleanupTimeTagKey and DeletionErrorTagKey
+	// name the tags written to ENIs whose cleanup failed, for orgs whose tag
+	// governance rejects the defaults. The error tag's value is sanitized to
+	// the characters and length AWS allows.
+	ManualCleanupTagKey        string
+	AttemptedCleanupTimeTagKey string
+	DeletionErrorTagKey        string
+	// OmitDeletionErrorTag leaves the error out of the manual cleanup tags
+	OmitDeletionErrorTag bool
+	// ReconcileTags removes the manual cleanup tags from ENIs that no longer
+	// match the orphan criteria; see ReconcileManualCleanupTags
+	ReconcileTags bool
+	// ConfirmUnchanged re-describes each ENI just before acting on it and
+	// skips it, with SkipReasonChangedSinceDetection, if its status,
+	// attachment or security groups changed since detection
+	ConfirmUnchanged bool
+	// TagOnly tags candidate ENIs with AuditTagKey instead of modifying them,
+	// for rolling out cleanup in audit mode. ENIs already tagged with the
+	// current AuditRunID are skipped.
+	TagOnly bool
+	// AuditRunID is the AuditTagKey value for TagOnly; defaults to the current UTC date
+	AuditRunID string
+	// TagConcurrency bounds the concurrent CreateTags calls in TagOnly mode;
+	// defaults to DefaultTagConcurrency
+	TagConcurrency int
+	// EscalationPolicy, if set, decides per ENI by its Tier whether it is
+	// only reported, disassociated or deleted. DisassociateOnly still applies
+	// to every ENI.
+	EscalationPolicy *EscalationPolicy
+	// MaxDeletions caps the ENIs deleted in a run as a guardrail against a
+	// filter that matches too much. Once reached, the remaining candidates
+	// are skipped and Errors explains why. Nil means no limit.
+	MaxDeletions *int
+	// DeleteConcurrency is how many ENIs are cleaned up concurrently per
+	// region; defaults to DefaultDeleteConcurrency. ENIs sharing Elastic IP
+	// associations are always cleaned up one after another, see associationGroups.
+	DeleteConcurrency int
+	// DeleteAttempts is how many times deleting an ENI is attempted before it
+	// is tagged for manual cleanup; defaults to DefaultDeleteAttempts
+	DeleteAttempts int
+	// DeleteRetryBaseDelay is the wait before the first delete retry, doubling
+	// with each further retry; defaults to DefaultDeleteRetryBaseDelay
+	DeleteRetryBaseDelay time.Duration
+	// VerifyDeletion re-describes deleted ENIs after each region's cleanup,
+	// retrying briefly for eventual consistency, and reports any that still
+	// exist in VerificationFailed
+	VerifyDeletion bool
+	// EventBusName, if set, receives a CleanupCompleted event from EventSource
+	// carrying the run summary once cleanup finishes. Publishing failures are
+	// logged and don't affect the result.
+	EventBusName *string
+	// PerENITimeout bounds each ENI's modify, detach, wait and delete
+	// sequence. An ENI that exceeds it is tagged for manual cleanup and
+	// counted as a failure, and cleanup moves on. Zero means no limit.
+	PerENITimeout time.Duration
+	// DecisionLog logs one line per processed ENI at info level, independent
+	// of the verbosity, in the form "<eni-id> <region> <decision> <reason>",
+	// where the decision is one of the Decision constants
+	DecisionLog bool
+	// HandleAssociations disassociates an ENI's Elastic IPs before deleting
+	// it, since the delete fails while an address is associated. The
+	// addresses are kept, and reported in CleanedENI.DisassociatedAddresses.
+	HandleAssociations bool
+	// RequireEmptyVPC only cleans up ENIs in VPCs without any instances that
+	// haven't terminated, for reaping ENIs after an environment is torn down
+	// without touching active VPCs. ENIs in occupied VPCs are skipped.
+	RequireEmptyVPC bool
+	// LockTableName, if set, names a DynamoDB table holding an advisory lock
+	// so only one cleanup run per account-region proceeds at a time, keeping
+	// stacks destroyed together from cleaning up the same ENIs. The table's
+	// partition key must be the string LockId. Regions whose lock is held by
+	// another run are skipped. Not taken for dry runs.
+	LockTableName *string
+	// LockTTL is how long a lock is held before another run may take it over,
+	// so a crashed run doesn't block cleanup; defaults to DefaultLockTTL.
+	// It should exceed the time a region's cleanup takes.
+	LockTTL time.Duration
+	// LockWaitTimeout is how long to wait for a held lock before skipping
+	// the region. Zero skips immediately.
+	LockWaitTimeout time.Duration
+	// ClientFactory creates the EC2 client for each region, as for DetectOptions
+	ClientFactory ClientFactory
+	// RoleOptions assume a role in place of the default credentials, as for
+	// DetectOptions
+	RoleOptions
+	// ConfigOptions select the AWS profile and endpoint, as for DetectOptions
+	ConfigOptions
+
+	// credentials replace the default credential chain, as for DetectOptions
+	credentials aws.CredentialsProvider
+}
+
+// restoreSecurityGroupTagKey returns the configured restore tag key or the default
+func (o CleanupOptions) restoreSecurityGroupTagKey() string {
+	if o.RestoreSecurityGroupTagKey != "" {
+		return o.RestoreSecurityGroupTagKey
+	}
+	return DefaultRestoreSecurityGroupTagKey
+}
+
+// ENIResult is the outcome of processing a single ENI during cleanup
+type ENIResult struct {
+	ENI OrphanedENI
+	// Action describes what was done, e.g. "deleted"; empty if nothing was done
+	Action string
+	// Outcome is OutcomeCleaned, OutcomeFailed or OutcomeSkipped
+	Outcome string
+	// Reason explains a skipped outcome, e.g. SkipReasonChangedSinceDetection
+	Reason string
+	// Error explains a failed outcome
+	Error string
+}
+
+// Cleanup outcomes reported for each ENI
+const (
+	OutcomeCleaned = "cleaned"
+	OutcomeFailed  = "failed"
+	OutcomeSkipped = "skipped"
+)
+
+// DetectResult is the outcome of detection across all regions
+type DetectResult struct {
+	// ENIs are the detected orphaned ENIs
+	ENIs []OrphanedENI
+	// Skipped are the ENIs that were deliberately spared, with the reason why
+	Skipped []SkippedENI
+	// AbortedVPCs are the VPCs whose candidates were withheld by MaxVPCDeletionRatio
+	AbortedVPCs []AbortedVPC
+	// RegionTimings is how long the scan of each region took, including
+	// regions whose scan failed
+	RegionTimings map[string]time.Duration
+	// Errors are the regions that could not be scanned, whose ENIs are
+	// missing from the result
+	Errors []RegionError
+
+	// scanned maps each region scanned successfully to its scan filters
+	scanned map[string][]types.Filter
 }
 
 // DetectOrphanedENIs detects orphaned ENIs across all specified regions
 func DetectOrphanedENIs(ctx context.Context, regions []string, options DetectOptions) ([]OrphanedENI, error) {
+	result, err := DetectOrphanedENIsWithDetails(ctx, regions, options)
+	return result.ENIs, err
+}
+
+// DetectOrphanedENIsWithDetails detects orphaned ENIs across all specified
+// regions and also reports the ENIs that were deliberately spared. Regions
+// that fail to scan are listed in Errors alongside the others' results; if
+// every region fails, an error is returned instead.
+func DetectOrphanedENIsWithDetails(ctx context.Context, regions []string, options DetectOptions) (DetectResult, error) {
+	if err := options.Validate(); err != nil {
+		return DetectResult{}, fmt.Errorf("invalid detect options: %w", err)
+	}
+
+	ctx, span := startSpan(ctx, "DetectOrphanedENIs")
+	defer span.End()
+
+	// Assume RoleArn once for every region; RunAccounts has already assumed
+	// each account's role
+	if options.credentials == nil {
+		credentials, err := options.assume(ctx, options.ConfigOptions)
+		if err != nil {
+			span.RecordError(err)
+			return DetectResult{}, err
+		}
+		options.credentials = credentials
+	}
+
+	// Expand region groups such as "eu" and sentinels such as "all-enabled"
+	regions, err := expandRegions(ctx, regions, options.RegionGroups, options.ConfigOptions, options.credentials)
+	if err != nil {
+		span.RecordError(err)
+		return DetectResult{}, err
+	}
+	span.SetAttributes(attribute.StringSlice(attrRegions, regions))
+
+	if err := validatePerRegionOptions(options, regions); err != nil {
+		span.RecordError(err)
+		return DetectResult{}, fmt.Errorf("invalid detect options: %w", err)
+	}
+	if err := validateAvailabilityZones(options, regions); err != nil {
+		span.RecordError(err)
+		return DetectResult{}, fmt.Errorf("invalid detect options: %w", err)
+	}
+
+	// Each region's result lands at its index, so merging doesn't depend on
+	// which scan finishes first
+	regionResults := make([]DetectResult, len(regions))
+	regionErrs := make([]*RegionError, len(regions))
+	regionTimings := make(map[string]time.Duration, len(regions))
+	scanned := make(map[string][]types.Filter, len(regions))
+	var mu sync.Mutex
+
+	var callbackMu sync.Mutex
+	regionComplete := func(region string, result RegionResult) {
+		if options.OnRegionComplete == nil {
+			return
+		}
+		callbackMu.Lock()
+		defer callbackMu.Unlock()
+		options.OnRegionComplete(region, result)
+	}
+
+	// Scan up to RegionConcurrency regions at once
+	tasks := make([]func(context.Context) error, len(regions))
+	for i, region := range regions {
+		tasks[i] = func(ctx context.Context) error {
+			regionCtx, regionSpan := startSpan(ctx, "ScanRegion", attribute.String(attrRegion, region))
+			regionOptions := options.forRegion(region)
+
+			// Default reserved descriptions to skip, plus user-specified ones
+			reservedDescriptions := []string{
+				"ELB", "Amazon EKS", "AWS-mgmt", "NAT Gateway", "Kubernetes.io",
+			}
+			reservedDescriptions = append(reservedDescriptions, regionOptions.SkipReservedDescriptions...)
+			regionOptions.SkipRequesterIds = append(append([]string{}, DefaultSkipRequesterIDs...), regionOptions.SkipRequesterIds...)
+
+			start := time.Now()
+			regionResult, err := detectRegion(regionCtx, region, regionOptions, reservedDescriptions)
+			elapsed := time.Since(start)
+			regionResult.Err = err
+			regionSpan.SetAttributes(attribute.Int(attrCandidates, len(regionResult.ENIs)))
+			endSpan(regionSpan, err)
+			regionComplete(region, regionResult)
+
+			mu.Lock()
+			defer mu.Unlock()
+			regionTimings[region] = elapsed
+			if err != nil {
+				regionErrs[i] = &RegionError{Region: region, Err: err}
+				return nil
+			}
+			scanned[region] = regionOptions.scanFilters()
+			regionResults[i] = DetectResult{
+				ENIs:        regionResult.ENIs,
+				Skipped:     regionResult.Skipped,
+				AbortedVPCs: regionResult.AbortedVPCs,
+			}
+			return nil
+		}
+	}
+	runConcurrently(ctx, options.regionConcurrency(), tasks)
+
+	// A failed region leaves the others' results intact, unless none succeeded
+	var failed []RegionError
+	var errs []error
+	for _, regionErr := range regionErrs {
+		if regionErr != nil {
+			failed = append(failed, *regionErr)
+			errs = append(errs, *regionErr)
+		}
+	}
+	if len(failed) > 0 && len(failed) == len(regions) {
+		err := fmt.Errorf("failed to scan every region: %w", errors.Join(errs...))
+		span.RecordError(err)
+		return DetectResult{RegionTimings: regionTimings, Errors: failed}, err
+	}
+	if len(failed) > 0 {
+		logging.Warningf("Some regions could not be scanned:\n%v", errors.Join(errs...))
+	}
+
+	// The same ENI can be seen by more than one region's scan; report it once
+	result := mergeDetectResults(regionResults)
+	result.RegionTimings = regionTimings
+	result.scanned = scanned
+	result.Errors = failed
+	sortOrphanedENIs(result.ENIs)
+	if options.DecisionLog {
+		logSkippedDecisions(result.Skipped)
+	}
+
+	span.SetAttributes(attribute.Int(attrCandidates, len(result.ENIs)))
+
+	return result, nil
+}
+
+// scanFilters returns the describe filters limiting which ENIs are scanned
+func (o DetectOptions) scanFilters() []types.Filter {
+	var filters []types.Filter
+
+	// If a security group ID is specified, filter by that
+	if o.SecurityGroupId != nil && *o.SecurityGroupId != "" {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("group-id"),
+			Values: []string{*o.SecurityGroupId},
+		})
+	}
+
+	// If an allowlist of ENI IDs is specified, only consider those
+	if len(o.NetworkInterfaceIds) > 0 {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("network-interface-id"),
+			Values: o.NetworkInterfaceIds,
+		})
+	}
+
+	// Scope the scan to the given VPCs and subnets
+	if len(o.VpcIds) > 0 {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("vpc-id"),
+			Values: o.VpcIds,
+		})
+	}
+	if len(o.SubnetIds) > 0 {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("subnet-id"),
+			Values: o.SubnetIds,
+		})
+	}
+	if len(o.AvailabilityZones) > 0 {
+		filters = append(filters, types.Filter{
+			Name:   aws.String(availabilityZoneFilter),
+			Values: o.AvailabilityZones,
+		})
+	}
+
+	return filters
+}
+
+// detectRegion detects orphaned ENIs in a single region
+func detectRegion(ctx context.Context, region string, options DetectOptions, reservedDescriptions []string) (RegionResult, error) {
 	var orphanedENIs []OrphanedENI
+	var skipped []SkippedENI
+	spare := func(eni types.NetworkInterface, reason string, detail string) {
+		skipped = append(skipped, SkippedENI{
+			ID:     aws.ToString(eni.NetworkInterfaceId),
+			Region: region,
+			Reason: reason,
+			Detail: detail,
+		})
+	}
+
+	// CIDRs are checked by Validate, so parsing can't fail here
+	excludedCIDRs, _ := parseCIDRs(options.ExcludeCIDRs)
+	descriptionPatterns, _ := compileDescriptionPatterns(options.ReservedDescriptionPatterns)
+
+	// The profile is checked by Validate too
+	profile, hasProfile := options.detectionProfile()
 
-	// Default reserved descriptions to skip
-	reservedDescriptions := []string{
-		"ELB", "Amazon EKS", "AWS-mgmt", "NAT Gateway", "Kubernetes.io",
+	// Candidates whose creation time is looked up in CloudTrail, by index
+	var ageLookups []int
+
+	// Create AWS config for this region
+	cfg, err := loadConfig(ctx, region, options.ConfigOptions, options.credentials)
+	if err != nil {
+		return RegionResult{}, fmt.Errorf("error loading AWS config: %w", err)
 	}
 
-	// Add user-specified reserved descriptions
-	reservedDescriptions = append(reservedDescriptions, options.SkipReservedDescriptions...)
+	// Create EC2 client, recording or replaying its calls if configured
+	captureOptions, err := apiCaptureOptions(options.RecordAPIPath, options.ReplayAPIPath)
+	if err != nil {
+		return RegionResult{}, err
+	}
+	ec2Client := options.ClientFactory.newClient(cfg, options.ConfigOptions, captureOptions...)
+
+	// Find all ENIs, not just available ones
+	filters := options.scanFilters()
+	enis, err := scanRegion(ctx, ec2Client, filters, options.IntraRegionParallelism, options.describeBatchSize())
+	if err != nil {
+		return RegionResult{}, fmt.Errorf("error finding ENIs: %w", err)
+	}
 
-	// Process each region
-	for _, region := range regions {
-		// Create AWS config for this region
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if options.PolicyFile != nil && *options.PolicyFile != "" {
+		orphanedENIs, skipped, err = detectByPolicy(ctx, region, enis, options)
 		if err != nil {
-			logging.V(5).Infof("Error loading AWS config for region %s: %v", region, err)
+			return RegionResult{}, err
+		}
+		return guardVPCDeletionRatio(ctx, ec2Client, region, enis, len(filters) > 0, RegionResult{ENIs: orphanedENIs, Skipped: skipped}, options)
+	}
+
+	// Look up what the filters need beyond the describe
+	enrichment, err := enrichRegion(ctx, ec2Client, enis, options)
+	if err != nil {
+		return RegionResult{}, err
+	}
+
+	// Load balancers are listed lazily, only if a load balancer ENI needs verifying
+	lbLookup := newLoadBalancerLookup(elasticloadbalancingv2.NewFromConfig(cfg))
+
+	// Filter the ENIs to find orphaned ones
+	for _, eni := range enis {
+		// Ignore stale results for ENIs this run already deleted
+		if options.DeletedENIs.Contains(aws.ToString(eni.NetworkInterfaceId)) {
+			logging.V(9).Infof("Skipping ENI %s already deleted in this run", *eni.NetworkInterfaceId)
 			continue
 		}
 
-		// Create EC2 client
-		ec2Client := ec2.NewFromConfig(cfg)
+		// The describe filters by zone already; check again in case a
+		// replayed or custom client ignored the filter
+		if !options.inAvailabilityZones(eni) {
+			continue
+		}
 
-		// Find all ENIs, not just available ones
-		var filters []types.Filter
+		// Transit gateway and VPN ENIs are never candidates
+		if isTransitGatewayOrVPNENI(eni) {
+			attachment := vpnAttachmentID(eni)
+			if attachment == "" {
+				attachment = "unknown attachment"
+			}
+			logging.V(5).Infof("Skipping transit gateway/VPN ENI %s (%s)", *eni.NetworkInterfaceId, attachment)
+			spare(eni, SkipReasonTransitGatewayOrVPN, attachment)
+			continue
+		}
 
-		// If a security group ID is specified, filter by that
-		if options.SecurityGroupId != nil && *options.SecurityGroupId != "" {
-			filters = append(filters, types.Filter{
-				Name:   aws.String("group-id"),
-				Values: []string{*options.SecurityGroupId},
-			})
+		// Neither are GWLB endpoint or Global Accelerator ENIs
+		if reason, owner, ok := protectedEndpoint(eni); ok {
+			logging.V(5).Infof("Skipping %s ENI %s (%s)", reason, *eni.NetworkInterfaceId, owner)
+			spare(eni, reason, owner)
+			continue
 		}
 
-		enis, err := findNetworkInterfaces(ctx, ec2Client, filters)
-		if err != nil {
-			logging.V(5).Infof("Error finding ENIs in region %s: %v", region, err)
+		// Trunk and branch ENIs are managed by the VPC CNI unless opted in
+		if isTrunkOrBranchENI(eni) && !options.IncludeTrunkBranchENIs && !profile.IncludeTrunkBranchENIs {
+			logging.V(9).Infof("Skipping CNI-managed %s ENI %s", eni.InterfaceType, *eni.NetworkInterfaceId)
+			spare(eni, SkipReasonTrunkOrBranch, string(eni.InterfaceType))
 			continue
 		}
 
-		// Filter the ENIs to find orphaned ones
-		for _, eni := range enis {
-			// Skip ENIs with reserved descriptions
-			if eni.Description != nil {
-				shouldSkip := false
-				for _, reservedDesc := range reservedDescriptions {
-					if strings.Contains(*eni.Description, reservedDesc) {
-						shouldSkip = true
-						break
-					}
-				}
-				if shouldSkip {
-					logging.V(9).Infof("Skipping ENI %s with reserved description: %s", *eni.NetworkInterfaceId, *eni.Description)
-					continue
-				}
+		// ENIs in shared subnets can affect the other account of the share
+		if owner, shared := sharedSubnetOwner(eni, enrichment.subnetOwners); shared && !options.IncludeSharedSubnets {
+			logging.V(9).Infof("Skipping ENI %s in subnet %s owned by %s", *eni.NetworkInterfaceId, aws.ToString(eni.SubnetId), owner)
+			spare(eni, SkipReasonSharedSubnet, fmt.Sprintf("shared subnet, not owned: %s is owned by %s", aws.ToString(eni.SubnetId), owner))
+			continue
+		}
+
+		// Don't race AWS's own teardown of ENIs deleted with their instance
+		deleteOnTermination := eni.Attachment != nil && aws.ToBool(eni.Attachment.DeleteOnTermination)
+		if deleteOnTermination && !options.IncludeDeleteOnTermination {
+			logging.V(9).Infof("Skipping ENI %s: will auto-delete on termination", *eni.NetworkInterfaceId)
+			spare(eni, SkipReasonDeleteOnTermination, "will auto-delete on termination")
+			continue
+		}
+
+		// Load balancer ENIs are identified by type and requester, and only become
+		// candidates once the owning load balancer is confirmed to be gone
+		var loadBalancerARN string
+		if isLoadBalancerENI(eni) {
+			if !options.VerifyLoadBalancers {
+				logging.V(9).Infof("Skipping load balancer ENI %s", *eni.NetworkInterfaceId)
+				spare(eni, SkipReasonLoadBalancer, "load balancer verification disabled")
+				continue
 			}
 
-			// Extract tags
-			tags := make(map[string]string)
-			for _, tag := range eni.TagSet {
-				if tag.Key != nil && tag.Value != nil {
-					tags[*tag.Key] = *tag.Value
-				}
+			arn, exists, err := lbLookup.check(ctx, eni, region)
+			if err != nil {
+				logging.V(5).Infof("Skipping load balancer ENI %s that could not be verified: %v", *eni.NetworkInterfaceId, err)
+				spare(eni, SkipReasonLoadBalancer, err.Error())
+				continue
+			}
+			if exists {
+				logging.V(9).Infof("Skipping ENI %s owned by existing load balancer %s", *eni.NetworkInterfaceId, arn)
+				spare(eni, SkipReasonLoadBalancer, arn)
+				continue
 			}
+			logging.V(5).Infof("ENI %s belongs to deleted load balancer %s", *eni.NetworkInterfaceId, arn)
+			loadBalancerARN = arn
+		}
 
-			// Filter by include tag keys if specified
-			if len(options.IncludeTagKeys) > 0 {
-				hasIncludeTag := false
-				for _, includeKey := range options.IncludeTagKeys {
-					if _, ok := tags[includeKey]; ok {
-						hasIncludeTag = true
-						break
-					}
-				}
-				if !hasIncludeTag {
-					continue
+		// NAT gateways sometimes leave their ENI behind when deleted
+		natGatewayID, deadNAT := deadNATGateway(eni, enrichment.liveNATGateways, options)
+		if deadNAT {
+			logging.V(5).Infof("ENI %s belongs to deleted NAT gateway %s", *eni.NetworkInterfaceId, natGatewayID)
+		}
+
+		// Only consider the requested interface types
+		interfaceType := string(eni.InterfaceType)
+		if len(options.OnlyInterfaceTypes) > 0 && !slices.Contains(options.OnlyInterfaceTypes, interfaceType) {
+			continue
+		}
+
+		// Skip ENIs of AWS-managed interface types, unless they belong to a
+		// load balancer or NAT gateway confirmed to be deleted
+		if options.skipsInterfaceType(interfaceType, profile) && loadBalancerARN == "" && !deadNAT {
+			logging.V(9).Infof("Skipping ENI %s of managed interface type %s", *eni.NetworkInterfaceId, interfaceType)
+			spare(eni, SkipReasonInterfaceType, interfaceType)
+			continue
+		}
+
+		// Skip ENIs created by reserved requesters, unless they belong to a load
+		// balancer or NAT gateway confirmed to be deleted
+		if requester, reserved := reservedRequester(eni, options.SkipRequesterIds); reserved && loadBalancerARN == "" && !deadNAT {
+			logging.V(9).Infof("Skipping ENI %s with reserved requester: %s", *eni.NetworkInterfaceId, requester)
+			spare(eni, SkipReasonReservedRequester, requester)
+			continue
+		}
+
+		// Skip ENIs AWS manages on the requester's behalf, unless they belong
+		// to a load balancer or NAT gateway confirmed to be deleted
+		if options.skipsRequesterManaged(eni, profile) && loadBalancerARN == "" && !deadNAT {
+			logging.V(9).Infof("Skipping requester-managed ENI %s created by %s", *eni.NetworkInterfaceId, aws.ToString(eni.RequesterId))
+			spare(eni, SkipReasonRequesterManaged, aws.ToString(eni.RequesterId))
+			continue
+		}
+
+		// Fall back to reserved descriptions for ENIs whose requester doesn't
+		// identify the owning service
+		if eni.Description != nil && loadBalancerARN == "" && !deadNAT {
+			shouldSkip := false
+			for _, reservedDesc := range reservedDescriptions {
+				if strings.Contains(*eni.Description, reservedDesc) {
+					shouldSkip = true
+					break
 				}
 			}
+			if pattern, matched := matchingDescriptionPattern(*eni.Description, descriptionPatterns); matched && !shouldSkip {
+				logging.V(9).Infof("ENI %s description matches reserved pattern %s", *eni.NetworkInterfaceId, pattern)
+				shouldSkip = true
+			}
+			if shouldSkip {
+				logging.V(9).Infof("Skipping ENI %s with reserved description: %s", *eni.NetworkInterfaceId, *eni.Description)
+				spare(eni, SkipReasonReservedDescription, *eni.Description)
+				continue
+			}
+		}
 
-			// Filter by exclude tag keys if specified
-			if len(options.ExcludeTagKeys) > 0 {
-				hasExcludeTag := false
-				for _, excludeKey := range options.ExcludeTagKeys {
-					if _, ok := tags[excludeKey]; ok {
-						hasExcludeTag = true
-						break
-					}
-				}
-				if hasExcludeTag {
-					continue
+		// Skip ENIs whose primary private IP is in an excluded CIDR
+		if cidr, excluded := matchingCIDR(aws.ToString(eni.PrivateIpAddress), excludedCIDRs); excluded {
+			logging.V(9).Infof("Skipping ENI %s: in excluded CIDR %s", *eni.NetworkInterfaceId, cidr)
+			spare(eni, SkipReasonExcludedCIDR, cidr.String())
+			continue
+		}
+
+		// Skip ENIs whose MAC address matches a known infrastructure prefix
+		if prefix, excluded := matchingMACPrefix(aws.ToString(eni.MacAddress), options.ExcludeMacPrefixes); excluded {
+			logging.V(9).Infof("Skipping ENI %s: MAC address %s matches excluded prefix %s", *eni.NetworkInterfaceId, *eni.MacAddress, prefix)
+			spare(eni, SkipReasonExcludedMAC, prefix)
+			continue
+		}
+
+		// Filter by public IP association if specified
+		hasPublicIP := eni.Association != nil && aws.ToString(eni.Association.PublicIp) != ""
+		if options.ExcludePublicIP && hasPublicIP {
+			logging.V(9).Infof("Skipping ENI %s with public IP %s", *eni.NetworkInterfaceId, *eni.Association.PublicIp)
+			spare(eni, SkipReasonPublicIP, *eni.Association.PublicIp)
+			continue
+		}
+		if options.OnlyPublicIP && !hasPublicIP {
+			continue
+		}
+
+		tags := eniTags(eni)
+
+		// Skip ENIs this tool detached recently to avoid racing a reattachment
+		if recentlyDetached(tags, options.DetachGracePeriod) {
+			logging.V(9).Infof("Skipping ENI %s detached within the last %s", *eni.NetworkInterfaceId, options.DetachGracePeriod)
+			spare(eni, SkipReasonRecentlyDetached, tags[DetachedAtTagKey])
+			continue
+		}
+
+		// Skip ENIs in VPCs with a deployment in progress or just finished
+		if deployedAt, ok := enrichment.recentDeploys[aws.ToString(eni.VpcId)]; ok {
+			logging.V(9).Infof("Skipping ENI %s: VPC %s was deployed to at %s", *eni.NetworkInterfaceId, aws.ToString(eni.VpcId), deployedAt)
+			spare(eni, SkipReasonRecentDeploy, deployedAt)
+			continue
+		}
+
+		// Only ENIs matching the selected profile are candidates
+		if hasProfile && !profile.matches(eni, tags, options.CreatedTagKey) {
+			continue
+		}
+
+		// Filter by include tag keys if specified
+		if len(options.IncludeTagKeys) > 0 {
+			hasIncludeTag := false
+			for _, includeKey := range options.IncludeTagKeys {
+				if _, ok := tags[includeKey]; ok {
+					hasIncludeTag = true
+					break
 				}
 			}
-
-			// Filter by age if specified
-			// Note: AWS SDK v2 doesn't expose CreateTime directly in NetworkInterface
-			// Skip age filtering for now
-			if options.OlderThanDays != nil {
-				logging.V(9).Infof("Age filtering is not available in the current AWS SDK version")
+			if !hasIncludeTag {
+				continue
 			}
+		}
 
-			// Extract security groups
-			var securityGroups []string
-			for _, group := range eni.Groups {
-				if group.GroupId != nil {
-					securityGroups = append(securityGroups, *group.GroupId)
+		// Filter by exclude tag keys if specified
+		if len(options.ExcludeTagKeys) > 0 {
+			excludedBy := ""
+			for _, excludeKey := range options.ExcludeTagKeys {
+				if _, ok := tags[excludeKey]; ok {
+					excludedBy = excludeKey
+					break
 				}
 			}
-
-			// Create orphaned ENI entry
-			orphanedENI := OrphanedENI{
-				ID:             *eni.NetworkInterfaceId,
-				Region:         region,
-				Tags:           tags,
-				SecurityGroups: securityGroups,
-				CreatedTime:    time.Now(), // Use current time as fallback since CreateTime isn't available
+			if excludedBy != "" {
+				spare(eni, SkipReasonProtectedTag, excludedBy)
+				continue
 			}
+		}
 
-			if eni.VpcId != nil {
-				orphanedENI.VPCID = *eni.VpcId
+		// Filter by the creation timestamp tag if specified, falling back to
+		// CloudTrail for ENIs without one when enabled
+		createdTime := time.Now() // Use current time as fallback since CreateTime isn't available
+		needsCloudTrail := false
+		if options.CreatedTagKey != nil && *options.CreatedTagKey != "" {
+			tagTime, err := parseCreatedTag(tags, *options.CreatedTagKey)
+			switch {
+			case err == nil:
+				if !withinCreatedBounds(tagTime, options) {
+					continue
+				}
+				createdTime = tagTime
+			case options.UseCloudTrailForAge:
+				needsCloudTrail = true
+			default:
+				logging.V(9).Infof("Skipping ENI %s: %v", *eni.NetworkInterfaceId, err)
+				continue
 			}
+		} else if options.UseCloudTrailForAge {
+			needsCloudTrail = true
+		}
 
-			if eni.SubnetId != nil {
-				orphanedENI.SubnetID = *eni.SubnetId
-			}
+		// Create orphaned ENI entry
+		orphanedENI := newOrphanedENI(eni, region)
+		orphanedENI.LoadBalancerARN = loadBalancerARN
+		orphanedENI.NATGatewayID = natGatewayID
+		orphanedENI.CreatedTime = createdTime
+		deadOwner, hasDeadOwner := enrichment.deadOwners[orphanedENI.ID]
+		if hasDeadOwner {
+			logging.V(5).Infof("ENI %s is attached to instance %s, which no longer exists", orphanedENI.ID, deadOwner)
+		}
+		orphanedENI.Reason = classifyReason(eni, tags, loadBalancerARN, hasDeadOwner, options)
+		if deadNAT {
+			orphanedENI.Reason = ReasonDeletedNATGateway
+		}
 
-			if eni.AvailabilityZone != nil {
-				orphanedENI.AvailabilityZone = *eni.AvailabilityZone
-			}
+		if needsCloudTrail {
+			ageLookups = append(ageLookups, len(orphanedENIs))
+		}
+		orphanedENIs = append(orphanedENIs, orphanedENI)
+	}
 
-			if eni.Description != nil {
-				orphanedENI.Description = *eni.Description
-			}
+	// Age the remaining candidates by their CloudTrail creation events
+	if len(ageLookups) > 0 {
+		orphanedENIs = filterByCloudTrailAge(ctx, newCloudTrailClient(cfg), orphanedENIs, ageLookups, options)
+	}
 
-			if eni.Attachment != nil {
-				orphanedENI.AttachmentState = string(eni.Attachment.Status)
-				if eni.Attachment.AttachmentId != nil {
-					orphanedENI.AttachmentID = *eni.Attachment.AttachmentId
-				}
-			}
+	// Classify the candidates for escalation once their ages are known
+	if options.EscalationPolicy != nil {
+		now := time.Now()
+		for i := range orphanedENIs {
+			orphanedENIs[i].Tier = options.EscalationPolicy.classify(orphanedENIs[i], enrichment.subnetOwners, options.CreatedTagKey, now)
+		}
+	}
+
+	return guardVPCDeletionRatio(ctx, ec2Client, region, enis, len(filters) > 0, RegionResult{ENIs: orphanedENIs, Skipped: skipped}, options)
+}
+
+// eniTags extracts an ENI's tags as a map
+func eniTags(eni types.NetworkInterface) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range eni.TagSet {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+	return tags
+}
+
+// newOrphanedENI builds an OrphanedENI from the fields of a described ENI.
+// Detection-specific fields such as Reason are left for the caller to set.
+func newOrphanedENI(eni types.NetworkInterface, region string) OrphanedENI {
+	orphanedENI := OrphanedENI{
+		ID:                  *eni.NetworkInterfaceId,
+		Region:              region,
+		Tags:                eniTags(eni),
+		HasPublicIP:         eni.Association != nil && aws.ToString(eni.Association.PublicIp) != "",
+		CreatedTime:         time.Now(), // CreateTime isn't available from the describe
+		MacAddress:          aws.ToString(eni.MacAddress),
+		PrivateIPAddress:    aws.ToString(eni.PrivateIpAddress),
+		DeleteOnTermination: eni.Attachment != nil && aws.ToBool(eni.Attachment.DeleteOnTermination),
+		Fingerprint:         eniFingerprint(eni),
+	}
+
+	for _, address := range eni.PrivateIpAddresses {
+		if address.Association != nil && aws.ToString(address.Association.AllocationId) != "" {
+			orphanedENI.ElasticIPAllocationIDs = append(orphanedENI.ElasticIPAllocationIDs, *address.Association.AllocationId)
+		}
+		if association, ok := newAddressAssociation(address.Association); ok {
+			orphanedENI.AddressAssociations = append(orphanedENI.AddressAssociations, association)
+		}
+	}
+	if len(orphanedENI.ElasticIPAllocationIDs) == 0 && eni.Association != nil && aws.ToString(eni.Association.AllocationId) != "" {
+		orphanedENI.ElasticIPAllocationIDs = []string{*eni.Association.AllocationId}
+	}
+	if len(orphanedENI.AddressAssociations) == 0 {
+		if association, ok := newAddressAssociation(eni.Association); ok {
+			orphanedENI.AddressAssociations = []AddressAssociation{association}
+		}
+	}
+
+	// Extract security groups
+	for _, group := range eni.Groups {
+		if group.GroupId != nil {
+			orphanedENI.SecurityGroups = append(orphanedENI.SecurityGroups, *group.GroupId)
+		}
+	}
+
+	if eni.VpcId != nil {
+		orphanedENI.VPCID = *eni.VpcId
+	}
+
+	if eni.SubnetId != nil {
+		orphanedENI.SubnetID = *eni.SubnetId
+	}
 
-			orphanedENIs = append(orphanedENIs, orphanedENI)
+	if eni.OwnerId != nil {
+		orphanedENI.OwnerID = *eni.OwnerId
+	}
+
+	if eni.RequesterId != nil {
+		orphanedENI.RequesterID = *eni.RequesterId
+	}
+
+	if eni.AvailabilityZone != nil {
+		orphanedENI.AvailabilityZone = *eni.AvailabilityZone
+	}
+
+	if eni.Description != nil {
+		orphanedENI.Description = *eni.Description
+	}
+
+	orphanedENI.InterfaceType = string(eni.InterfaceType)
+
+	if eni.Attachment != nil {
+		orphanedENI.AttachmentState = string(eni.Attachment.Status)
+		if eni.Attachment.AttachmentId != nil {
+			orphanedENI.AttachmentID = *eni.Attachment.AttachmentId
 		}
 	}
 
-	return orphanedENIs, nil
+	return orphanedENI
 }
 
 // CleanupOrphanedENIs cleans up orphaned ENIs in the specified regions
 func CleanupOrphanedENIs(ctx context.Context, enis []OrphanedENI, dryRun bool, disassociateOnly bool, defaultSecurityGroupId *string, targetSecurityGroupId *string) CleanupResult {
-	result := CleanupResult{
-		CleanedENIs: make([]CleanedENI, 0),
-		Errors:      make([]string, 0),
+	return CleanupOrphanedENIsWithOptions(ctx, enis, CleanupOptions{
+		DryRun:                 dryRun,
+		DisassociateOnly:       disassociateOnly,
+		DefaultSecurityGroupId: defaultSecurityGroupId,
+		TargetSecurityGroupId:  targetSecurityGroupId,
+	})
+}
+
+// CleanupOrphanedENIsWithOptions cleans up orphaned ENIs using the given cleanup options
+func CleanupOrphanedENIsWithOptions(ctx context.Context, enis []OrphanedENI, options CleanupOptions) CleanupResult {
+	ctx, span := startSpan(ctx, "CleanupOrphanedENIs", attribute.Int(attrCandidates, len(enis)))
+	defer span.End()
+
+	results := newResultAccumulator(options.onENIResult())
+
+	// Track deletions so duplicate or stale entries are only processed once
+	if options.DeletedENIs == nil {
+		options.DeletedENIs = NewDeletedENIs()
 	}
 
+	// Cache of security group VPCs and VPC default security groups for this run
+	sgVPCs := newSGVPCCache()
+	deletions := newDeletionCap(options.MaxDeletions)
+
 	// Create a map to group ENIs by region
 	enisByRegion := make(map[string][]OrphanedENI)
 	for _, eni := range enis {
 		enisByRegion[eni.Region] = append(enisByRegion[eni.Region], eni)
 	}
 
-	// Process each region
-	for region, regionENIs := range enisByRegion {
-		// Create AWS config for this region
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	// Branch ENIs must be deleted before their trunk
+	for _, regionENIs := range enisByRegion {
+		sortForCleanup(regionENIs)
+	}
+
+	// Every call below uses RoleArn, when set
+	if options.credentials == nil {
+		credentials, err := options.assume(ctx, options.ConfigOptions)
 		if err != nil {
-			errMsg := fmt.Sprintf("Error loading AWS config for region %s: %v", region, err)
-			result.Errors = append(result.Errors, errMsg)
-			result.FailureCount += len(regionENIs)
-			continue
+			errMsg := fmt.Sprintf("Error assuming role: %v", err)
+			results.fail(len(enis), errMsg)
+			for _, eni := range enis {
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+			}
+			result := results.snapshot()
+			publishCleanupCompleted(ctx, options, len(enis), result)
+			return result
 		}
+		options.credentials = credentials
+	}
 
-		// Create EC2 client
-		ec2Client := ec2.NewFromConfig(cfg)
+	// In audit mode candidates are only tagged, in batches
+	if options.TagOnly {
+		tagAuditCandidates(ctx, enisByRegion, options, results)
+		result := results.snapshot()
+		publishCleanupCompleted(ctx, options, len(enis), result)
+		return result
+	}
 
-		// Get the default security group ID for the region if not provided
-		var defaultSG string
-		if defaultSecurityGroupId != nil && *defaultSecurityGroupId != "" {
-			defaultSG = *defaultSecurityGroupId
+	// Coordinate with cleanups run by other stacks
+	var lock *cleanupLock
+	if !options.DryRun {
+		var err error
+		lock, err = newCleanupLock(ctx, options)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error setting up cleanup lock: %v", err)
+			results.fail(len(enis), errMsg)
+			for _, eni := range enis {
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+			}
+			result := results.snapshot()
+			publishCleanupCompleted(ctx, options, len(enis), result)
+			return result
 		}
+	}
 
-		// Process each ENI in the region
+	// Process each region, timing each one
+	for region, regionENIs := range enisByRegion {
+		if ctx.Err() != nil {
+			results.truncate()
+			break
+		}
+
+		start := time.Now()
+		cleanupRegion(ctx, region, regionENIs, options, lock, sgVPCs, deletions, results)
+		results.regionTook(region, time.Since(start))
+	}
+
+	result := results.snapshot()
+	publishCleanupCompleted(ctx, options, len(enis), result)
+	return result
+}
+
+// cleanupRegion cleans up the candidates in one region, recording the results
+func cleanupRegion(ctx context.Context, region string, regionENIs []OrphanedENI, options CleanupOptions, lock *cleanupLock, sgVPCs *sgVPCCache, deletions *deletionCap, results *resultAccumulator) {
+	// Create AWS config for this region
+	cfg, err := loadConfig(ctx, region, options.ConfigOptions, options.credentials)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error loading AWS config for region %s: %v", region, err)
+		results.fail(len(regionENIs), errMsg)
 		for _, eni := range regionENIs {
-			if dryRun {
-				logging.V(5).Infof("[DRY RUN] Would clean up ENI %s in region %s", eni.ID, eni.Region)
-				result.SkippedCount++
-				continue
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+		}
+		return
+	}
+
+	// Create EC2 client, recording or replaying its calls if configured
+	captureOptions, err := apiCaptureOptions(options.RecordAPIPath, options.ReplayAPIPath)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error setting up API capture for region %s: %v", region, err)
+		results.fail(len(regionENIs), errMsg)
+		for _, eni := range regionENIs {
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+		}
+		return
+	}
+	ec2Client := options.ClientFactory.newClient(cfg, options.ConfigOptions, captureOptions...)
+
+	// Leave VPCs that still have instances alone
+	var occupied map[string]bool
+	if options.RequireEmptyVPC {
+		occupied, err = occupiedVPCs(ctx, ec2Client, regionENIs)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error checking VPCs for instances in region %s: %v", region, err)
+			results.fail(len(regionENIs), errMsg)
+			for _, eni := range regionENIs {
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
 			}
+			return
+		}
+	}
 
-			// For security group disassociation, we need to determine which groups to remove
-			var newGroups []string
-			var targetSG string
-			var actionTaken string
-
-			// If targetSecurityGroupId is specified, we only want to remove that one
-			if targetSecurityGroupId != nil && *targetSecurityGroupId != "" {
-				targetSG = *targetSecurityGroupId
-				// Keep all security groups except the target one
-				for _, sg := range eni.SecurityGroups {
-					if sg != targetSG {
-						newGroups = append(newGroups, sg)
-					}
-				}
+	// Get the default security group ID for the region if not provided
+	var defaultSG string
+	if options.DefaultSecurityGroupId != nil && *options.DefaultSecurityGroupId != "" {
+		defaultSG = *options.DefaultSecurityGroupId
+	}
 
-				// If no groups would be left and we have a default, use it
-				if len(newGroups) == 0 && defaultSG != "" {
-					newGroups = append(newGroups, defaultSG)
-				}
+	// Only one run per account-region proceeds at a time
+	if lock != nil {
+		acquired, err := lock.acquire(ctx, region)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error acquiring cleanup lock for region %s: %v", region, err)
+			results.fail(len(regionENIs), errMsg)
+			for _, eni := range regionENIs {
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: errMsg})
+			}
+			return
+		}
+		if !acquired {
+			logging.V(5).Infof("Skipping %d ENIs in region %s: cleanup lock is held by another run", len(regionENIs), region)
+			for _, eni := range regionENIs {
+				results.skip()
+				results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped})
+			}
+			return
+		}
+	}
 
-				// If the target SG is not in the current groups, skip
-				sgFound := false
-				for _, sg := range eni.SecurityGroups {
-					if sg == targetSG {
-						sgFound = true
-						break
-					}
-				}
+	// Process each ENI in the region. With DeleteConcurrency, groups of ENIs
+	// sharing addresses are cleaned up in parallel, each group in order.
+	var mu sync.Mutex
+	var deletedIDs []string
+	process := func(ctx context.Context, enis []OrphanedENI) {
+		for _, eni := range enis {
+			if ctx.Err() != nil {
+				results.truncate()
+				return
+			}
+			if cleanupCandidate(ctx, ec2Client, region, eni, options, occupied, defaultSG, sgVPCs, deletions, results) {
+				mu.Lock()
+				deletedIDs = append(deletedIDs, eni.ID)
+				mu.Unlock()
+			}
+		}
+	}
+	if options.deleteConcurrency() <= 1 {
+		process(ctx, regionENIs)
+	} else {
+		var tasks []func(context.Context) error
+		for _, group := range associationGroups(regionENIs) {
+			tasks = append(tasks, func(ctx context.Context) error {
+				process(ctx, group)
+				return nil
+			})
+		}
+		runConcurrently(ctx, options.deleteConcurrency(), tasks)
+	}
 
-				if !sgFound {
-					logging.V(5).Infof("ENI %s does not have target security group %s, skipping", eni.ID, targetSG)
-					result.SkippedCount++
-					continue
-				}
+	// Confirm the deletions took effect rather than trusting the API response
+	if options.VerifyDeletion && len(deletedIDs) > 0 && ctx.Err() == nil {
+		remaining, err := verifyDeletions(ctx, ec2Client, deletedIDs)
+		if err != nil {
+			results.verificationFailed(deletedIDs, fmt.Sprintf("Could not verify %d deleted ENIs in region %s: %v", len(deletedIDs), region, err))
+		} else if len(remaining) > 0 {
+			results.verificationFailed(remaining, fmt.Sprintf("%d deleted ENIs in region %s still exist: %v", len(remaining), region, remaining))
+		}
+	}
 
-				actionTaken = "disassociated from security group " + targetSG
-			} else {
-				// If no target is specified, remove all security groups and use default if available
-				if defaultSG != "" {
-					newGroups = []string{defaultSG}
-				} else {
-					newGroups = []string{} // Empty which is OK for AWS
-				}
-				actionTaken = "disassociated from all security groups"
+	if lock != nil {
+		lock.release(ctx, region)
+	}
+}
+
+// cleanupCandidate runs the pre-cleanup checks for an ENI, cleans it up and
+// reports the result. It returns whether the ENI was deleted.
+func cleanupCandidate(ctx context.Context, ec2Client ENIClient, region string, eni OrphanedENI, options CleanupOptions, occupied map[string]bool, defaultSG string, sgVPCs *sgVPCCache, deletions *deletionCap, results *resultAccumulator) bool {
+	if options.DeletedENIs.Contains(eni.ID) {
+		logging.V(5).Infof("Skipping ENI %s already deleted in this run", eni.ID)
+		return false
+	}
+
+	if occupied[eni.VPCID] {
+		logging.V(5).Infof("Skipping ENI %s: VPC %s still has instances", eni.ID, eni.VPCID)
+		results.skip()
+		results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped})
+		return false
+	}
+
+	if options.EscalationPolicy.action(eni.Tier) == EscalationActionReport {
+		logging.V(5).Infof("Reporting ENI %s only: escalation tier %q", eni.ID, eni.Tier)
+		results.skip()
+		results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped, Reason: SkipReasonEscalationReportOnly})
+		return false
+	}
+
+	// Close the window between detection and cleanup for ENIs that changed
+	if options.ConfirmUnchanged {
+		unchanged, change, err := confirmUnchanged(ctx, ec2Client, eni)
+		if err != nil {
+			results.fail(1, err.Error())
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeFailed, Error: err.Error()})
+			return false
+		}
+		if !unchanged {
+			logging.V(5).Infof("Skipping ENI %s: changed since detection (%s)", eni.ID, change)
+			results.skip()
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped, Reason: SkipReasonChangedSinceDetection})
+			return false
+		}
+	}
+
+	// Stop deleting once MaxDeletions is reached; dry runs delete nothing
+	deleting := !options.DryRun && !options.disassociateOnly(eni)
+	if deleting {
+		ok, capMsg := deletions.reserve()
+		if capMsg != "" {
+			logging.Warningf("%s", capMsg)
+			results.addError(capMsg)
+		}
+		if !ok {
+			logging.V(5).Infof("Skipping ENI %s: maxDeletions reached", eni.ID)
+			results.skip()
+			results.report(ENIResult{ENI: eni, Outcome: OutcomeSkipped, Reason: SkipReasonMaxDeletions})
+			return false
+		}
+	}
+
+	eniCtx, eniSpan := startSpan(ctx, "CleanupENI", attribute.String(attrRegion, region), attribute.String(attrENIID, eni.ID))
+	action, outcome, errMsg := cleanupENI(eniCtx, ec2Client, eni, options, defaultSG, sgVPCs, results)
+	if deleting && action != "deleted" {
+		deletions.release()
+	}
+	eniSpan.SetAttributes(attribute.String(attrAction, action), attribute.String(attrOutcome, outcome))
+	if errMsg != "" {
+		eniSpan.SetStatus(codes.Error, errMsg)
+	}
+	eniSpan.End()
+
+	results.report(ENIResult{ENI: eni, Action: action, Outcome: outcome, Error: errMsg})
+	return action == "deleted"
+}
+
+// cleanupENI disassociates and optionally deletes a single ENI, recording the
+// result. It returns the action taken, the outcome and the error of a failure.
+func cleanupENI(ctx context.Context, ec2Client ENIClient, eni OrphanedENI, options CleanupOptions, defaultSG string, sgVPCs *sgVPCCache, results *resultAccumulator) (string, string, string) {
+	if options.DryRun {
+		logging.V(5).Infof("[DRY RUN] Would clean up ENI %s in region %s", eni.ID, eni.Region)
+		results.skip()
+		return "", OutcomeSkipped, ""
+	}
+
+	// Stage cleanup across runs until the ENI has been found orphaned enough times
+	if options.DeletionStrikesRequired > 1 {
+		strikes := currentStrikes(eni.Tags) + 1
+		if strikes < options.DeletionStrikesRequired {
+			logging.V(5).Infof("ENI %s has %d of %d strikes, deferring cleanup", eni.ID, strikes, options.DeletionStrikesRequired)
+			recordStrike(ctx, ec2Client, eni.ID, strikes)
+			results.skip()
+			return fmt.Sprintf("recorded strike %d of %d", strikes, options.DeletionStrikesRequired), OutcomeSkipped, ""
+		}
+	}
+
+	// For security group disassociation, we need to determine which groups to remove
+	var newGroups []string
+	var targetSG string
+	var actionTaken string
+	var disassociated []string
+
+	// If targetSecurityGroupId is specified, we only want to remove that one
+	if options.TargetSecurityGroupId != nil && *options.TargetSecurityGroupId != "" {
+		targetSG = *options.TargetSecurityGroupId
+
+		// If the target SG is not in the current groups, skip
+		sgFound := false
+		for _, sg := range eni.SecurityGroups {
+			if sg == targetSG {
+				sgFound = true
+				break
 			}
+		}
 
-			// Modify the ENI's security groups
-			logging.V(5).Infof("Modifying security groups for ENI %s", eni.ID)
-			_, err := ec2Client.ModifyNetworkInterfaceAttribute(ctx, &ec2.ModifyNetworkInterfaceAttributeInput{
-				NetworkInterfaceId: aws.String(eni.ID),
-				Groups:             newGroups,
-			})
+		if !sgFound {
+			logging.V(5).Infof("ENI %s does not have target security group %s, skipping", eni.ID, targetSG)
+			results.skip()
+			return "", OutcomeSkipped, ""
+		}
+
+		// Keep all security groups except the target one
+		for _, sg := range eni.SecurityGroups {
+			if sg != targetSG {
+				newGroups = append(newGroups, sg)
+			}
+		}
 
+		// If no groups would be left, fall back to the default security group
+		if len(newGroups) == 0 {
+			fallbackSG, err := resolveFallbackSecurityGroup(ctx, ec2Client, eni, options.restoreSecurityGroupTagKey(), defaultSG, options.RequireDefaultOnEmpty, sgVPCs)
 			if err != nil {
-				errMsg := fmt.Sprintf("Failed to modify security groups for ENI %s: %v", eni.ID, err)
-				result.Errors = append(result.Errors, errMsg)
+				results.fail(1, err.Error())
+				return actionTaken, OutcomeFailed, err.Error()
+			}
+			newGroups = append(newGroups, fallbackSG)
+		}
 
-				// Try to tag for manual cleanup
-				tagENIForManualCleanup(ctx, ec2Client, eni.ID, err.Error())
-				result.FailureCount++
-				continue
+		actionTaken = "disassociated from security group " + targetSG
+	} else {
+		// If no target is specified, remove all security groups and use the default instead
+		fallbackSG, err := resolveFallbackSecurityGroup(ctx, ec2Client, eni, options.restoreSecurityGroupTagKey(), defaultSG, options.RequireDefaultOnEmpty, sgVPCs)
+		if err != nil {
+			results.fail(1, err.Error())
+			return actionTaken, OutcomeFailed, err.Error()
+		}
+		newGroups = []string{fallbackSG}
+		actionTaken = "disassociated from all security groups"
+	}
+
+	// Record which stack is about to act on the ENI, before any changes
+	if options.TagWithStackInfo {
+		tagENIWithStackInfo(ctx, ec2Client, eni.ID, options.StackName, options.ProjectName)
+	}
+
+	// Bound the sequence so one wedged ENI can't starve the rest. Tagging
+	// for manual cleanup uses ctx so it still runs after a timeout.
+	opCtx := ctx
+	if options.PerENITimeout > 0 {
+		var cancel context.CancelFunc
+		opCtx, cancel = context.WithTimeout(ctx, options.PerENITimeout)
+		defer cancel()
+	}
+
+	// Modify the ENI's security groups
+	logging.V(5).Infof("Modifying security groups for ENI %s", eni.ID)
+	_, err := ec2Client.ModifyNetworkInterfaceAttribute(opCtx, &ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: aws.String(eni.ID),
+		Groups:             newGroups,
+	})
+
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to modify security groups for ENI %s: %v", eni.ID, err)
+		return abandonENI(ctx, ec2Client, eni, "", errMsg, err.Error(), options, results)
+	}
+
+	// Only attempt to delete if not in disassociate-only mode
+	if !options.disassociateOnly(eni) {
+		// Detach the ENI if it's attached
+		if eni.AttachmentState != "" && eni.AttachmentState != "detached" && eni.AttachmentID != "" {
+			logging.V(5).Infof("Detaching ENI %s (attachment ID: %s)", eni.ID, eni.AttachmentID)
+			_, err := ec2Client.DetachNetworkInterface(opCtx, &ec2.DetachNetworkInterfaceInput{
+				AttachmentId: aws.String(eni.AttachmentID),
+				Force:        aws.Bool(true),
+			})
+			if err != nil {
+				errMsg := fmt.Sprintf("Error detaching ENI %s: %v", eni.ID, err)
+				if perENITimedOut(opCtx) {
+					errMsg = fmt.Sprintf("Gave up on ENI %s after the %s per-ENI timeout while detaching", eni.ID, options.PerENITimeout)
+				}
+				return abandonENI(ctx, ec2Client, eni, actionTaken, errMsg, errMsg, options, results)
 			}
 
-			// Only attempt to delete if not in disassociate-only mode
-			if !disassociateOnly {
-				// Detach the ENI if it's attached
-				if eni.AttachmentState != "" && eni.AttachmentState != "detached" && eni.AttachmentID != "" {
-					logging.V(5).Infof("Detaching ENI %s (attachment ID: %s)", eni.ID, eni.AttachmentID)
-					_, err := ec2Client.DetachNetworkInterface(ctx, &ec2.DetachNetworkInterfaceInput{
-						AttachmentId: aws.String(eni.AttachmentID),
-						Force:        aws.Bool(true),
-					})
-					if err != nil {
-						errMsg := fmt.Sprintf("Error detaching ENI %s: %v", eni.ID, err)
-						result.Errors = append(result.Errors, errMsg)
-						result.FailureCount++
-						continue
-					}
-
-					// Wait a moment for detachment to complete
-					time.Sleep(5 * time.Second)
+			// Record the detachment so subsequent runs observe the cooldown
+			tagENIDetached(ctx, ec2Client, eni.ID)
+
+			// Wait for detachment to complete, capped by the interface type's wait
+			maxWait := detachWait(eni.InterfaceType, options.DetachWaitByType, options.detachTimeout())
+			if err := waitForDetach(opCtx, ec2Client, eni, maxWait); err != nil {
+				errMsg := fmt.Sprintf("ENI %s did not become available within %s of detaching: %v", eni.ID, maxWait, err)
+				if perENITimedOut(opCtx) {
+					errMsg = fmt.Sprintf("Gave up on ENI %s after the %s per-ENI timeout while waiting for it to detach", eni.ID, options.PerENITimeout)
 				}
+				return abandonENI(ctx, ec2Client, eni, actionTaken, errMsg, errMsg, options, results)
+			}
+		}
 
-				// Try to delete the ENI
-				logging.V(5).Infof("Deleting ENI %s", eni.ID)
-				_, err = ec2Client.DeleteNetworkInterface(ctx, &ec2.DeleteNetworkInterfaceInput{
-					NetworkInterfaceId: aws.String(eni.ID),
+		// Associated Elastic IPs block the delete
+		if options.HandleAssociations {
+			disassociated = disassociateAddresses(opCtx, ec2Client, eni, results)
+		}
+
+		// Try to delete the ENI, retrying while it becomes deletable
+		logging.V(5).Infof("Deleting ENI %s", eni.ID)
+		err = deleteWithRetry(opCtx, ec2Client, eni.ID, options)
+		if err != nil && perENITimedOut(opCtx) {
+			errMsg := fmt.Sprintf("Gave up on ENI %s after the %s per-ENI timeout while deleting", eni.ID, options.PerENITimeout)
+			return abandonENI(ctx, ec2Client, eni, actionTaken, errMsg, errMsg, options, results)
+		}
+		if err != nil {
+			// Tag the ENI for manual cleanup since we can't delete it
+			errMsg := fmt.Sprintf("Could not delete ENI %s after removing security groups and %d attempts: %v", eni.ID, options.deleteAttempts(), err)
+			results.addError(errMsg)
+
+			// But we succeeded in disassociating security groups, so count as success with disassociate action
+			actionTaken = "disassociated from security groups (delete failed)"
+
+			if tagErr := tagENIForManualCleanup(ctx, ec2Client, eni.ID, options.manualCleanupTags(err.Error(), time.Now())); tagErr == nil {
+				results.manualCleanup(eni.ID)
+			} else {
+				results.deadLetter(CleanedENI{
+					ID:            eni.ID,
+					Region:        eni.Region,
+					VpcID:         eni.VPCID,
+					Description:   eni.Description,
+					ActionTaken:   actionTaken,
+					SecurityGroup: targetSG,
+					Reason:        eni.Reason,
+					Errors:        []string{errMsg, fmt.Sprintf("Failed to tag ENI %s for manual cleanup: %v", eni.ID, tagErr)},
 				})
-				if err != nil {
-					// Tag the ENI for manual cleanup since we can't delete it
-					errMsg := fmt.Sprintf("Could not delete ENI %s after removing security groups: %v", eni.ID, err)
-					result.Errors = append(result.Errors, errMsg)
-					tagENIForManualCleanup(ctx, ec2Client, eni.ID, err.Error())
-
-					// But we succeeded in disassociating security groups, so count as success with disassociate action
-					actionTaken = "disassociated from security groups (delete failed)"
-				} else {
-					actionTaken = "deleted"
-				}
 			}
+		} else {
+			actionTaken = "deleted"
+			options.DeletedENIs.Add(eni.ID)
+		}
+	}
 
-			// Success - add to cleaned ENIs
-			result.SuccessCount++
-			result.CleanedENIs = append(result.CleanedENIs, CleanedENI{
-				ID:            eni.ID,
-				Region:        eni.Region,
-				VpcID:         eni.VPCID,
-				Description:   eni.Description,
-				ActionTaken:   actionTaken,
-				SecurityGroup: targetSG,
-			})
+	// Success - add to cleaned ENIs
+	results.clean(CleanedENI{
+		ID:                     eni.ID,
+		Region:                 eni.Region,
+		VpcID:                  eni.VPCID,
+		Description:            eni.Description,
+		ActionTaken:            actionTaken,
+		SecurityGroup:          targetSG,
+		Reason:                 eni.Reason,
+		DisassociatedAddresses: disassociated,
+		NatGatewayID:           eni.NATGatewayID,
+	})
+
+	return actionTaken, OutcomeCleaned, ""
+}
+
+// perENITimedOut reports whether an ENI's PerENITimeout has expired
+func perENITimedOut(opCtx context.Context) bool {
+	return errors.Is(opCtx.Err(), context.DeadlineExceeded)
+}
+
+// parseCreatedTag reads an RFC3339 creation timestamp from the given tag
+func parseCreatedTag(tags map[string]string, key string) (time.Time, error) {
+	value, ok := tags[key]
+	if !ok {
+		return time.Time{}, fmt.Errorf("creation tag %s is missing", key)
+	}
+
+	createdTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("creation tag %s has unparseable value %q: %w", key, value, err)
+	}
+
+	return createdTime, nil
+}
+
+// withinCreatedBounds checks a creation time against the CreatedAfter/CreatedBefore
+// bounds and the OlderThanDays age limit
+func withinCreatedBounds(createdTime time.Time, options DetectOptions) bool {
+	if options.CreatedAfter != nil && !createdTime.After(*options.CreatedAfter) {
+		return false
+	}
+	if options.CreatedBefore != nil && !createdTime.Before(*options.CreatedBefore) {
+		return false
+	}
+	if options.OlderThanDays != nil {
+		maxCreated := time.Now().Add(-time.Duration(*options.OlderThanDays * 24 * float64(time.Hour)))
+		if createdTime.After(maxCreated) {
+			return false
 		}
 	}
+	return true
+}
 
-	return result
+// recentlyDetached checks whether an ENI carries a detached-at tag within the grace period
+func recentlyDetached(tags map[string]string, gracePeriod time.Duration) bool {
+	if gracePeriod <= 0 {
+		return false
+	}
+
+	value, ok := tags[DetachedAtTagKey]
+	if !ok {
+		return false
+	}
+
+	detachedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+
+	return time.Since(detachedAt) < gracePeriod
 }
 
-// findNetworkInterfaces finds ENIs in the given region based on filters
-func findNetworkInterfaces(ctx context.Context, client *ec2.Client, filters []types.Filter) ([]types.NetworkInterface, error) {
-	// Find ENIs with the specified filters
-	resp, err := client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
-		Filters: filters,
+// detachTimeout returns the configured detach timeout or the default
+func (o CleanupOptions) detachTimeout() time.Duration {
+	if o.DetachTimeout > 0 {
+		return o.DetachTimeout
+	}
+	return DefaultDetachWait
+}
+
+// detachWait returns the post-detach wait for an interface type, preferring
+// configured overrides over the built-in defaults, and fallback otherwise
+func detachWait(interfaceType string, overrides map[string]time.Duration, fallback time.Duration) time.Duration {
+	if wait, ok := overrides[interfaceType]; ok {
+		return wait
+	}
+	if wait, ok := defaultDetachWaitByType[interfaceType]; ok {
+		return wait
+	}
+	return fallback
+}
+
+// waitForDetach polls the ENI until it is available, returning an error if
+// it still isn't after maxWait. A non-positive maxWait doesn't wait.
+func waitForDetach(ctx context.Context, client ENIClient, eni OrphanedENI, maxWait time.Duration) error {
+	if maxWait <= 0 {
+		return nil
+	}
+
+	waiter := ec2.NewNetworkInterfaceAvailableWaiter(client, func(o *ec2.NetworkInterfaceAvailableWaiterOptions) {
+		o.MinDelay = time.Second
+		o.MaxDelay = 15 * time.Second
 	})
+	err := waiter.Wait(ctx, &ec2.DescribeNetworkInterfacesInput{
+		NetworkInterfaceIds: []string{eni.ID},
+	}, maxWait)
 	if err != nil {
-		return nil, err
+		logging.V(5).Infof("ENI %s (%s) not available %s after detach: %v", eni.ID, eni.InterfaceType, maxWait, err)
 	}
+	return err
+}
+
+// findNetworkInterfaces finds ENIs in the given region based on filters
+func findNetworkInterfaces(ctx context.Context, client ENIClient, filters []types.Filter) ([]types.NetworkInterface, error) {
+	return findNetworkInterfacesPaged(ctx, client, filters, nil)
+}
+
+// findNetworkInterfacesPaged finds ENIs based on filters, requesting pages of
+// up to maxResults ENIs, or EC2's default page size when nil
+func findNetworkInterfacesPaged(ctx context.Context, client ENIClient, filters []types.Filter, maxResults *int32) ([]types.NetworkInterface, error) {
+	// Find ENIs with the specified filters, following every page of results
+	var enis []types.NetworkInterface
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(client, &ec2.DescribeNetworkInterfacesInput{
+		Filters:    filters,
+		MaxResults: maxResults,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		enis = append(enis, page.NetworkInterfaces...)
+	}
+
+	return enis, nil
+}
 
-	return resp.NetworkInterfaces, nil
+// abandonENI counts an ENI whose cleanup failed and tags it for manual
+// cleanup. If even the tag can't be written, the ENI is dead-lettered with
+// every error encountered. It returns cleanupENI's results for the failure.
+func abandonENI(ctx context.Context, client ENIClient, eni OrphanedENI, actionTaken string, errMsg string, tagMsg string, options CleanupOptions, results *resultAccumulator) (string, string, string) {
+	results.fail(1, errMsg)
+	err := tagENIForManualCleanup(ctx, client, eni.ID, options.manualCleanupTags(tagMsg, time.Now()))
+	if err == nil {
+		results.manualCleanup(eni.ID)
+	} else {
+		results.deadLetter(CleanedENI{
+			ID:          eni.ID,
+			Region:      eni.Region,
+			VpcID:       eni.VPCID,
+			Description: eni.Description,
+			ActionTaken: actionTaken,
+			Reason:      eni.Reason,
+			Errors:      []string{errMsg, fmt.Sprintf("Failed to tag ENI %s for manual cleanup: %v", eni.ID, err)},
+		})
+	}
+	return actionTaken, OutcomeFailed, errMsg
 }
 
 // tagENIForManualCleanup tags an ENI for manual cleanup
-func tagENIForManualCleanup(ctx context.Context, client *ec2.Client, eniID string, errorMsg string) {
+func tagENIForManualCleanup(ctx context.Context, client ENIClient, eniID string, tags []types.Tag) error {
+	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{eniID},
+		Tags:      tags,
+	})
+	if err != nil {
+		logging.V(5).Infof("Failed to tag ENI %s for manual cleanup: %v", eniID, err)
+	}
+	return err
+}
+
+// tagENIDetached records when this tool force-detached an ENI
+func tagENIDetached(ctx context.Context, client ENIClient, eniID string) {
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
 		Resources: []string{eniID},
 		Tags: []types.Tag{
 			{
-				Key:   aws.String("NeedsManualCleanup"),
-				Value: aws.String("true"),
-			},
-			{
-				Key:   aws.String("AttemptedCleanupTime"),
+				Key:   aws.String(DetachedAtTagKey),
 				Value: aws.String(timestamp),
 			},
-			{
-				Key:   aws.String("DeletionError"),
-				Value: aws.String(errorMsg),
-			},
 		},
 	})
 	if err != nil {
-		logging.V(5).Infof("Failed to tag ENI %s for manual cleanup: %v", eniID, err)
+		logging.V(5).Infof("Failed to tag ENI %s with detachment time: %v", eniID, err)
+	}
+}
+
+// tagENIWithStackInfo tags an ENI with the Pulumi stack and project running the cleanup
+func tagENIWithStackInfo(ctx context.Context, client ENIClient, eniID string, stackName string, projectName string) {
+	var tags []types.Tag
+	if stackName != "" {
+		tags = append(tags, types.Tag{Key: aws.String(StackTagKey), Value: aws.String(stackName)})
+	}
+	if projectName != "" {
+		tags = append(tags, types.Tag{Key: aws.String(ProjectTagKey), Value: aws.String(projectName)})
+	}
+	if len(tags) == 0 {
+		logging.V(5).Infof("No stack or project name available to tag ENI %s", eniID)
+		return
+	}
+
+	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{eniID},
+		Tags:      tags,
+	})
+	if err != nil {
+		logging.V(5).Infof("Failed to tag ENI %s with stack info: %v", eniID, err)
 	}
 }
@@ -0,0 +1,50 @@
+package enicleanup
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ConfigOptions select where the AWS config comes from, in place of the
+// default config chain
+type ConfigOptions struct {
+	// Profile is the shared config profile to load credentials and settings
+	// from, as AWS_PROFILE would
+	Profile *string
+	// Endpoint overrides the endpoint of every AWS service called, e.g. to
+	// point at LocalStack for testing
+	Endpoint *string
+	// QPS caps the EC2 calls made per second in each region, spacing out
+	// describes and deletes to stay under the account's request rate. Zero
+	// means no limit. Throttled calls are retried either way.
+	QPS float64
+}
+
+// loadOptions composes the config load options for a region, using
+// credentials in place of the profile's or the default chain's when set. An
+// empty region leaves the default.
+func (o ConfigOptions) loadOptions(region string, credentials aws.CredentialsProvider) []func(*config.LoadOptions) error {
+	var optFns []func(*config.LoadOptions) error
+	if region != "" {
+		optFns = append(optFns, config.WithRegion(region))
+	}
+	if o.Profile != nil && *o.Profile != "" {
+		optFns = append(optFns, config.WithSharedConfigProfile(*o.Profile))
+	}
+	if o.Endpoint != nil && *o.Endpoint != "" {
+		optFns = append(optFns, config.WithBaseEndpoint(*o.Endpoint))
+	}
+	if credentials != nil {
+		optFns = append(optFns, config.WithCredentialsProvider(credentials))
+	}
+	return optFns
+}
+
+// loadConfig loads the AWS config for a region as selected by source, using
+// credentials in place of the default credential chain when set. An empty
+// region leaves the default.
+func loadConfig(ctx context.Context, region string, source ConfigOptions, credentials aws.CredentialsProvider) (aws.Config, error) {
+	return config.LoadDefaultConfig(ctx, source.loadOptions(region, credentials)...)
+}
@@ -0,0 +1,85 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+func TestConfigOptionsLoadOptions(t *testing.T) {
+	credentials := fakeRoleCredentials{roleArn: "arn:aws:iam::222222222222:role/cleanup"}
+
+	tests := []struct {
+		name        string
+		args        ResourceArgs
+		region      string
+		credentials aws.CredentialsProvider
+		want        config.LoadOptions
+	}{
+		{
+			name:   "default config",
+			region: "us-east-1",
+			want:   config.LoadOptions{Region: "us-east-1"},
+		},
+		{
+			name:   "profile and endpoint",
+			args:   ResourceArgs{Profile: aws.String("tooling"), Endpoint: aws.String("http://localhost:4566")},
+			region: "eu-west-1",
+			want:   config.LoadOptions{Region: "eu-west-1", SharedConfigProfile: "tooling", BaseEndpoint: "http://localhost:4566"},
+		},
+		{
+			name: "empty values are ignored",
+			args: ResourceArgs{Profile: aws.String(""), Endpoint: aws.String("")},
+			want: config.LoadOptions{},
+		},
+		{
+			name:        "credentials with a profile",
+			args:        ResourceArgs{Profile: aws.String("tooling")},
+			credentials: credentials,
+			want:        config.LoadOptions{SharedConfigProfile: "tooling", Credentials: credentials},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got config.LoadOptions
+			for _, optFn := range tt.args.configOptions().loadOptions(tt.region, tt.credentials) {
+				if err := optFn(&got); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// LoadOptions holds funcs, so compare the fields loadOptions sets
+			if got.Region != tt.want.Region || got.SharedConfigProfile != tt.want.SharedConfigProfile ||
+				got.BaseEndpoint != tt.want.BaseEndpoint || got.Credentials != tt.want.Credentials {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+			if got.Retryer != nil {
+				t.Error("expected no retryer override")
+			}
+		})
+	}
+}
+
+func TestEndpointReachesEC2Client(t *testing.T) {
+	var endpoints []string
+	factory := func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient {
+		endpoints = append(endpoints, aws.ToString(cfg.BaseEndpoint))
+		return newFakeEC2()
+	}
+
+	args := ResourceArgs{Regions: []string{"us-east-1"}, Endpoint: aws.String("http://localhost:4566")}
+	options, err := args.detectOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.ClientFactory = factory
+	if _, err := DetectOrphanedENIs(context.Background(), args.Regions, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(endpoints) != 1 || endpoints[0] != "http://localhost:4566" {
+		t.Errorf("expected the EC2 client to use the endpoint override, got %v", endpoints)
+	}
+}
@@ -0,0 +1,1145 @@
+package enicleanup
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestDetachWait(t *testing.T) {
+	overrides := map[string]time.Duration{
+		"interface": 30 * time.Second,
+		"lambda":    time.Minute,
+	}
+
+	tests := []struct {
+		interfaceType string
+		overrides     map[string]time.Duration
+		want          time.Duration
+	}{
+		{interfaceType: "interface", want: DefaultDetachWait},
+		{interfaceType: "lambda", want: defaultDetachWaitByType["lambda"]},
+		{interfaceType: "interface", overrides: overrides, want: 30 * time.Second},
+		{interfaceType: "lambda", overrides: overrides, want: time.Minute},
+		{interfaceType: "efa", overrides: overrides, want: DefaultDetachWait},
+	}
+
+	for _, tt := range tests {
+		if got := detachWait(tt.interfaceType, tt.overrides, DefaultDetachWait); got != tt.want {
+			t.Errorf("detachWait(%q) = %s, want %s", tt.interfaceType, got, tt.want)
+		}
+	}
+}
+
+func TestDetachTimeoutAbandonsStillAttachedENIs(t *testing.T) {
+	stillAttached := availableENI("eni-1")
+	stillAttached.Status = types.NetworkInterfaceStatusInUse
+	fake := newFakeEC2(stillAttached)
+	useFakeEC2(t, fake)
+
+	result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{
+		{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", AttachmentState: "attached", AttachmentID: "eni-attach-1"},
+	}, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		DetachTimeout:          100 * time.Millisecond,
+	})
+
+	if fake.deleted["eni-1"] != 0 || result.FailureCount != 1 {
+		t.Fatalf("expected the ENI to fail without a delete attempt, got %+v", result)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "did not become available within 100ms of detaching") {
+		t.Errorf("expected a detach timeout error, got %v", result.Errors)
+	}
+	if fake.tags["eni-1"][DefaultManualCleanupTagKey] != "true" {
+		t.Errorf("expected the ENI to be tagged for manual cleanup, got %v", fake.tags["eni-1"])
+	}
+}
+
+func TestIsTransitGatewayOrVPNENI(t *testing.T) {
+	tests := []struct {
+		name       string
+		eni        types.NetworkInterface
+		want       bool
+		attachment string
+	}{
+		{
+			name: "transit gateway attachment",
+			eni: types.NetworkInterface{
+				InterfaceType: "transit_gateway",
+				Description:   aws.String("Network Interface for Transit Gateway Attachment tgw-attach-0a1b2c3d"),
+			},
+			want:       true,
+			attachment: "tgw-attach-0a1b2c3d",
+		},
+		{
+			name: "VPN requester",
+			eni: types.NetworkInterface{
+				InterfaceType: "interface",
+				RequesterId:   aws.String("AWS-ClientVPN"),
+				Description:   aws.String("ClientVPNEndpoint-cvpn-endpoint-0123abcd"),
+			},
+			want:       true,
+			attachment: "cvpn-endpoint-0123abcd",
+		},
+		{
+			name: "regular interface",
+			eni:  types.NetworkInterface{InterfaceType: "interface", Description: aws.String("my eni")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransitGatewayOrVPNENI(tt.eni); got != tt.want {
+				t.Errorf("isTransitGatewayOrVPNENI() = %v, want %v", got, tt.want)
+			}
+			if got := vpnAttachmentID(tt.eni); got != tt.attachment {
+				t.Errorf("vpnAttachmentID() = %q, want %q", got, tt.attachment)
+			}
+		})
+	}
+}
+
+func TestProtectedEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		eni    types.NetworkInterface
+		reason string
+		owner  string
+	}{
+		{
+			name: "GWLB endpoint",
+			eni: types.NetworkInterface{
+				InterfaceType: "gateway_load_balancer_endpoint",
+				Description:   aws.String("VPC Endpoint Interface vpce-0a1b2c3d4e5f"),
+			},
+			reason: SkipReasonGatewayLoadBalancerEndpoint,
+			owner:  "vpce-0a1b2c3d4e5f",
+		},
+		{
+			name: "Global Accelerator by type",
+			eni: types.NetworkInterface{
+				InterfaceType: "global_accelerator_managed",
+				Description:   aws.String("Accelerator 1234abcd-abcd-1234-abcd-1234abcdef12 endpoint"),
+			},
+			reason: SkipReasonGlobalAccelerator,
+			owner:  "1234abcd-abcd-1234-abcd-1234abcdef12",
+		},
+		{
+			name: "Global Accelerator by requester",
+			eni: types.NetworkInterface{
+				InterfaceType: "interface",
+				RequesterId:   aws.String("AWS-GlobalAccelerator"),
+			},
+			reason: SkipReasonGlobalAccelerator,
+			owner:  "unknown accelerator",
+		},
+		{
+			name: "interface endpoint",
+			eni: types.NetworkInterface{
+				InterfaceType: "interface",
+				Description:   aws.String("VPC Endpoint Interface vpce-0a1b2c3d4e5f"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, owner, ok := protectedEndpoint(tt.eni)
+			if ok != (tt.reason != "") || reason != tt.reason || owner != tt.owner {
+				t.Errorf("protectedEndpoint() = %q, %q, %v, want %q, %q", reason, owner, ok, tt.reason, tt.owner)
+			}
+		})
+	}
+}
+
+func TestClassifyReason(t *testing.T) {
+	tagKey := "CreatedAt"
+	days := 7.0
+	sg := "sg-123"
+	detached := types.NetworkInterface{Status: types.NetworkInterfaceStatusAvailable}
+	inUse := types.NetworkInterface{Status: types.NetworkInterfaceStatusInUse, Attachment: &types.NetworkInterfaceAttachment{}}
+
+	tests := []struct {
+		name            string
+		eni             types.NetworkInterface
+		tags            map[string]string
+		loadBalancerARN string
+		deadOwner       bool
+		options         DetectOptions
+		want            string
+	}{
+		{name: "deleted load balancer", eni: detached, loadBalancerARN: "arn:lb", options: DetectOptions{SecurityGroupId: &sg}, want: ReasonDeletedLoadBalancer},
+		{name: "dead attachment owner", eni: inUse, deadOwner: true, options: DetectOptions{SecurityGroupId: &sg}, want: ReasonDeadAttachmentOwner},
+		{name: "target security group", eni: detached, options: DetectOptions{SecurityGroupId: &sg}, want: ReasonMatchedSecurityGroup},
+		{name: "stale creation tag", eni: detached, options: DetectOptions{CreatedTagKey: &tagKey, OlderThanDays: &days}, want: ReasonStale},
+		{name: "detached", eni: detached, tags: map[string]string{"Name": "x"}, want: ReasonDetached},
+		{name: "untagged", eni: inUse, want: ReasonUntagged},
+		{name: "filters only", eni: inUse, tags: map[string]string{"Name": "x"}, want: ReasonMatchedFilters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyReason(tt.eni, tt.tags, tt.loadBalancerARN, tt.deadOwner, tt.options); got != tt.want {
+				t.Errorf("classifyReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentStrikes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "", want: 0},
+		{value: "2", want: 2},
+		{value: "-1", want: 0},
+		{value: "two", want: 0},
+	}
+
+	for _, tt := range tests {
+		tags := map[string]string{}
+		if tt.value != "" {
+			tags[StrikesTagKey] = tt.value
+		}
+		if got := currentStrikes(tags); got != tt.want {
+			t.Errorf("currentStrikes(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatchingCIDR(t *testing.T) {
+	prefixes, err := parseCIDRs([]string{"10.0.0.0/16", "192.168.1.7/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cidr, ok := matchingCIDR("10.0.42.1", prefixes); !ok || cidr.String() != "10.0.0.0/16" {
+		t.Errorf("expected 10.0.42.1 to match 10.0.0.0/16, got %v %v", cidr, ok)
+	}
+	if cidr, ok := matchingCIDR("192.168.1.200", prefixes); !ok || cidr.String() != "192.168.1.0/24" {
+		t.Errorf("expected 192.168.1.200 to match the masked 192.168.1.0/24, got %v %v", cidr, ok)
+	}
+	for _, ip := range []string{"10.1.0.1", "", "not-an-ip"} {
+		if _, ok := matchingCIDR(ip, prefixes); ok {
+			t.Errorf("expected %q not to match", ip)
+		}
+	}
+}
+
+func TestDeletedENIsAreNotReprocessed(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"))
+	useFakeEC2(t, fake)
+
+	ctx := context.Background()
+	deleted := NewDeletedENIs()
+	detectOptions := DetectOptions{DeletedENIs: deleted}
+	cleanupOptions := CleanupOptions{DefaultSecurityGroupId: aws.String("sg-default"), DeletedENIs: deleted}
+
+	enis, err := DetectOrphanedENIs(ctx, []string{"us-east-1"}, detectOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enis) != 1 {
+		t.Fatalf("expected 1 orphaned ENI, got %d", len(enis))
+	}
+
+	result := CleanupOrphanedENIsWithOptions(ctx, enis, cleanupOptions)
+	if result.SuccessCount != 1 || fake.deleted["eni-1"] != 1 {
+		t.Fatalf("expected eni-1 to be deleted once, got result %+v and %d deletes", result, fake.deleted["eni-1"])
+	}
+
+	// The describe still lists the deleted ENI, but a re-scan excludes it
+	rescanned, err := DetectOrphanedENIs(ctx, []string{"us-east-1"}, detectOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rescanned) != 0 {
+		t.Errorf("expected deleted ENI to be excluded from the re-scan, got %d ENIs", len(rescanned))
+	}
+
+	// Cleaning up the stale candidates again neither acts nor counts
+	result = CleanupOrphanedENIsWithOptions(ctx, enis, cleanupOptions)
+	if result.SuccessCount != 0 || result.FailureCount != 0 || result.SkippedCount != 0 {
+		t.Errorf("expected the deleted ENI to be ignored, got %+v", result)
+	}
+	if fake.deleted["eni-1"] != 1 {
+		t.Errorf("expected a single delete call, got %d", fake.deleted["eni-1"])
+	}
+}
+
+func TestDuplicateCandidatesAreCleanedOnce(t *testing.T) {
+	fake := newFakeEC2()
+	useFakeEC2(t, fake)
+
+	eni := OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{eni, eni}, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+	})
+
+	if result.SuccessCount != 1 || len(result.CleanedENIs) != 1 {
+		t.Errorf("expected one cleaned ENI, got %+v", result)
+	}
+	if fake.deleted["eni-1"] != 1 {
+		t.Errorf("expected a single delete call, got %d", fake.deleted["eni-1"])
+	}
+}
+
+func TestOnRegionComplete(t *testing.T) {
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1")))
+
+	var regions []string
+	var detected int
+	options := DetectOptions{
+		OnRegionComplete: func(region string, result RegionResult) {
+			if result.Err != nil {
+				t.Errorf("unexpected error for %s: %v", region, result.Err)
+			}
+			regions = append(regions, region)
+			detected += len(result.ENIs)
+		},
+	}
+
+	enis, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1", "us-west-2"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Regions are scanned concurrently, so the callbacks arrive in any order
+	slices.Sort(regions)
+	if !slices.Equal(regions, []string{"us-east-1", "us-west-2"}) {
+		t.Errorf("expected a callback per region, got %v", regions)
+	}
+	// The fake returns eni-1 in both regions; the merged result reports it once
+	if detected != 2 || len(enis) != 1 {
+		t.Errorf("expected callbacks to report 2 ENIs merged into 1, got %d and %d", detected, len(enis))
+	}
+}
+
+func TestMatchingMACPrefix(t *testing.T) {
+	prefixes := []string{"0A:1B:2C", "fe-ed"}
+
+	tests := []struct {
+		mac  string
+		want string
+	}{
+		{mac: "0a:1b:2c:3d:4e:5f", want: "0A:1B:2C"},
+		{mac: "fe:ed:00:11:22:33", want: "fe-ed"},
+		{mac: "0a:1b:2d:3d:4e:5f"},
+		{mac: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := matchingMACPrefix(tt.mac, prefixes)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("matchingMACPrefix(%q) = %q, %v; want %q", tt.mac, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSkippedDetails(t *testing.T) {
+	reserved := availableENI("eni-reserved")
+	reserved.Description = aws.String("ELB app/my-lb/123")
+	protected := availableENI("eni-protected")
+	protected.TagSet = []types.Tag{{Key: aws.String("keep"), Value: aws.String("true")}}
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1"), reserved, protected))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+		SkipReservedDescriptions: []string{"ELB app/"},
+		ExcludeTagKeys:           []string{"keep"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-1" {
+		t.Errorf("expected only eni-1 to be detected, got %+v", result.ENIs)
+	}
+
+	want := []SkippedENI{
+		{ID: "eni-reserved", Region: "us-east-1", Reason: SkipReasonReservedDescription, Detail: "ELB app/my-lb/123"},
+		{ID: "eni-protected", Region: "us-east-1", Reason: SkipReasonProtectedTag, Detail: "keep"},
+	}
+	if len(result.Skipped) != len(want) {
+		t.Fatalf("expected %d skipped ENIs, got %+v", len(want), result.Skipped)
+	}
+	for i := range want {
+		if result.Skipped[i] != want[i] {
+			t.Errorf("skipped[%d] = %+v, want %+v", i, result.Skipped[i], want[i])
+		}
+	}
+}
+
+func TestReservedRequesterIsCheckedBeforeDescription(t *testing.T) {
+	rds := availableENI("eni-rds")
+	rds.RequesterId = aws.String("amazon-rds")
+	rds.Description = aws.String("RDSNetworkInterface")
+	custom := availableENI("eni-custom")
+	custom.RequesterId = aws.String("123456789012")
+	custom.Description = aws.String("ELB app/my-lb/123")
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1"), rds, custom))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+		SkipRequesterIds: []string{"123456789012"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-1" {
+		t.Errorf("expected only eni-1 to be detected, got %+v", result.ENIs)
+	}
+	want := []SkippedENI{
+		{ID: "eni-rds", Region: "us-east-1", Reason: SkipReasonReservedRequester, Detail: "amazon-rds"},
+		{ID: "eni-custom", Region: "us-east-1", Reason: SkipReasonReservedRequester, Detail: "123456789012"},
+	}
+	if len(result.Skipped) != len(want) {
+		t.Fatalf("expected %d skipped ENIs, got %+v", len(want), result.Skipped)
+	}
+	for i := range want {
+		if result.Skipped[i] != want[i] {
+			t.Errorf("skipped[%d] = %+v, want %+v", i, result.Skipped[i], want[i])
+		}
+	}
+}
+
+func TestScanFilters(t *testing.T) {
+	groupFilter := types.Filter{Name: aws.String("group-id"), Values: []string{"sg-app"}}
+	vpcFilter := types.Filter{Name: aws.String("vpc-id"), Values: []string{"vpc-1", "vpc-2"}}
+	subnetFilter := types.Filter{Name: aws.String("subnet-id"), Values: []string{"subnet-1"}}
+	zoneFilter := types.Filter{Name: aws.String("availability-zone"), Values: []string{"us-east-1a"}}
+
+	tests := []struct {
+		name    string
+		options DetectOptions
+		want    []types.Filter
+	}{
+		{name: "no filters"},
+		{name: "vpcs", options: DetectOptions{VpcIds: []string{"vpc-1", "vpc-2"}}, want: []types.Filter{vpcFilter}},
+		{name: "subnets", options: DetectOptions{SubnetIds: []string{"subnet-1"}}, want: []types.Filter{subnetFilter}},
+		{
+			name:    "vpcs and subnets",
+			options: DetectOptions{VpcIds: []string{"vpc-1", "vpc-2"}, SubnetIds: []string{"subnet-1"}},
+			want:    []types.Filter{vpcFilter, subnetFilter},
+		},
+		{
+			name:    "security group, vpcs and subnets",
+			options: DetectOptions{SecurityGroupId: aws.String("sg-app"), VpcIds: []string{"vpc-1", "vpc-2"}, SubnetIds: []string{"subnet-1"}},
+			want:    []types.Filter{groupFilter, vpcFilter, subnetFilter},
+		},
+		{name: "availability zones", options: DetectOptions{AvailabilityZones: []string{"us-east-1a"}}, want: []types.Filter{zoneFilter}},
+		{
+			name:    "subnets and availability zones",
+			options: DetectOptions{SubnetIds: []string{"subnet-1"}, AvailabilityZones: []string{"us-east-1a"}},
+			want:    []types.Filter{subnetFilter, zoneFilter},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeEC2()
+			useFakeEC2(t, fake)
+
+			if _, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, tt.options); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(fake.describeFilters) != 1 {
+				t.Fatalf("expected one describe, got %d", len(fake.describeFilters))
+			}
+			got := fake.describeFilters[0]
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected filters %s, got %s", filterNames(tt.want), filterNames(got))
+			}
+			for i := range tt.want {
+				if aws.ToString(got[i].Name) != aws.ToString(tt.want[i].Name) || !slices.Equal(got[i].Values, tt.want[i].Values) {
+					t.Errorf("filter %d = %s %v, want %s %v", i, aws.ToString(got[i].Name), got[i].Values, aws.ToString(tt.want[i].Name), tt.want[i].Values)
+				}
+			}
+		})
+	}
+}
+
+// filterNames lists the names of describe filters, for test failure messages
+func filterNames(filters []types.Filter) []string {
+	names := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		names = append(names, aws.ToString(filter.Name))
+	}
+	return names
+}
+
+func TestVpcAndSubnetFiltersNarrowDetection(t *testing.T) {
+	other := availableENI("eni-other")
+	other.VpcId = aws.String("vpc-2")
+	other.SubnetId = aws.String("subnet-2")
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1"), other))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+		VpcIds:    []string{"vpc-1", "vpc-2"},
+		SubnetIds: []string{"subnet-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-other" {
+		t.Errorf("expected only eni-other to be detected, got %+v", result.ENIs)
+	}
+}
+
+func TestAvailabilityZoneFilter(t *testing.T) {
+	inZone := availableENI("eni-1")
+	inZone.AvailabilityZone = aws.String("us-east-1a")
+	other := availableENI("eni-other")
+	other.AvailabilityZone = aws.String("us-east-1b")
+
+	t.Run("ENIs outside the zones are dropped when the describe ignores the filter", func(t *testing.T) {
+		useFakeEC2(t, newFakeEC2(inZone, other))
+
+		result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+			AvailabilityZones: []string{"us-east-1a"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-1" {
+			t.Errorf("expected only eni-1 to be detected, got %+v", result.ENIs)
+		}
+	})
+
+	t.Run("zones must belong to a scanned region", func(t *testing.T) {
+		useFakeEC2(t, newFakeEC2(inZone))
+
+		_, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+			AvailabilityZones: []string{"us-east-1a", "us-west-2a", "us-east-10a"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "[us-west-2a us-east-10a]") {
+			t.Errorf("expected an error naming us-west-2a and us-east-10a, got %v", err)
+		}
+	})
+}
+
+func TestZoneInRegion(t *testing.T) {
+	tests := []struct {
+		zone   string
+		region string
+		want   bool
+	}{
+		{zone: "us-east-1a", region: "us-east-1", want: true},
+		{zone: "us-east-1-bos-1a", region: "us-east-1", want: true},
+		{zone: "us-east-1", region: "us-east-1", want: false},
+		{zone: "us-east-10a", region: "us-east-1", want: false},
+		{zone: "us-west-2a", region: "us-east-1", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := zoneInRegion(tt.zone, tt.region); got != tt.want {
+			t.Errorf("zoneInRegion(%q, %q) = %v, want %v", tt.zone, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestReservedDescriptionMatching(t *testing.T) {
+	cache := availableENI("eni-cache")
+	cache.Description = aws.String("ElastiCache my-cluster")
+
+	tests := []struct {
+		name      string
+		options   DetectOptions
+		wantFound []string
+	}{
+		{
+			name:      "substrings are case-sensitive",
+			options:   DetectOptions{SkipReservedDescriptions: []string{"elasticache"}},
+			wantFound: []string{"eni-1", "eni-cache"},
+		},
+		{
+			name:      "substrings still match",
+			options:   DetectOptions{SkipReservedDescriptions: []string{"Cache my"}},
+			wantFound: []string{"eni-1"},
+		},
+		{
+			name:      "patterns match case-insensitively",
+			options:   DetectOptions{ReservedDescriptionPatterns: []string{"(?i)^elasticache"}},
+			wantFound: []string{"eni-1"},
+		},
+		{
+			name:      "patterns are anchored as written",
+			options:   DetectOptions{ReservedDescriptionPatterns: []string{"^my-cluster"}},
+			wantFound: []string{"eni-1", "eni-cache"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeEC2(t, newFakeEC2(availableENI("eni-1"), cache))
+
+			result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []string
+			for _, eni := range result.ENIs {
+				found = append(found, eni.ID)
+			}
+			if !slices.Equal(found, tt.wantFound) {
+				t.Errorf("found %v, want %v", found, tt.wantFound)
+			}
+			for _, skipped := range result.Skipped {
+				if skipped.Reason != SkipReasonReservedDescription || skipped.Detail != "ElastiCache my-cluster" {
+					t.Errorf("unexpected skip %+v", skipped)
+				}
+			}
+		})
+	}
+}
+
+func TestRequesterManagedENIsAreSkippedByDefault(t *testing.T) {
+	managed := availableENI("eni-managed")
+	managed.RequesterManaged = aws.Bool(true)
+	managed.RequesterId = aws.String("AROAEXAMPLE:service")
+
+	tests := []struct {
+		name        string
+		options     DetectOptions
+		wantFound   []string
+		wantSkipped []SkippedENI
+	}{
+		{
+			name:      "skipped by default",
+			wantFound: []string{"eni-1"},
+			wantSkipped: []SkippedENI{
+				{ID: "eni-managed", Region: "us-east-1", Reason: SkipReasonRequesterManaged, Detail: "AROAEXAMPLE:service"},
+			},
+		},
+		{
+			name:      "included on request",
+			options:   DetectOptions{IncludeRequesterManaged: true},
+			wantFound: []string{"eni-1", "eni-managed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeEC2(t, newFakeEC2(availableENI("eni-1"), managed))
+
+			result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []string
+			for _, eni := range result.ENIs {
+				found = append(found, eni.ID)
+				if eni.ID == "eni-managed" && eni.RequesterID != "AROAEXAMPLE:service" {
+					t.Errorf("expected the requester ID to be recorded, got %q", eni.RequesterID)
+				}
+			}
+			if !slices.Equal(found, tt.wantFound) {
+				t.Errorf("found %v, want %v", found, tt.wantFound)
+			}
+			if !slices.Equal(result.Skipped, tt.wantSkipped) {
+				t.Errorf("skipped %+v, want %+v", result.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestPerRegionOptions(t *testing.T) {
+	protected := availableENI("eni-protected")
+	protected.TagSet = []types.Tag{{Key: aws.String("keep"), Value: aws.String("true")}}
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1"), protected))
+
+	options := DetectOptions{
+		LogLevel: "info",
+		PerRegionOptions: map[string]DetectOptions{
+			"us-west-2": {ExcludeTagKeys: []string{"keep"}},
+		},
+	}
+
+	merged := options.forRegion("us-west-2")
+	if merged.LogLevel != "info" || len(merged.ExcludeTagKeys) != 1 || merged.PerRegionOptions != nil {
+		t.Errorf("expected base options with the override applied, got %+v", merged)
+	}
+
+	enis, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1", "us-west-2"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, eni := range enis {
+		got = append(got, eni.Region+"/"+eni.ID)
+	}
+	// eni-protected is a candidate in us-east-1 but protected in us-west-2, so
+	// it is skipped; eni-1 is reported once
+	want := []string{"us-east-1/eni-1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	_, err = DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, options)
+	if err == nil || !strings.Contains(err.Error(), "[us-west-2]") {
+		t.Errorf("expected an error for a region that isn't scanned, got %v", err)
+	}
+}
+
+func TestDeleteOnTerminationIsSkippedByDefault(t *testing.T) {
+	autoDeleted := availableENI("eni-auto")
+	autoDeleted.Status = types.NetworkInterfaceStatusInUse
+	autoDeleted.Attachment = &types.NetworkInterfaceAttachment{
+		AttachmentId:        aws.String("eni-attach-1"),
+		DeleteOnTermination: aws.Bool(true),
+	}
+	useFakeEC2(t, newFakeEC2(autoDeleted))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != SkipReasonDeleteOnTermination {
+		t.Errorf("expected the ENI to be skipped as delete-on-termination, got %+v", result)
+	}
+
+	result, err = DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{IncludeDeleteOnTermination: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 1 || !result.ENIs[0].DeleteOnTermination {
+		t.Errorf("expected the ENI to be detected with DeleteOnTermination set, got %+v", result)
+	}
+}
+
+func TestRespectRecentDeploys(t *testing.T) {
+	settled := availableENI("eni-settled")
+	settled.VpcId = aws.String("vpc-settled")
+	deploying := availableENI("eni-deploying")
+	deploying.VpcId = aws.String("vpc-deploying")
+	fake := newFakeEC2(settled, deploying)
+	fake.vpcs = []types.Vpc{
+		{
+			VpcId: aws.String("vpc-settled"),
+			Tags:  []types.Tag{{Key: aws.String(DefaultDeployTimestampTagKey), Value: aws.String(time.Now().Add(-2 * time.Hour).Format(time.RFC3339))}},
+		},
+		{
+			VpcId: aws.String("vpc-deploying"),
+			Tags:  []types.Tag{{Key: aws.String(DefaultDeployTimestampTagKey), Value: aws.String(time.Now().Add(-5 * time.Minute).Format(time.RFC3339))}},
+		},
+	}
+	useFakeEC2(t, fake)
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{RespectRecentDeploys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-settled" {
+		t.Errorf("expected only eni-settled to be detected, got %+v", result.ENIs)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].ID != "eni-deploying" || result.Skipped[0].Reason != SkipReasonRecentDeploy {
+		t.Errorf("expected eni-deploying to be skipped as recent-deploy, got %+v", result.Skipped)
+	}
+
+	// Without the option, deploy tags are ignored
+	result, err = DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 2 {
+		t.Errorf("expected both ENIs without RespectRecentDeploys, got %+v", result.ENIs)
+	}
+}
+
+func TestVerifyAttachmentOwners(t *testing.T) {
+	attachedTo := func(id, instanceID string) types.NetworkInterface {
+		eni := availableENI(id)
+		eni.Status = types.NetworkInterfaceStatusInUse
+		eni.OwnerId = aws.String("123456789012")
+		eni.Attachment = &types.NetworkInterfaceAttachment{
+			AttachmentId:    aws.String("eni-attach-" + id),
+			InstanceId:      aws.String(instanceID),
+			InstanceOwnerId: aws.String("123456789012"),
+		}
+		return eni
+	}
+	fake := newFakeEC2(
+		attachedTo("eni-live", "i-live"),
+		attachedTo("eni-gone", "i-gone"),
+		attachedTo("eni-terminated", "i-terminated"),
+	)
+	fake.instances = []types.Instance{
+		{InstanceId: aws.String("i-live"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}},
+		{InstanceId: aws.String("i-terminated"), State: &types.InstanceState{Name: types.InstanceStateNameTerminated}},
+	}
+	useFakeEC2(t, fake)
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{VerifyAttachmentOwners: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons := make(map[string]string)
+	for _, eni := range result.ENIs {
+		reasons[eni.ID] = eni.Reason
+	}
+	if reasons["eni-gone"] != ReasonDeadAttachmentOwner || reasons["eni-terminated"] != ReasonDeadAttachmentOwner {
+		t.Errorf("expected ENIs of missing and terminated instances to have a dead owner, got %v", reasons)
+	}
+	if reasons["eni-live"] == ReasonDeadAttachmentOwner {
+		t.Errorf("expected the ENI of a running instance not to have a dead owner, got %v", reasons)
+	}
+}
+
+func TestMaxVPCDeletionRatio(t *testing.T) {
+	inVPC := func(id, vpcID string, tags ...types.Tag) types.NetworkInterface {
+		eni := availableENI(id)
+		eni.VpcId = aws.String(vpcID)
+		eni.TagSet = tags
+		return eni
+	}
+	keep := types.Tag{Key: aws.String("Keep"), Value: aws.String("true")}
+	useFakeEC2(t, newFakeEC2(
+		inVPC("eni-a1", "vpc-a"),
+		inVPC("eni-a2", "vpc-a", keep),
+		inVPC("eni-a3", "vpc-a", keep),
+		inVPC("eni-b1", "vpc-b"),
+		inVPC("eni-b2", "vpc-b"),
+		inVPC("eni-c1", "vpc-c"),
+	))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+		ExcludeTagKeys:            []string{"Keep"},
+		MaxVPCDeletionRatio:       0.5,
+		VPCDeletionRatioOverrides: []string{"vpc-c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var detected []string
+	for _, eni := range result.ENIs {
+		detected = append(detected, eni.ID)
+	}
+	slices.Sort(detected)
+	if !slices.Equal(detected, []string{"eni-a1", "eni-c1"}) {
+		t.Errorf("expected only the candidates of vpc-a and the overridden vpc-c, got %v", detected)
+	}
+
+	want := []AbortedVPC{{VpcID: "vpc-b", Region: "us-east-1", Candidates: 2, Total: 2}}
+	if !slices.Equal(result.AbortedVPCs, want) {
+		t.Errorf("expected vpc-b to be aborted, got %+v", result.AbortedVPCs)
+	}
+
+	withheld := 0
+	for _, skipped := range result.Skipped {
+		if skipped.Reason == SkipReasonVPCDeletionRatio {
+			withheld++
+		}
+	}
+	if withheld != 2 {
+		t.Errorf("expected vpc-b's 2 candidates to be spared, got %d", withheld)
+	}
+}
+
+func TestBranchENIsAreCleanedBeforeTrunks(t *testing.T) {
+	enis := []OrphanedENI{
+		{ID: "eni-trunk", InterfaceType: "trunk"},
+		{ID: "eni-1", InterfaceType: "interface"},
+		{ID: "eni-branch-1", InterfaceType: "branch"},
+		{ID: "eni-2"},
+		{ID: "eni-branch-2", InterfaceType: "branch"},
+	}
+
+	sortForCleanup(enis)
+
+	var got []string
+	for _, eni := range enis {
+		got = append(got, eni.ID)
+	}
+	want := []string{"eni-branch-1", "eni-branch-2", "eni-1", "eni-2", "eni-trunk"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected cleanup order %v, got %v", want, got)
+	}
+}
+
+func TestPerENITimeoutGivesUpOnWedgedENI(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-wedged"), availableENI("eni-ok"))
+	fake.wedged = map[string]bool{"eni-wedged": true}
+	useFakeEC2(t, fake)
+
+	enis := []OrphanedENI{
+		{ID: "eni-wedged", Region: "us-east-1", VPCID: "vpc-1"},
+		{ID: "eni-ok", Region: "us-east-1", VPCID: "vpc-1"},
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		PerENITimeout:          50 * time.Millisecond,
+	})
+
+	if result.SuccessCount != 1 || result.FailureCount != 1 {
+		t.Fatalf("expected one success and one failure, got %+v", result)
+	}
+	if fake.deleted["eni-ok"] != 1 {
+		t.Error("expected the healthy ENI to be deleted after the wedged one")
+	}
+	if fake.tags["eni-wedged"]["NeedsManualCleanup"] != "true" {
+		t.Errorf("expected the wedged ENI to be tagged for manual cleanup, got %v", fake.tags["eni-wedged"])
+	}
+}
+
+func TestENIsThatCannotBeTaggedAreDeadLettered(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-tagged"), availableENI("eni-stranded"))
+	fake.failModify = map[string]bool{"eni-tagged": true, "eni-stranded": true}
+	fake.failTags = map[string]bool{"eni-stranded": true}
+	useFakeEC2(t, fake)
+
+	enis := []OrphanedENI{
+		{ID: "eni-tagged", Region: "us-east-1", VPCID: "vpc-1"},
+		{ID: "eni-stranded", Region: "us-east-1", VPCID: "vpc-1"},
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{DefaultSecurityGroupId: aws.String("sg-default")})
+
+	if result.FailureCount != 2 {
+		t.Errorf("expected both ENIs to fail, got %d", result.FailureCount)
+	}
+	if len(result.DeadLetter) != 1 || result.DeadLetter[0].ID != "eni-stranded" {
+		t.Fatalf("expected only the untaggable ENI to be dead-lettered, got %+v", result.DeadLetter)
+	}
+	if errs := result.DeadLetter[0].Errors; len(errs) != 2 || !strings.Contains(errs[0], "UnauthorizedOperation") || !strings.Contains(errs[1], "RequestLimitExceeded") {
+		t.Errorf("expected the modify and tagging errors, got %v", errs)
+	}
+}
+
+func TestUndeletableENIsThatCannotBeTaggedAreDeadLettered(t *testing.T) {
+	recordDeleteBackoff(t)
+	fake := &flakyDeleteEC2{fakeEC2: newFakeEC2(), failures: 3, attempts: make(map[string]int)}
+	fake.failTags = map[string]bool{"eni-stranded": true}
+
+	result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{
+		{ID: "eni-stranded", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}},
+	}, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		ClientFactory:          stubFactory(fake),
+	})
+
+	if len(result.ManualCleanup) != 0 {
+		t.Errorf("expected no ENI tagged for manual cleanup, got %v", result.ManualCleanup)
+	}
+	if len(result.DeadLetter) != 1 || result.DeadLetter[0].ID != "eni-stranded" {
+		t.Fatalf("expected the undeletable, untaggable ENI to be dead-lettered, got %+v", result.DeadLetter)
+	}
+	if errs := result.DeadLetter[0].Errors; len(errs) != 2 || !strings.Contains(errs[0], "InvalidNetworkInterface.InUse") || !strings.Contains(errs[1], "RequestLimitExceeded") {
+		t.Errorf("expected the delete and tagging errors, got %v", errs)
+	}
+}
+
+func TestDescribeBatchSizeIsClamped(t *testing.T) {
+	tests := []struct {
+		batchSize int
+		want      int32
+	}{
+		{batchSize: 3, want: 5},
+		{batchSize: 50, want: 50},
+		{batchSize: 5000, want: 1000},
+	}
+	for _, tt := range tests {
+		fake := newFakeEC2(availableENI("eni-1"))
+		useFakeEC2(t, fake)
+
+		if _, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, DetectOptions{DescribeBatchSize: tt.batchSize}); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.describeMaxResults) == 0 || aws.ToInt32(fake.describeMaxResults[0]) != tt.want {
+			t.Errorf("batch size %d: expected pages of %d, got %v", tt.batchSize, tt.want, fake.describeMaxResults)
+		}
+	}
+
+	fake := newFakeEC2(availableENI("eni-1"))
+	useFakeEC2(t, fake)
+	if _, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, DetectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if fake.describeMaxResults[0] != nil {
+		t.Errorf("expected EC2's default page size when unset, got %d", *fake.describeMaxResults[0])
+	}
+}
+
+func TestFindNetworkInterfacesFollowsNextToken(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"), availableENI("eni-2"), availableENI("eni-3"))
+	fake.describePageSize = 2
+
+	enis, err := findNetworkInterfaces(context.Background(), fake, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, eni := range enis {
+		ids = append(ids, aws.ToString(eni.NetworkInterfaceId))
+	}
+	if !slices.Equal(ids, []string{"eni-1", "eni-2", "eni-3"}) {
+		t.Errorf("expected the ENIs of both pages, got %v", ids)
+	}
+	if len(fake.describeMaxResults) != 2 {
+		t.Errorf("expected 2 describe calls, got %d", len(fake.describeMaxResults))
+	}
+}
+
+func TestRequireEmptyVPCSkipsOccupiedVPCs(t *testing.T) {
+	fake := newFakeEC2()
+	fake.instances = []types.Instance{
+		{InstanceId: aws.String("i-live"), VpcId: aws.String("vpc-active"), State: &types.InstanceState{Name: types.InstanceStateNameStopped}},
+		{InstanceId: aws.String("i-gone"), VpcId: aws.String("vpc-torn-down"), State: &types.InstanceState{Name: types.InstanceStateNameTerminated}},
+	}
+	useFakeEC2(t, fake)
+
+	enis := []OrphanedENI{
+		{ID: "eni-active", Region: "us-east-1", VPCID: "vpc-active"},
+		{ID: "eni-torn-down", Region: "us-east-1", VPCID: "vpc-torn-down"},
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		RequireEmptyVPC:        true,
+	})
+
+	if result.SuccessCount != 1 || result.SkippedCount != 1 {
+		t.Fatalf("expected one cleaned and one skipped ENI, got %+v", result)
+	}
+	if fake.deleted["eni-torn-down"] != 1 || fake.deleted["eni-active"] != 0 {
+		t.Errorf("expected only the ENI in the empty VPC to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestHandleAssociationsDisassociatesBeforeDelete(t *testing.T) {
+	withEIP := availableENI("eni-eip")
+	withEIP.Association = &types.NetworkInterfaceAssociation{
+		AllocationId:  aws.String("eipalloc-1"),
+		AssociationId: aws.String("eipassoc-1"),
+		PublicIp:      aws.String("203.0.113.10"),
+	}
+	fake := newFakeEC2(withEIP)
+	fake.associations = map[string][]string{"eni-eip": {"eipassoc-1"}}
+	useFakeEC2(t, fake)
+
+	enis, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		HandleAssociations:     true,
+	})
+
+	if fake.deleted["eni-eip"] != 1 || result.SuccessCount != 1 {
+		t.Fatalf("expected the ENI to be deleted once its address was disassociated, got %+v", result)
+	}
+	if got := result.CleanedENIs[0].DisassociatedAddresses; len(got) != 1 || got[0] != "203.0.113.10" {
+		t.Errorf("expected the disassociated address to be reported, got %v", got)
+	}
+}
+
+func TestAssociatedAddressBlocksDeleteByDefault(t *testing.T) {
+	recordDeleteBackoff(t)
+	fake := newFakeEC2()
+	fake.associations = map[string][]string{"eni-eip": {"eipassoc-1"}}
+	useFakeEC2(t, fake)
+
+	enis := []OrphanedENI{{
+		ID:                  "eni-eip",
+		Region:              "us-east-1",
+		VPCID:               "vpc-1",
+		AddressAssociations: []AddressAssociation{{AssociationID: "eipassoc-1", PublicIP: "203.0.113.10"}},
+	}}
+	CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{DefaultSecurityGroupId: aws.String("sg-default")})
+
+	if fake.deleted["eni-eip"] != 0 || fake.tags["eni-eip"]["NeedsManualCleanup"] != "true" {
+		t.Errorf("expected the associated ENI to be tagged for manual cleanup, got tags %v", fake.tags["eni-eip"])
+	}
+}
+
+func TestSharedSubnetENIsAreSkipped(t *testing.T) {
+	ours := availableENI("eni-ours")
+	ours.OwnerId = aws.String("111111111111")
+	shared := availableENI("eni-shared")
+	shared.OwnerId = aws.String("111111111111")
+	shared.SubnetId = aws.String("subnet-shared")
+	fake := newFakeEC2(ours, shared)
+	fake.subnets = []types.Subnet{
+		{SubnetId: aws.String("subnet-1"), OwnerId: aws.String("111111111111")},
+		{SubnetId: aws.String("subnet-shared"), OwnerId: aws.String("222222222222")},
+	}
+	useFakeEC2(t, fake)
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-ours" {
+		t.Fatalf("expected only eni-ours to be a candidate, got %+v", result.ENIs)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Reason != SkipReasonSharedSubnet ||
+		!strings.Contains(result.Skipped[0].Detail, "222222222222") {
+		t.Errorf("expected eni-shared to be spared as a shared subnet ENI, got %+v", result.Skipped)
+	}
+
+	result, err = DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{IncludeSharedSubnets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 2 {
+		t.Errorf("expected IncludeSharedSubnets to include eni-shared, got %+v", result.ENIs)
+	}
+}
+
+func TestDeletedNATGatewayENIsAreCandidates(t *testing.T) {
+	natENI := func(id, gatewayID string) types.NetworkInterface {
+		eni := availableENI(id)
+		eni.InterfaceType = types.NetworkInterfaceTypeNatGateway
+		eni.Description = aws.String("Interface for NAT Gateway " + gatewayID)
+		return eni
+	}
+	fake := newFakeEC2(natENI("eni-dead", "nat-0dead"), natENI("eni-gone", "nat-0bad"), natENI("eni-live", "nat-0cafe"), natENI("eni-deleting", "nat-0abc"))
+	fake.natGateways = []types.NatGateway{
+		{NatGatewayId: aws.String("nat-0dead"), State: types.NatGatewayStateDeleted},
+		{NatGatewayId: aws.String("nat-0cafe"), State: types.NatGatewayStateAvailable},
+		{NatGatewayId: aws.String("nat-0abc"), State: types.NatGatewayStateDeleting},
+	}
+	useFakeEC2(t, fake)
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ENIs) != 0 || len(result.Skipped) != 4 {
+		t.Fatalf("expected every NAT gateway ENI to be spared without VerifyNATGateways, got %+v", result.ENIs)
+	}
+
+	result, err = DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{VerifyNATGateways: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateways := make(map[string]string)
+	for _, eni := range result.ENIs {
+		if eni.Reason != ReasonDeletedNATGateway {
+			t.Errorf("expected %s to be selected as %s, got %s", eni.ID, ReasonDeletedNATGateway, eni.Reason)
+		}
+		gateways[eni.ID] = eni.NATGatewayID
+	}
+	want := map[string]string{"eni-dead": "nat-0dead", "eni-gone": "nat-0bad"}
+	if !maps.Equal(gateways, want) {
+		t.Errorf("expected candidates %v, got %v", want, gateways)
+	}
+	// Live NAT gateway ENIs are identified by their interface type before
+	// the description fallback is reached
+	for _, skipped := range result.Skipped {
+		if skipped.Reason != SkipReasonInterfaceType {
+			t.Errorf("expected %s to be spared by its interface type, got %s", skipped.ID, skipped.Reason)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// regionNamePattern matches AWS region names such as us-east-1 and us-gov-west-1
+var regionNamePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// Check validates the inputs at plan time, so invalid arguments are reported
+// against their property in `pulumi preview` instead of failing Create.
+func (r Resource) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (ResourceArgs, []p.CheckFailure, error) {
+	args, failures, err := infer.DefaultCheck[ResourceArgs](ctx, newInputs)
+	if err != nil || len(failures) > 0 {
+		return args, failures, err
+	}
+
+	// Unknown inputs decode as zero values, which could fail validation
+	// spuriously; they are checked once known
+	if newInputs.ContainsUnknowns() {
+		return args, nil, nil
+	}
+
+	// Resolve omitted regions from the ambient AWS environment, so the
+	// regions scanned are recorded in the inputs
+	args.Regions = DefaultRegions(ctx, args.Regions)
+
+	return args, args.checkFailures(), nil
+}
+
+// checkFailures validates the arguments, reporting each problem against the
+// property it came from
+func (args ResourceArgs) checkFailures() []p.CheckFailure {
+	var failures []p.CheckFailure
+	if len(args.Regions) == 0 {
+		failures = append(failures, p.CheckFailure{Property: "regions", Reason: "at least one region must be specified"})
+	}
+	for _, region := range args.Regions {
+		if !validRegion(region, args.RegionGroups) {
+			failures = append(failures, p.CheckFailure{
+				Property: "regions",
+				Reason:   fmt.Sprintf("%q is not a region name, region group or region sentinel", region),
+			})
+		}
+	}
+
+	if args.MaxDeletions != nil && *args.MaxDeletions < 0 {
+		failures = append(failures, p.CheckFailure{Property: "maxDeletions", Reason: fmt.Sprintf("maxDeletions must not be negative, got %d", *args.MaxDeletions)})
+	}
+
+	if args.DeleteAttempts != nil && *args.DeleteAttempts < 0 {
+		failures = append(failures, p.CheckFailure{Property: "deleteAttempts", Reason: fmt.Sprintf("deleteAttempts must not be negative, got %d", *args.DeleteAttempts)})
+	}
+
+	if args.DeleteRetryBaseDelaySeconds != nil && *args.DeleteRetryBaseDelaySeconds < 0 {
+		failures = append(failures, p.CheckFailure{Property: "deleteRetryBaseDelaySeconds", Reason: fmt.Sprintf("deleteRetryBaseDelaySeconds must not be negative, got %v", *args.DeleteRetryBaseDelaySeconds)})
+	}
+
+	if _, err := args.detectOptions(); err != nil {
+		failures = append(failures, validationFailures(err)...)
+	}
+	return failures
+}
+
+// validRegion checks whether a region is a region name, a region group or a
+// sentinel such as all-enabled
+func validRegion(region string, groups map[string][]string) bool {
+	if _, ok := groups[region]; ok {
+		return true
+	}
+	if _, ok := DefaultRegionGroups[region]; ok {
+		return true
+	}
+	return isRegionSentinel(region) || regionNamePattern.MatchString(region)
+}
+
+// validationFailures converts the errors joined by Validate into check
+// failures, using each ValidationError's property
+func validationFailures(err error) []p.CheckFailure {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var failures []p.CheckFailure
+		for _, err := range joined.Unwrap() {
+			failures = append(failures, validationFailures(err)...)
+		}
+		return failures
+	}
+
+	if invalid, ok := err.(*ValidationError); ok {
+		return []p.CheckFailure{{Property: invalid.Property, Reason: invalid.Error()}}
+	}
+	if wrapped := errors.Unwrap(err); wrapped != nil {
+		return validationFailures(wrapped)
+	}
+	return []p.CheckFailure{{Reason: err.Error()}}
+}
@@ -0,0 +1,46 @@
+package enicleanup
+
+import (
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+func TestCheckFailuresNameTheirProperty(t *testing.T) {
+	olderThanDays := -1.0
+	createdTagKey := "created-at"
+	onlyPublicIP, excludePublicIP := true, true
+	args := ResourceArgs{
+		Regions:         []string{"us-east-1", "US East", "eu", "all-enabled"},
+		OlderThanDays:   &olderThanDays,
+		CreatedTagKey:   &createdTagKey,
+		ExcludePublicIp: &excludePublicIP,
+		OnlyPublicIp:    &onlyPublicIP,
+		IncludeTagKeys:  []string{"team"},
+		ExcludeTagKeys:  []string{"team"},
+	}
+
+	want := map[string]bool{"regions": true, "olderThanDays": true, "onlyPublicIp": true, "excludeTagKeys": true}
+	failures := args.checkFailures()
+	if len(failures) != len(want) {
+		t.Fatalf("expected %d failures, got %+v", len(want), failures)
+	}
+	for _, failure := range failures {
+		if !want[failure.Property] {
+			t.Errorf("unexpected failure %+v", failure)
+		}
+	}
+}
+
+func TestCheckFailuresRequireRegions(t *testing.T) {
+	failures := ResourceArgs{}.checkFailures()
+	if len(failures) != 1 || failures[0] != (p.CheckFailure{Property: "regions", Reason: "at least one region must be specified"}) {
+		t.Errorf("expected a missing regions failure, got %+v", failures)
+	}
+
+	badTime := "yesterday"
+	failures = ResourceArgs{Regions: []string{"us-gov-west-1"}, CreatedTagKey: &badTime, CreatedBefore: &badTime}.checkFailures()
+	if len(failures) != 1 || failures[0].Property != "createdBefore" {
+		t.Errorf("expected an unparseable createdBefore failure, got %+v", failures)
+	}
+}
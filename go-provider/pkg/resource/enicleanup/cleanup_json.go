@@ -0,0 +1,39 @@
+package enicleanup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// ToJSON renders the result as indented JSON, including every cleaned and
+// dead-lettered ENI with its region, action and timestamp, and all errors
+func (r CleanupResult) ToJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cleanup result: %w", err)
+	}
+	return data, nil
+}
+
+// writeOutputFile writes the result as JSON to the outputFile argument, if
+// set. The cleanup has already happened by then, so a failure is recorded in
+// the result's errors rather than failing the operation.
+func (args ResourceArgs) writeOutputFile(result *CleanupResult) {
+	if args.OutputFile == nil || *args.OutputFile == "" {
+		return
+	}
+
+	data, err := result.ToJSON()
+	if err == nil {
+		err = os.WriteFile(*args.OutputFile, data, 0o644)
+	}
+	if err != nil {
+		logging.V(5).Infof("Failed to write cleanup result to %s: %v", *args.OutputFile, err)
+		result.Errors = append(result.Errors, fmt.Sprintf("Error writing cleanup result to %s: %v", *args.OutputFile, err))
+		return
+	}
+	logging.V(5).Infof("Wrote cleanup result to %s", *args.OutputFile)
+}
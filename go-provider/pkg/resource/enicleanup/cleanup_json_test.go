@@ -0,0 +1,97 @@
+package enicleanup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestCleanupResultJSON(t *testing.T) {
+	fake := newFakeEC2()
+	fake.failModify = map[string]bool{"eni-2": true}
+	fake.failTags = map[string]bool{"eni-2": true}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{
+		{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}},
+		{ID: "eni-2", Region: "us-west-2", VPCID: "vpc-2", SecurityGroups: []string{"sg-app"}},
+	}, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		ClientFactory:          stubFactory(fake),
+	})
+	if len(result.CleanedENIs) != 1 || len(result.DeadLetter) != 1 || len(result.Errors) == 0 {
+		t.Fatalf("expected one cleaned and one dead-lettered ENI, got %+v", result)
+	}
+
+	data, err := result.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var roundTripped CleanupResult
+	if err := json.Unmarshal(data, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTripped, result) {
+		t.Errorf("round trip changed the result:\ngot  %+v\nwant %+v", roundTripped, result)
+	}
+
+	var schema struct {
+		SuccessCount *int             `json:"successCount"`
+		FailureCount *int             `json:"failureCount"`
+		SkippedCount *int             `json:"skippedCount"`
+		CleanedENIs  []map[string]any `json:"cleanedEnis"`
+		DeadLetter   []map[string]any `json:"deadLetter"`
+		Errors       []string         `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.SuccessCount == nil || schema.FailureCount == nil || schema.SkippedCount == nil || len(schema.Errors) == 0 {
+		t.Errorf("expected the counts and errors, got %s", data)
+	}
+	for _, eni := range append(schema.CleanedENIs, schema.DeadLetter...) {
+		for _, key := range []string{"id", "region", "actionTaken", "timestamp"} {
+			if _, ok := eni[key]; !ok {
+				t.Errorf("expected %q in %v", key, eni)
+			}
+		}
+		if timestamp, _ := eni["timestamp"].(string); timestamp != "" {
+			if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+				t.Errorf("timestamp is not RFC3339: %v", err)
+			}
+		}
+	}
+}
+
+func TestWriteOutputFile(t *testing.T) {
+	result := CleanupResult{
+		SuccessCount: 1,
+		CleanedENIs:  []CleanedENI{{ID: "eni-1", Region: "us-east-1", ActionTaken: "deleted", Timestamp: "2024-01-01T00:00:00Z"}},
+		Errors:       []string{},
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	ResourceArgs{OutputFile: &path}.writeOutputFile(&result)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written CleanupResult
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(written, result) {
+		t.Errorf("wrote %+v, want %+v", written, result)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing", "result.json")
+	ResourceArgs{OutputFile: &missing}.writeOutputFile(&result)
+	if len(result.Errors) != 1 {
+		t.Errorf("expected the failed write to be recorded, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,125 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// stubFactory returns a ClientFactory handing out the stub for every region
+func stubFactory(stub ENIClient) ClientFactory {
+	return func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient { return stub }
+}
+
+func TestClientFactoryDetection(t *testing.T) {
+	reserved := availableENI("eni-reserved")
+	reserved.Description = aws.String("ELB app/web/0123456789abcdef")
+
+	tests := []struct {
+		name        string
+		eni         types.NetworkInterface
+		wantFound   bool
+		wantSkipped string
+	}{
+		{name: "available ENI is a candidate", eni: availableENI("eni-1"), wantFound: true},
+		{name: "reserved description is skipped", eni: reserved, wantSkipped: SkipReasonReservedDescription},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newFakeEC2(tt.eni)
+			detected, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{ClientFactory: stubFactory(stub)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if found := len(detected.ENIs) == 1; found != tt.wantFound {
+				t.Errorf("expected found %v, got %+v", tt.wantFound, detected.ENIs)
+			}
+			if tt.wantSkipped != "" && (len(detected.Skipped) != 1 || detected.Skipped[0].Reason != tt.wantSkipped) {
+				t.Errorf("expected the ENI skipped as %s, got %+v", tt.wantSkipped, detected.Skipped)
+			}
+		})
+	}
+}
+
+func TestClientFactoryCleanup(t *testing.T) {
+	recordDeleteBackoff(t)
+	tests := []struct {
+		name             string
+		eni              OrphanedENI
+		associations     map[string][]string
+		options          CleanupOptions
+		wantAction       string
+		wantDetached     bool
+		wantDeleted      bool
+		wantManualTagged bool
+		wantSkipped      bool
+	}{
+		{
+			name:        "deletes a detached ENI",
+			eni:         OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"},
+			wantAction:  "deleted",
+			wantDeleted: true,
+		},
+		{
+			name:         "detaches before deleting",
+			eni:          OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", AttachmentState: "attached", AttachmentID: "eni-attach-1"},
+			wantAction:   "deleted",
+			wantDetached: true,
+			wantDeleted:  true,
+		},
+		{
+			name:       "disassociates only",
+			eni:        OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"},
+			options:    CleanupOptions{DisassociateOnly: true},
+			wantAction: "disassociated from all security groups",
+		},
+		{
+			name:             "tags for manual cleanup when the delete fails",
+			eni:              OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"},
+			associations:     map[string][]string{"eni-1": {"eipassoc-1"}},
+			wantAction:       "disassociated from security groups (delete failed)",
+			wantManualTagged: true,
+		},
+		{
+			name:        "skips ENIs in occupied VPCs",
+			eni:         OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"},
+			options:     CleanupOptions{RequireEmptyVPC: true},
+			wantSkipped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newFakeEC2(availableENI("eni-1"))
+			stub.associations = tt.associations
+			stub.instances = []types.Instance{{InstanceId: aws.String("i-1"), VpcId: aws.String("vpc-1"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}}}
+
+			options := tt.options
+			options.DefaultSecurityGroupId = aws.String("sg-default")
+			options.ClientFactory = stubFactory(stub)
+			result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{tt.eni}, options)
+
+			if tt.wantSkipped {
+				if result.SkippedCount != 1 || len(stub.modified) != 0 {
+					t.Errorf("expected the ENI to be skipped untouched, got %+v", result)
+				}
+				return
+			}
+			if len(result.CleanedENIs) != 1 || result.CleanedENIs[0].ActionTaken != tt.wantAction {
+				t.Fatalf("expected action %q, got %+v", tt.wantAction, result)
+			}
+			if detached := len(stub.detached) > 0; detached != tt.wantDetached {
+				t.Errorf("expected detached %v, got %v", tt.wantDetached, stub.detached)
+			}
+			if deleted := stub.deleted["eni-1"] == 1; deleted != tt.wantDeleted {
+				t.Errorf("expected deleted %v, got %v", tt.wantDeleted, stub.deleted)
+			}
+			if tagged := stub.tags["eni-1"][DefaultManualCleanupTagKey] == "true"; tagged != tt.wantManualTagged {
+				t.Errorf("expected tagged for manual cleanup %v, got %v", tt.wantManualTagged, stub.tags["eni-1"])
+			}
+		})
+	}
+}
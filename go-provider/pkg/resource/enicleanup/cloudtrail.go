@@ -0,0 +1,137 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailtypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// cloudTrailConcurrency bounds concurrent LookupEvents calls per region.
+// CloudTrail allows two lookups per second per account and region.
+const cloudTrailConcurrency = 2
+
+// cloudTrailRetention is how far back LookupEvents can see. ENIs with no
+// CreateNetworkInterface event were created before then.
+const cloudTrailRetention = 90 * 24 * time.Hour
+
+// cloudTrailAPI is the subset of the CloudTrail client used for ENI ages
+type cloudTrailAPI interface {
+	LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
+}
+
+// newCloudTrailClient creates the CloudTrail client. Tests replace it with a fake.
+var newCloudTrailClient = func(cfg aws.Config) cloudTrailAPI {
+	return cloudtrail.NewFromConfig(cfg)
+}
+
+// cloudTrailCreation is an ENI's creation time as found in CloudTrail
+type cloudTrailCreation struct {
+	created time.Time
+	// found is false when CloudTrail had no CreateNetworkInterface event,
+	// so the ENI predates the retention window
+	found bool
+}
+
+// cloudTrailCreations caches creation times found by ENI ID across runs,
+// since they never change and lookups are slow. Misses aren't cached, as
+// CloudTrail can take several minutes to make an event visible.
+var cloudTrailCreations = struct {
+	sync.Mutex
+	byID map[string]cloudTrailCreation
+}{byID: make(map[string]cloudTrailCreation)}
+
+// lookupCreationTime finds when an ENI was created from its
+// CreateNetworkInterface event, using the cache where possible
+func lookupCreationTime(ctx context.Context, client cloudTrailAPI, eniID string) (cloudTrailCreation, error) {
+	cloudTrailCreations.Lock()
+	creation, ok := cloudTrailCreations.byID[eniID]
+	cloudTrailCreations.Unlock()
+	if ok {
+		return creation, nil
+	}
+
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []cloudtrailtypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailtypes.LookupAttributeKeyResourceName,
+				AttributeValue: aws.String(eniID),
+			},
+		},
+	}
+	for {
+		output, err := client.LookupEvents(ctx, input)
+		if err != nil {
+			return cloudTrailCreation{}, fmt.Errorf("error looking up CloudTrail events for ENI %s: %w", eniID, err)
+		}
+		for _, event := range output.Events {
+			if aws.ToString(event.EventName) == "CreateNetworkInterface" && event.EventTime != nil {
+				creation = cloudTrailCreation{created: *event.EventTime, found: true}
+			}
+		}
+		if creation.found || output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	if creation.found {
+		cloudTrailCreations.Lock()
+		cloudTrailCreations.byID[eniID] = creation
+		cloudTrailCreations.Unlock()
+	}
+	return creation, nil
+}
+
+// filterByCloudTrailAge looks up the creation time of the candidates at the
+// given indexes in CloudTrail, at most cloudTrailConcurrency at a time, and
+// drops those outside the CreatedAfter/CreatedBefore/OlderThanDays bounds.
+// ENIs CloudTrail has no event for are taken to be as old as its retention
+// window, and ENIs whose lookup fails are dropped.
+func filterByCloudTrailAge(ctx context.Context, client cloudTrailAPI, enis []OrphanedENI, indexes []int, options DetectOptions) []OrphanedENI {
+	if len(indexes) == 0 {
+		return enis
+	}
+
+	keep := make([]bool, len(enis))
+	for i := range keep {
+		keep[i] = true
+	}
+
+	sem := make(chan struct{}, cloudTrailConcurrency)
+	var wg sync.WaitGroup
+	for _, i := range indexes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			creation, err := lookupCreationTime(ctx, client, enis[i].ID)
+			if err != nil {
+				logging.V(5).Infof("Skipping ENI %s: %v", enis[i].ID, err)
+				keep[i] = false
+				return
+			}
+			if !creation.found {
+				creation.created = time.Now().Add(-cloudTrailRetention)
+			}
+			keep[i] = withinCreatedBounds(creation.created, options)
+			enis[i].CreatedTime = creation.created
+		}()
+	}
+	wg.Wait()
+
+	var kept []OrphanedENI
+	for i, eni := range enis {
+		if keep[i] {
+			kept = append(kept, eni)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,106 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailtypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// fakeCloudTrail answers LookupEvents from CreateNetworkInterface times by ENI ID
+type fakeCloudTrail struct {
+	mu      sync.Mutex
+	created map[string]time.Time
+	fail    map[string]bool
+	lookups map[string]int
+}
+
+func (f *fakeCloudTrail) LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	id := aws.ToString(params.LookupAttributes[0].AttributeValue)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups[id]++
+	if f.fail[id] {
+		return nil, errors.New("ThrottlingException")
+	}
+
+	output := &cloudtrail.LookupEventsOutput{}
+	if created, ok := f.created[id]; ok {
+		output.Events = []cloudtrailtypes.Event{
+			{EventName: aws.String("CreateTags"), EventTime: aws.Time(created.Add(time.Minute))},
+			{EventName: aws.String("CreateNetworkInterface"), EventTime: aws.Time(created)},
+		}
+	}
+	return output, nil
+}
+
+func useFakeCloudTrail(t *testing.T, fake *fakeCloudTrail) {
+	t.Helper()
+	fake.lookups = make(map[string]int)
+	original := newCloudTrailClient
+	newCloudTrailClient = func(cfg aws.Config) cloudTrailAPI { return fake }
+	reset := func() {
+		cloudTrailCreations.Lock()
+		cloudTrailCreations.byID = make(map[string]cloudTrailCreation)
+		cloudTrailCreations.Unlock()
+	}
+	reset()
+	t.Cleanup(func() {
+		newCloudTrailClient = original
+		reset()
+	})
+}
+
+func TestCloudTrailAgeFiltering(t *testing.T) {
+	tagged := availableENI("eni-tagged")
+	tagged.TagSet = []types.Tag{{Key: aws.String("created-at"), Value: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339))}}
+	useFakeEC2(t, newFakeEC2(availableENI("eni-old"), availableENI("eni-new"), availableENI("eni-untracked"), availableENI("eni-failed"), tagged))
+	fake := &fakeCloudTrail{
+		created: map[string]time.Time{
+			"eni-old": time.Now().Add(-30 * 24 * time.Hour),
+			"eni-new": time.Now().Add(-time.Hour),
+		},
+		fail: map[string]bool{"eni-failed": true},
+	}
+	useFakeCloudTrail(t, fake)
+
+	options := DetectOptions{
+		OlderThanDays:       aws.Float64(7),
+		CreatedTagKey:       aws.String("created-at"),
+		UseCloudTrailForAge: true,
+	}
+	enis, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// eni-new is too young, eni-failed couldn't be aged, and eni-tagged is
+	// aged by its tag without a lookup
+	var got []string
+	for _, eni := range enis {
+		got = append(got, eni.ID)
+	}
+	if len(got) != 2 || got[0] != "eni-old" || got[1] != "eni-untracked" {
+		t.Fatalf("expected eni-old and eni-untracked, got %v", got)
+	}
+	if !enis[0].CreatedTime.Equal(fake.created["eni-old"]) {
+		t.Errorf("expected the CloudTrail creation time, got %s", enis[0].CreatedTime)
+	}
+	if fake.lookups["eni-tagged"] != 0 {
+		t.Error("expected tagged ENIs not to be looked up")
+	}
+
+	// Found creation times are cached, misses are looked up again
+	if _, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, options); err != nil {
+		t.Fatal(err)
+	}
+	if fake.lookups["eni-old"] != 1 || fake.lookups["eni-untracked"] != 2 {
+		t.Errorf("expected cached hits and repeated misses, got %v", fake.lookups)
+	}
+}
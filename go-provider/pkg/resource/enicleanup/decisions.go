@@ -0,0 +1,71 @@
+package enicleanup
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// Decisions logged by DecisionLog
+const (
+	DecisionCandidate    = "candidate"
+	DecisionSkip         = "skip"
+	DecisionDryRun       = "dry-run"
+	DecisionDelete       = "delete"
+	DecisionDisassociate = "disassociate"
+	DecisionTag          = "tag"
+	DecisionFailed       = "failed"
+)
+
+// DecisionLogf writes DecisionLog lines. It logs at info level regardless of
+// the verbosity; commands with their own logger may replace it.
+var DecisionLogf = logging.Infof
+
+// LogDecision writes one DecisionLog line for an ENI
+func LogDecision(id, region, decision, reason string) {
+	if reason == "" {
+		reason = "-"
+	}
+	DecisionLogf("%s %s %s %s", id, region, decision, reason)
+}
+
+// logSkippedDecisions logs the ENIs detection spared
+func logSkippedDecisions(skipped []SkippedENI) {
+	for _, eni := range skipped {
+		LogDecision(eni.ID, eni.Region, DecisionSkip, eni.Reason)
+	}
+}
+
+// cleanupDecision maps a cleanup result to the decision logged for it
+func cleanupDecision(result ENIResult, dryRun bool) string {
+	switch {
+	case result.Outcome == OutcomeFailed:
+		return DecisionFailed
+	case result.Outcome == OutcomeSkipped && dryRun:
+		return DecisionDryRun
+	case result.Outcome == OutcomeSkipped:
+		return DecisionSkip
+	case result.Action == "deleted":
+		return DecisionDelete
+	case result.Action == auditActionTagged:
+		return DecisionTag
+	default:
+		return DecisionDisassociate
+	}
+}
+
+// onENIResult returns the per-ENI callback for cleanup: OnENIResult, plus a
+// DecisionLog line when enabled
+func (o CleanupOptions) onENIResult() func(ENIResult) {
+	if !o.DecisionLog {
+		return o.OnENIResult
+	}
+	return func(result ENIResult) {
+		reason := result.ENI.Reason
+		if result.Error != "" {
+			reason = result.Error
+		}
+		LogDecision(result.ENI.ID, result.ENI.Region, cleanupDecision(result, o.DryRun), reason)
+		if o.OnENIResult != nil {
+			o.OnENIResult(result)
+		}
+	}
+}
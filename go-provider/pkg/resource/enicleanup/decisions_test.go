@@ -0,0 +1,64 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// captureDecisions records DecisionLog lines for the duration of a test
+func captureDecisions(t *testing.T) *[]string {
+	t.Helper()
+	var lines []string
+	original := DecisionLogf
+	DecisionLogf = func(format string, args ...interface{}) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	t.Cleanup(func() { DecisionLogf = original })
+	return &lines
+}
+
+func TestDecisionLogWritesOneLinePerENI(t *testing.T) {
+	protected := availableENI("eni-protected")
+	protected.TagSet = []types.Tag{{Key: aws.String("keep"), Value: aws.String("true")}}
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1"), protected))
+	lines := captureDecisions(t)
+
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+		ExcludeTagKeys: []string{"keep"},
+		DecisionLog:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	CleanupOrphanedENIsWithOptions(context.Background(), detected.ENIs, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		DecisionLog:            true,
+	})
+
+	want := []string{
+		"eni-protected us-east-1 skip protected-tag",
+		"eni-1 us-east-1 delete detached",
+	}
+	if len(*lines) != len(want) {
+		t.Fatalf("expected %d decision lines, got %q", len(want), *lines)
+	}
+	for i := range want {
+		if (*lines)[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, (*lines)[i], want[i])
+		}
+	}
+}
+
+func TestDecisionLogIsOffByDefault(t *testing.T) {
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1")))
+	lines := captureDecisions(t)
+
+	enis := []OrphanedENI{{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"}}
+	CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{DryRun: true})
+
+	if len(*lines) != 0 {
+		t.Errorf("expected no decision lines, got %q", *lines)
+	}
+}
@@ -0,0 +1,80 @@
+package enicleanup
+
+// DefaultDeleteConcurrency is the default number of ENIs cleaned up
+// concurrently per region; 1 cleans them up one at a time
+const DefaultDeleteConcurrency = 1
+
+// deleteConcurrency returns the configured delete concurrency or the default
+func (o CleanupOptions) deleteConcurrency() int {
+	if o.DeleteConcurrency > 0 {
+		return o.DeleteConcurrency
+	}
+	return DefaultDeleteConcurrency
+}
+
+// associationGroups partitions a region's candidates into groups that can be
+// cleaned up concurrently. ENIs sharing an Elastic IP association, allocation
+// or public IP are grouped, since disassociating or deleting one changes what
+// the others reference, as are all trunk and branch ENIs, so branches are
+// still removed before their trunk. Groups are ordered by their first ENI and
+// keep the given order within.
+func associationGroups(enis []OrphanedENI) [][]OrphanedENI {
+	parent := make([]int, len(enis))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Union each ENI with the first ENI seen sharing any of its keys
+	owners := make(map[string]int)
+	for i, eni := range enis {
+		for _, key := range dependencyKeys(eni) {
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = i
+				continue
+			}
+			if a, b := find(owner), find(i); a != b {
+				parent[max(a, b)] = min(a, b)
+			}
+		}
+	}
+
+	var groups [][]OrphanedENI
+	index := make(map[int]int)
+	for i, eni := range enis {
+		root := find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], eni)
+	}
+	return groups
+}
+
+// dependencyKeys returns the shared resources cleaning up an ENI depends on
+func dependencyKeys(eni OrphanedENI) []string {
+	var keys []string
+	for _, association := range eni.AddressAssociations {
+		keys = append(keys, "association:"+association.AssociationID)
+		if association.PublicIP != "" {
+			keys = append(keys, "public-ip:"+association.PublicIP)
+		}
+	}
+	for _, allocationID := range eni.ElasticIPAllocationIDs {
+		keys = append(keys, "allocation:"+allocationID)
+	}
+	if eni.InterfaceType == trunkInterfaceType || eni.InterfaceType == branchInterfaceType {
+		keys = append(keys, "trunk-branch")
+	}
+	return keys
+}
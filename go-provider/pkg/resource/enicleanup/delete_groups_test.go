@@ -0,0 +1,146 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+func TestAssociationGroups(t *testing.T) {
+	enis := []OrphanedENI{
+		{ID: "eni-a", AddressAssociations: []AddressAssociation{{AssociationID: "eipassoc-1", PublicIP: "203.0.113.10"}}},
+		{ID: "eni-b"},
+		{ID: "eni-c", AddressAssociations: []AddressAssociation{{AssociationID: "eipassoc-2", PublicIP: "203.0.113.10"}}},
+		{ID: "eni-trunk", InterfaceType: trunkInterfaceType},
+		{ID: "eni-d", ElasticIPAllocationIDs: []string{"eipalloc-1"}},
+		{ID: "eni-branch", InterfaceType: branchInterfaceType},
+		{ID: "eni-e", ElasticIPAllocationIDs: []string{"eipalloc-1"}},
+	}
+
+	var got [][]string
+	for _, group := range associationGroups(enis) {
+		var ids []string
+		for _, eni := range group {
+			ids = append(ids, eni.ID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]string{{"eni-a", "eni-c"}, {"eni-b"}, {"eni-trunk", "eni-branch"}, {"eni-d", "eni-e"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected groups %v, got %v", want, got)
+	}
+}
+
+// sharedAddressEC2 models ENIs sharing Elastic IP associations: it records a
+// conflict whenever two ENIs sharing an address are disassociated or deleted
+// at the same time, and the most calls ever in flight across all ENIs.
+type sharedAddressEC2 struct {
+	*fakeEC2
+	// addresses maps ENI IDs to the address they share
+	addresses map[string]string
+
+	mu          sync.Mutex
+	busy        map[string]string
+	inFlight    int
+	maxInFlight int
+	conflicts   []string
+}
+
+func (s *sharedAddressEC2) enter(eniID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if address := s.addresses[eniID]; address != "" {
+		if other, ok := s.busy[address]; ok && other != eniID {
+			s.conflicts = append(s.conflicts, other+"/"+eniID)
+		}
+		s.busy[address] = eniID
+	}
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+}
+
+func (s *sharedAddressEC2) leave(eniID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if address := s.addresses[eniID]; s.busy[address] == eniID {
+		delete(s.busy, address)
+	}
+	s.inFlight--
+}
+
+func (s *sharedAddressEC2) DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
+	eniID := ""
+	s.fakeEC2.mu.Lock()
+	for id, associations := range s.fakeEC2.associations {
+		for _, association := range associations {
+			if association == aws.ToString(params.AssociationId) {
+				eniID = id
+			}
+		}
+	}
+	s.fakeEC2.mu.Unlock()
+
+	s.enter(eniID)
+	defer s.leave(eniID)
+	time.Sleep(10 * time.Millisecond)
+	return s.fakeEC2.DisassociateAddress(ctx, params, optFns...)
+}
+
+func (s *sharedAddressEC2) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	eniID := aws.ToString(params.NetworkInterfaceId)
+	s.enter(eniID)
+	defer s.leave(eniID)
+	time.Sleep(10 * time.Millisecond)
+	return s.fakeEC2.DeleteNetworkInterface(ctx, params, optFns...)
+}
+
+func TestDeleteConcurrencySerializesSharedAssociations(t *testing.T) {
+	fake := newFakeEC2()
+	fake.associations = map[string][]string{
+		"eni-a1": {"eipassoc-a1"},
+		"eni-a2": {"eipassoc-a2"},
+		"eni-b1": {"eipassoc-b1"},
+		"eni-b2": {"eipassoc-b2"},
+	}
+	mock := &sharedAddressEC2{
+		fakeEC2:   fake,
+		addresses: map[string]string{"eni-a1": "203.0.113.10", "eni-a2": "203.0.113.10", "eni-b1": "203.0.113.20", "eni-b2": "203.0.113.20"},
+		busy:      make(map[string]string),
+	}
+	original := newEC2Client
+	newEC2Client = func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient { return mock }
+	t.Cleanup(func() { newEC2Client = original })
+
+	var enis []OrphanedENI
+	for _, id := range []string{"eni-a1", "eni-b1", "eni-a2", "eni-b2"} {
+		enis = append(enis, OrphanedENI{
+			ID:                  id,
+			Region:              "us-east-1",
+			VPCID:               "vpc-1",
+			AddressAssociations: []AddressAssociation{{AssociationID: "eipassoc-" + id[len("eni-"):], PublicIP: mock.addresses[id]}},
+		})
+	}
+	enis = append(enis, OrphanedENI{ID: "eni-plain", Region: "us-east-1", VPCID: "vpc-1"})
+
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		HandleAssociations:     true,
+		DeleteConcurrency:      4,
+	})
+
+	if result.SuccessCount != len(enis) {
+		t.Fatalf("expected every ENI to be deleted, got %+v", result)
+	}
+	if len(mock.conflicts) > 0 {
+		t.Errorf("expected ENIs sharing an address to be cleaned up one at a time, got overlaps %v", mock.conflicts)
+	}
+	if mock.maxInFlight < 2 {
+		t.Errorf("expected independent groups to be cleaned up concurrently, got at most %d call in flight", mock.maxInFlight)
+	}
+}
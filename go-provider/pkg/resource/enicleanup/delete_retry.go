@@ -0,0 +1,88 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultDeleteAttempts is the default number of times deleting an ENI is
+// attempted before it is tagged for manual cleanup
+const DefaultDeleteAttempts = 3
+
+// DefaultDeleteRetryBaseDelay is the default wait before the first delete
+// retry. Each further retry waits twice as long as the one before.
+const DefaultDeleteRetryBaseDelay = 2 * time.Second
+
+// nonRetryableDeleteErrorCodes are the API error codes for deletes that
+// fail the same way however often they are retried
+var nonRetryableDeleteErrorCodes = []string{
+	"AuthFailure",
+	"UnauthorizedOperation",
+	"OperationNotPermitted",
+	"InvalidParameterValue",
+	"InvalidNetworkInterfaceID.Malformed",
+}
+
+// notFoundErrorCode is the API error code for deleting an ENI that no longer exists
+const notFoundErrorCode = "InvalidNetworkInterfaceID.NotFound"
+
+// deleteRetryWait waits before a delete retry, returning early with the
+// context's error if it is done. Tests replace it to skip the backoff.
+var deleteRetryWait = waitContext
+
+// deleteAttempts returns the configured delete attempts or the default
+func (o CleanupOptions) deleteAttempts() int {
+	if o.DeleteAttempts > 0 {
+		return o.DeleteAttempts
+	}
+	return DefaultDeleteAttempts
+}
+
+// deleteRetryBaseDelay returns the configured base delay or the default
+func (o CleanupOptions) deleteRetryBaseDelay() time.Duration {
+	if o.DeleteRetryBaseDelay > 0 {
+		return o.DeleteRetryBaseDelay
+	}
+	return DefaultDeleteRetryBaseDelay
+}
+
+// deleteWithRetry deletes an ENI, retrying with exponential backoff since
+// ENIs often only become deletable a few seconds after being detached or
+// having their security groups changed. An ENI that is already gone counts
+// as deleted. It returns the last error once every attempt failed, the
+// error can't be fixed by retrying or ctx is done.
+func deleteWithRetry(ctx context.Context, client ENIClient, id string, options CleanupOptions) error {
+	attempts := options.deleteAttempts()
+	delay := options.deleteRetryBaseDelay()
+	for attempt := 1; ; attempt++ {
+		_, err := client.DeleteNetworkInterface(ctx, &ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(id),
+		})
+		if hasErrorCode(err, notFoundErrorCode) {
+			logging.V(5).Infof("ENI %s no longer exists, treating it as deleted", id)
+			return nil
+		}
+		if err == nil || attempt == attempts || ctx.Err() != nil || hasErrorCode(err, nonRetryableDeleteErrorCodes...) {
+			return err
+		}
+
+		logging.V(5).Infof("Deleting ENI %s failed on attempt %d of %d, retrying in %s: %v", id, attempt, attempts, delay, err)
+		if waitErr := deleteRetryWait(ctx, delay); waitErr != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// hasErrorCode reports whether err is an API error with one of the codes
+func hasErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && slices.Contains(codes, apiErr.ErrorCode())
+}
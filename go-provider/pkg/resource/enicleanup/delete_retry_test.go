@@ -0,0 +1,158 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+)
+
+// recordDeleteBackoff skips the waits between delete retries for the rest of
+// the test, returning the delays that would have been waited
+func recordDeleteBackoff(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var mu sync.Mutex
+	var delays []time.Duration
+	original := deleteRetryWait
+	deleteRetryWait = func(ctx context.Context, delay time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delays = append(delays, delay)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { deleteRetryWait = original })
+	return &delays
+}
+
+// flakyDeleteEC2 fails the first failures deletes of each ENI, like an ENI
+// that takes a few seconds to become deletable
+type flakyDeleteEC2 struct {
+	*fakeEC2
+	failures int
+	attempts map[string]int
+}
+
+func (f *flakyDeleteEC2) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	f.mu.Lock()
+	id := aws.ToString(params.NetworkInterfaceId)
+	f.attempts[id]++
+	failed := f.attempts[id] <= f.failures
+	f.mu.Unlock()
+	if failed {
+		return nil, errors.New("InvalidNetworkInterface.InUse: the network interface is currently in use")
+	}
+	return f.fakeEC2.DeleteNetworkInterface(ctx, params, optFns...)
+}
+
+func TestDeleteRetriesWithBackoff(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     int
+		wantAttempts int
+		wantDeleted  bool
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "succeeds on the third attempt",
+			wantAttempts: 3,
+			wantDeleted:  true,
+			wantDelays:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:         "tags for manual cleanup once attempts run out",
+			attempts:     2,
+			wantAttempts: 2,
+			wantDelays:   []time.Duration{time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays := recordDeleteBackoff(t)
+			fake := &flakyDeleteEC2{fakeEC2: newFakeEC2(), failures: 2, attempts: make(map[string]int)}
+
+			result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{
+				{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}},
+			}, CleanupOptions{
+				DefaultSecurityGroupId: aws.String("sg-default"),
+				ClientFactory:          stubFactory(fake),
+				DeleteAttempts:         tt.attempts,
+				DeleteRetryBaseDelay:   time.Second,
+			})
+
+			if fake.attempts["eni-1"] != tt.wantAttempts {
+				t.Errorf("expected %d delete attempts, got %d", tt.wantAttempts, fake.attempts["eni-1"])
+			}
+			if deleted := fake.deleted["eni-1"] == 1; deleted != tt.wantDeleted {
+				t.Errorf("expected deleted %v, got %v", tt.wantDeleted, deleted)
+			}
+			if !slices.Equal(*delays, tt.wantDelays) {
+				t.Errorf("expected backoff %v, got %v", tt.wantDelays, *delays)
+			}
+			tagged := fake.tags["eni-1"]["NeedsManualCleanup"] == "true"
+			if tagged == tt.wantDeleted {
+				t.Errorf("expected tagged for manual cleanup %v, got tags %v", !tt.wantDeleted, fake.tags["eni-1"])
+			}
+			if len(result.CleanedENIs) != 1 {
+				t.Fatalf("expected one cleaned ENI, got %+v", result.CleanedENIs)
+			}
+		})
+	}
+}
+
+// failingDeleteEC2 fails every delete with err
+type failingDeleteEC2 struct {
+	*fakeEC2
+	err      error
+	attempts int
+}
+
+func (f *failingDeleteEC2) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	return nil, f.err
+}
+
+func TestDeleteStopsOnNonRetryableErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		wantAction string
+		wantTagged bool
+	}{
+		{name: "already deleted", code: "InvalidNetworkInterfaceID.NotFound", wantAction: "deleted"},
+		{name: "unauthorized", code: "UnauthorizedOperation", wantAction: "disassociated from security groups (delete failed)", wantTagged: true},
+		{name: "invalid parameter", code: "InvalidParameterValue", wantAction: "disassociated from security groups (delete failed)", wantTagged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays := recordDeleteBackoff(t)
+			fake := &failingDeleteEC2{fakeEC2: newFakeEC2(), err: &smithy.GenericAPIError{Code: tt.code}}
+
+			result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{
+				{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}},
+			}, CleanupOptions{
+				DefaultSecurityGroupId: aws.String("sg-default"),
+				ClientFactory:          stubFactory(fake),
+			})
+
+			if fake.attempts != 1 || len(*delays) != 0 {
+				t.Errorf("expected exactly one delete attempt without backoff, got %d attempts and %v", fake.attempts, *delays)
+			}
+			if len(result.CleanedENIs) != 1 || result.CleanedENIs[0].ActionTaken != tt.wantAction {
+				t.Fatalf("expected the ENI to be %s, got %+v", tt.wantAction, result.CleanedENIs)
+			}
+			if tagged := fake.tags["eni-1"]["NeedsManualCleanup"] == "true"; tagged != tt.wantTagged {
+				t.Errorf("expected tagged for manual cleanup %v, got tags %v", tt.wantTagged, fake.tags["eni-1"])
+			}
+		})
+	}
+}
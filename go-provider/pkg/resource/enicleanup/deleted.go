@@ -0,0 +1,38 @@
+package enicleanup
+
+import "sync"
+
+// DeletedENIs tracks the ENIs deleted during a run. EC2 is eventually
+// consistent, so a describe shortly after a successful delete can still list
+// the ENI; sharing a tracker between detection and cleanup keeps such ENIs
+// from being processed, or counted, twice. It is safe for concurrent use, and
+// a nil tracker tracks nothing.
+type DeletedENIs struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+// NewDeletedENIs creates an empty tracker
+func NewDeletedENIs() *DeletedENIs {
+	return &DeletedENIs{ids: make(map[string]bool)}
+}
+
+// Add records that an ENI was deleted
+func (d *DeletedENIs) Add(id string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ids[id] = true
+}
+
+// Contains checks whether an ENI was deleted during the run
+func (d *DeletedENIs) Contains(id string) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ids[id]
+}
@@ -0,0 +1,72 @@
+package enicleanup
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultDeployTimestampTagKey is the VPC tag deployment pipelines set to the
+// RFC 3339 time of their latest Pulumi or CloudFormation deployment
+const DefaultDeployTimestampTagKey = "eni-cleanup:last-deploy"
+
+// DefaultRecentDeployWindow is how long after a deployment its VPC is left alone
+const DefaultRecentDeployWindow = 30 * time.Minute
+
+// deployTimestampTagKey returns the configured deploy tag key or the default
+func (o DetectOptions) deployTimestampTagKey() string {
+	if o.DeployTimestampTagKey != "" {
+		return o.DeployTimestampTagKey
+	}
+	return DefaultDeployTimestampTagKey
+}
+
+// recentDeployWindow returns the configured quiet period or the default
+func (o DetectOptions) recentDeployWindow() time.Duration {
+	if o.RecentDeployWindow > 0 {
+		return o.RecentDeployWindow
+	}
+	return DefaultRecentDeployWindow
+}
+
+// recentlyDeployedVPCs returns the VPCs whose deploy timestamp tag is within
+// the window, mapped to the tag value. Unparseable timestamps are ignored.
+func recentlyDeployedVPCs(ctx context.Context, client ENIClient, tagKey string, window time.Duration) (map[string]string, error) {
+	deployed := make(map[string]string)
+	paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []string{tagKey},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vpc := range page.Vpcs {
+			for _, tag := range vpc.Tags {
+				if aws.ToString(tag.Key) != tagKey {
+					continue
+				}
+				deployedAt, err := time.Parse(time.RFC3339, aws.ToString(tag.Value))
+				if err != nil {
+					logging.V(5).Infof("Ignoring unparseable %s tag %q on VPC %s", tagKey, aws.ToString(tag.Value), aws.ToString(vpc.VpcId))
+					continue
+				}
+				if time.Since(deployedAt) < window {
+					deployed[aws.ToString(vpc.VpcId)] = aws.ToString(tag.Value)
+				}
+			}
+		}
+	}
+
+	return deployed, nil
+}
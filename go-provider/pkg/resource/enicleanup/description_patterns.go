@@ -0,0 +1,30 @@
+package enicleanup
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// compileDescriptionPatterns compiles ReservedDescriptionPatterns
+func compileDescriptionPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchingDescriptionPattern returns the first pattern matching the
+// description, if any
+func matchingDescriptionPattern(description string, patterns []*regexp.Regexp) (*regexp.Regexp, bool) {
+	for _, pattern := range patterns {
+		if pattern.MatchString(description) {
+			return pattern, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// liveInstanceStates are the instance states that keep a VPC occupied
+var liveInstanceStates = []string{
+	string(types.InstanceStateNamePending),
+	string(types.InstanceStateNameRunning),
+	string(types.InstanceStateNameShuttingDown),
+	string(types.InstanceStateNameStopping),
+	string(types.InstanceStateNameStopped),
+}
+
+// occupiedVPCs returns which of the ENIs' VPCs still contain an instance that
+// hasn't terminated
+func occupiedVPCs(ctx context.Context, client ENIClient, enis []OrphanedENI) (map[string]bool, error) {
+	var vpcIDs []string
+	for _, eni := range enis {
+		if eni.VPCID != "" && !slices.Contains(vpcIDs, eni.VPCID) {
+			vpcIDs = append(vpcIDs, eni.VPCID)
+		}
+	}
+
+	occupied := make(map[string]bool)
+	for start := 0; start < len(vpcIDs); start += maxFilterValues {
+		paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: vpcIDs[start:min(start+maxFilterValues, len(vpcIDs))],
+				},
+				{
+					Name:   aws.String("instance-state-name"),
+					Values: liveInstanceStates,
+				},
+			},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					occupied[aws.ToString(instance.VpcId)] = true
+				}
+			}
+		}
+	}
+	return occupied, nil
+}
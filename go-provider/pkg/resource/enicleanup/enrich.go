@@ -0,0 +1,147 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// DefaultEnrichmentConcurrency is the default bound on concurrent enrichment
+// lookups per region
+const DefaultEnrichmentConcurrency = 4
+
+// enrichmentConcurrency returns the configured enrichment concurrency or the default
+func (o DetectOptions) enrichmentConcurrency() int {
+	if o.EnrichmentConcurrency > 0 {
+		return o.EnrichmentConcurrency
+	}
+	return DefaultEnrichmentConcurrency
+}
+
+// regionEnrichment holds the lookups made about a region's ENIs after the
+// initial describe
+type regionEnrichment struct {
+	// recentDeploys maps recently deployed VPCs to their deploy timestamp
+	recentDeploys map[string]string
+	// deadOwners maps ENIs attached to a dead instance to that instance
+	deadOwners map[string]string
+	// subnetOwners maps the ENIs' existing subnets to the account owning them
+	subnetOwners map[string]string
+	// liveNATGateways holds the NAT gateways of available NAT gateway ENIs
+	// that still exist
+	liveNATGateways map[string]bool
+}
+
+// enrichRegion runs the lookups enabled by the options for a region's ENIs.
+// Each lookup call is an independent task, so they run concurrently, at most
+// EnrichmentConcurrency at a time, instead of serializing the scan.
+func enrichRegion(ctx context.Context, client ENIClient, enis []types.NetworkInterface, options DetectOptions) (regionEnrichment, error) {
+	var enrichment regionEnrichment
+	var tasks []func(context.Context) error
+
+	// VPCs deployed to recently are left alone while the deployment settles
+	if options.RespectRecentDeploys {
+		tasks = append(tasks, func(ctx context.Context) error {
+			deploys, err := recentlyDeployedVPCs(ctx, client, options.deployTimestampTagKey(), options.recentDeployWindow())
+			if err != nil {
+				return fmt.Errorf("error finding recently deployed VPCs: %w", err)
+			}
+			enrichment.recentDeploys = deploys
+			return nil
+		})
+	}
+
+	// Attachments can outlive their instance, leaving ENIs neither cleanly
+	// available nor attached to anything live
+	var mu sync.Mutex
+	live := make(map[string]bool)
+	if options.VerifyAttachmentOwners {
+		for _, batch := range attachedInstanceIDs(enis) {
+			tasks = append(tasks, func(ctx context.Context) error {
+				instanceIDs, err := liveInstances(ctx, client, batch)
+				if err != nil {
+					return fmt.Errorf("error verifying attachment owners: %w", err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range instanceIDs {
+					live[id] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	// ENIs in subnets shared with us through RAM, or by us, belong to the
+	// other side of the share. Subnets missing from the lookup no longer
+	// exist, which EscalationPolicy escalates.
+	if !options.IncludeSharedSubnets || options.EscalationPolicy != nil {
+		enrichment.subnetOwners = make(map[string]string)
+		for _, batch := range eniSubnetIDs(enis) {
+			tasks = append(tasks, func(ctx context.Context) error {
+				owners, err := subnetOwners(ctx, client, batch)
+				if err != nil {
+					return fmt.Errorf("error looking up subnet owners: %w", err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				maps.Copy(enrichment.subnetOwners, owners)
+				return nil
+			})
+		}
+	}
+
+	// NAT gateway ENIs are reserved until their NAT gateway is confirmed gone
+	if options.VerifyNATGateways {
+		enrichment.liveNATGateways = make(map[string]bool)
+		for _, batch := range natGatewayIDs(enis) {
+			tasks = append(tasks, func(ctx context.Context) error {
+				gatewayIDs, err := liveNATGateways(ctx, client, batch)
+				if err != nil {
+					return fmt.Errorf("error verifying NAT gateways: %w", err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range gatewayIDs {
+					enrichment.liveNATGateways[id] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	if err := runConcurrently(ctx, options.enrichmentConcurrency(), tasks); err != nil {
+		return regionEnrichment{}, err
+	}
+
+	if options.VerifyAttachmentOwners {
+		enrichment.deadOwners = deadAttachmentOwners(enis, live)
+	}
+	return enrichment, nil
+}
+
+// runConcurrently runs the tasks with at most concurrency running at once and
+// returns their errors joined
+func runConcurrently(ctx context.Context, concurrency int, tasks []func(context.Context) error) error {
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = task(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
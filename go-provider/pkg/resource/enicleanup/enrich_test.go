@@ -0,0 +1,70 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestRunConcurrentlyBoundsTasks(t *testing.T) {
+	var running, peak atomic.Int32
+	tasks := make([]func(context.Context) error, 10)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			if i == 3 {
+				return errors.New("lookup failed")
+			}
+			return nil
+		}
+	}
+
+	err := runConcurrently(context.Background(), 3, tasks)
+	if err == nil || err.Error() != "lookup failed" {
+		t.Errorf("expected the task error, got %v", err)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("expected at most 3 concurrent tasks, got %d", peak.Load())
+	}
+}
+
+func TestEnrichRegionBatchesAttachmentOwners(t *testing.T) {
+	// More instances than fit in one filter, so the lookup spans batches
+	var enis []types.NetworkInterface
+	fake := newFakeEC2()
+	for i := range maxFilterValues + 5 {
+		instanceID := fmt.Sprintf("i-%d", i)
+		enis = append(enis, types.NetworkInterface{
+			NetworkInterfaceId: aws.String(fmt.Sprintf("eni-%d", i)),
+			Attachment:         &types.NetworkInterfaceAttachment{InstanceId: aws.String(instanceID)},
+		})
+		if i%2 == 0 {
+			fake.instances = append(fake.instances, types.Instance{InstanceId: aws.String(instanceID)})
+		}
+	}
+
+	enrichment, err := enrichRegion(context.Background(), fake, enis, DetectOptions{VerifyAttachmentOwners: true, EnrichmentConcurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (maxFilterValues + 5) / 2; len(enrichment.deadOwners) != want {
+		t.Errorf("expected %d dead owners, got %d", want, len(enrichment.deadOwners))
+	}
+	if enrichment.deadOwners["eni-1"] != "i-1" || enrichment.deadOwners["eni-0"] != "" {
+		t.Errorf("unexpected dead owners: eni-0=%q eni-1=%q", enrichment.deadOwners["eni-0"], enrichment.deadOwners["eni-1"])
+	}
+}
@@ -0,0 +1,134 @@
+package enicleanup
+
+import (
+	"strings"
+	"time"
+)
+
+// Escalation tiers, from least to most clearly orphaned
+const (
+	// TierLow marks a young or tagged ENI
+	TierLow = "low"
+	// TierMedium marks an untagged ENI older than EscalationPolicy.MediumAfter
+	TierMedium = "medium"
+	// TierHigh marks an ENI older than EscalationPolicy.HighAfter or whose
+	// subnet no longer exists
+	TierHigh = "high"
+)
+
+// Actions an EscalationPolicy can take for a tier
+const (
+	// EscalationActionReport only reports the ENI, skipping it during cleanup
+	EscalationActionReport = "report"
+	// EscalationActionDisassociate removes the ENI's security groups without
+	// deleting it, as DisassociateOnly does
+	EscalationActionDisassociate = "disassociate"
+	// EscalationActionDelete cleans up and deletes the ENI
+	EscalationActionDelete = "delete"
+)
+
+// DefaultEscalationActions are the actions for tiers missing from
+// EscalationPolicy.Actions
+var DefaultEscalationActions = map[string]string{
+	TierLow:    EscalationActionReport,
+	TierMedium: EscalationActionDisassociate,
+	TierHigh:   EscalationActionDelete,
+}
+
+// SkipReasonEscalationReportOnly marks a candidate the EscalationPolicy
+// only reports
+const SkipReasonEscalationReportOnly = "escalation-report-only"
+
+// EscalationPolicy classifies each candidate into a tier during detection by
+// how clearly orphaned it is, and maps the tiers to the action cleanup takes,
+// so one deployment can observe young ENIs while reaping abandoned ones.
+// Ages come from CreatedTagKey or UseCloudTrailForAge; ENIs of unknown age
+// only reach TierHigh through a missing subnet.
+type EscalationPolicy struct {
+	// MediumAfter is the age from which an untagged ENI is TierMedium. Tags
+	// with the reserved aws: and eni-cleanup: prefixes, and CreatedTagKey,
+	// don't count as tagged.
+	MediumAfter time.Duration
+	// HighAfter is the age from which any ENI is TierHigh
+	HighAfter time.Duration
+	// Actions maps tiers to EscalationAction values, overriding
+	// DefaultEscalationActions
+	Actions map[string]string
+}
+
+// validate checks the thresholds are ordered and the actions known
+func (p *EscalationPolicy) validate() []error {
+	if p == nil {
+		return nil
+	}
+
+	var errs []error
+	if p.MediumAfter < 0 || p.HighAfter < 0 {
+		errs = append(errs, invalidOption("escalationPolicy", "escalation thresholds must not be negative"))
+	}
+	if p.HighAfter > 0 && p.HighAfter < p.MediumAfter {
+		errs = append(errs, invalidOption("escalationPolicy", "escalation highAfter (%s) must not be shorter than mediumAfter (%s)", p.HighAfter, p.MediumAfter))
+	}
+	for tier, action := range p.Actions {
+		if _, ok := DefaultEscalationActions[tier]; !ok {
+			errs = append(errs, invalidOption("escalationPolicy", "unknown escalation tier %q, expected low, medium or high", tier))
+		}
+		if action != EscalationActionReport && action != EscalationActionDisassociate && action != EscalationActionDelete {
+			errs = append(errs, invalidOption("escalationPolicy", "unknown escalation action %q for tier %q, expected report, disassociate or delete", action, tier))
+		}
+	}
+	return errs
+}
+
+// classify returns the tier of a candidate. subnets holds the subnets that
+// exist; an ENI whose subnet isn't in it is dangling.
+func (p *EscalationPolicy) classify(eni OrphanedENI, subnets map[string]string, createdTagKey *string, now time.Time) string {
+	if _, ok := subnets[eni.SubnetID]; eni.SubnetID != "" && !ok {
+		return TierHigh
+	}
+
+	age := now.Sub(eni.CreatedTime)
+	if p.HighAfter > 0 && age >= p.HighAfter {
+		return TierHigh
+	}
+	if p.MediumAfter > 0 && age >= p.MediumAfter && !userTagged(eni.Tags, createdTagKey) {
+		return TierMedium
+	}
+	return TierLow
+}
+
+// userTagged reports whether any tag was set by a user rather than by AWS or
+// this tool
+func userTagged(tags map[string]string, createdTagKey *string) bool {
+	for key := range tags {
+		if strings.HasPrefix(key, "aws:") || strings.HasPrefix(key, "eni-cleanup:") {
+			continue
+		}
+		if createdTagKey != nil && key == *createdTagKey {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// action returns what cleanup does with an ENI of the tier. Without a
+// policy every ENI is deleted; with one, unclassified ENIs are TierLow.
+func (p *EscalationPolicy) action(tier string) string {
+	if p == nil {
+		return EscalationActionDelete
+	}
+	if tier == "" {
+		tier = TierLow
+	}
+	if action, ok := p.Actions[tier]; ok {
+		return action
+	}
+	return DefaultEscalationActions[tier]
+}
+
+// disassociateOnly reports whether cleanup leaves the ENI in place after
+// removing its security groups
+func (o CleanupOptions) disassociateOnly(eni OrphanedENI) bool {
+	return o.DisassociateOnly || o.EscalationPolicy.action(eni.Tier) == EscalationActionDisassociate
+}
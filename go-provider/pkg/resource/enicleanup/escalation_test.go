@@ -0,0 +1,103 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestEscalationPolicyClassify(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := &EscalationPolicy{MediumAfter: 7 * 24 * time.Hour, HighAfter: 30 * 24 * time.Hour}
+	subnets := map[string]string{"subnet-1": "111111111111"}
+	createdTagKey := "CreatedAt"
+
+	tests := []struct {
+		name string
+		eni  OrphanedENI
+		want string
+	}{
+		{name: "young", eni: OrphanedENI{SubnetID: "subnet-1", CreatedTime: now.Add(-time.Hour)}, want: TierLow},
+		{name: "old but tagged", eni: OrphanedENI{SubnetID: "subnet-1", CreatedTime: now.Add(-10 * 24 * time.Hour), Tags: map[string]string{"team": "web"}}, want: TierLow},
+		{name: "old and untagged", eni: OrphanedENI{SubnetID: "subnet-1", CreatedTime: now.Add(-10 * 24 * time.Hour), Tags: map[string]string{"CreatedAt": "x", "aws:cloudformation:stack-name": "web", StrikesTagKey: "1"}}, want: TierMedium},
+		{name: "ancient and tagged", eni: OrphanedENI{SubnetID: "subnet-1", CreatedTime: now.Add(-40 * 24 * time.Hour), Tags: map[string]string{"team": "web"}}, want: TierHigh},
+		{name: "dangling subnet", eni: OrphanedENI{SubnetID: "subnet-gone", CreatedTime: now, Tags: map[string]string{"team": "web"}}, want: TierHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.classify(tt.eni, subnets, &createdTagKey, now); got != tt.want {
+				t.Errorf("expected tier %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEscalationPolicyValidate(t *testing.T) {
+	policy := &EscalationPolicy{
+		MediumAfter: 30 * 24 * time.Hour,
+		HighAfter:   7 * 24 * time.Hour,
+		Actions:     map[string]string{"critical": EscalationActionDelete, TierLow: "ignore"},
+	}
+	err := DetectOptions{LogLevel: "info", EscalationPolicy: policy}.Validate()
+	if got := len(validationFailures(err)); got != 3 {
+		t.Errorf("expected 3 escalation policy failures, got %d: %v", got, err)
+	}
+}
+
+func TestEscalationPolicyActsByTier(t *testing.T) {
+	old := time.Now().Add(-10 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	ancient := time.Now().Add(-40 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	young := availableENI("eni-young")
+	young.TagSet = []types.Tag{{Key: aws.String("CreatedAt"), Value: aws.String(time.Now().UTC().Format(time.RFC3339))}}
+	stale := availableENI("eni-stale")
+	stale.TagSet = []types.Tag{{Key: aws.String("CreatedAt"), Value: aws.String(old)}}
+	abandoned := availableENI("eni-abandoned")
+	abandoned.TagSet = []types.Tag{{Key: aws.String("CreatedAt"), Value: aws.String(ancient)}, {Key: aws.String("team"), Value: aws.String("web")}}
+	dangling := availableENI("eni-dangling")
+	dangling.SubnetId = aws.String("subnet-gone")
+	dangling.TagSet = []types.Tag{{Key: aws.String("CreatedAt"), Value: aws.String(time.Now().UTC().Format(time.RFC3339))}}
+
+	fake := newFakeEC2(young, stale, abandoned, dangling)
+	fake.subnets = []types.Subnet{{SubnetId: aws.String("subnet-1")}}
+	useFakeEC2(t, fake)
+
+	policy := &EscalationPolicy{MediumAfter: 7 * 24 * time.Hour, HighAfter: 30 * 24 * time.Hour}
+	enis, err := DetectOrphanedENIs(context.Background(), []string{"us-east-1"}, DetectOptions{
+		CreatedTagKey:    aws.String("CreatedAt"),
+		EscalationPolicy: policy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiers := make(map[string]string)
+	for _, eni := range enis {
+		tiers[eni.ID] = eni.Tier
+	}
+	want := map[string]string{"eni-young": TierLow, "eni-stale": TierMedium, "eni-abandoned": TierHigh, "eni-dangling": TierHigh}
+	for id, tier := range want {
+		if tiers[id] != tier {
+			t.Errorf("expected %s in tier %s, got %q", id, tier, tiers[id])
+		}
+	}
+
+	reasons := make(map[string]string)
+	CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		EscalationPolicy:       policy,
+		OnENIResult:            func(r ENIResult) { reasons[r.ENI.ID] = r.Reason },
+	})
+
+	if len(fake.modified["eni-young"]) != 0 || reasons["eni-young"] != SkipReasonEscalationReportOnly {
+		t.Errorf("expected the low tier ENI to only be reported, got reason %q", reasons["eni-young"])
+	}
+	if len(fake.modified["eni-stale"]) == 0 || fake.deleted["eni-stale"] != 0 {
+		t.Errorf("expected the medium tier ENI to be disassociated but kept")
+	}
+	if fake.deleted["eni-abandoned"] != 1 || fake.deleted["eni-dangling"] != 1 {
+		t.Errorf("expected the high tier ENIs to be deleted, got %v", fake.deleted)
+	}
+}
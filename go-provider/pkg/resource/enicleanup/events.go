@@ -0,0 +1,107 @@
+package enicleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// EventBridge event fields for the completion event
+const (
+	EventSource                     = "eni-cleanup"
+	EventDetailTypeCleanupCompleted = "CleanupCompleted"
+)
+
+// eventBridgeAPI is the subset of the EventBridge client used to publish events
+type eventBridgeAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// newEventBridgeClient creates the EventBridge client. Tests replace it with a fake.
+var newEventBridgeClient = func(cfg aws.Config) eventBridgeAPI {
+	return eventbridge.NewFromConfig(cfg)
+}
+
+// CleanupCompletedDetail is the detail of the CleanupCompleted event
+type CleanupCompletedDetail struct {
+	DryRun             bool         `json:"dryRun"`
+	TagOnly            bool         `json:"tagOnly"`
+	Candidates         int          `json:"candidates"`
+	SuccessCount       int          `json:"successCount"`
+	FailureCount       int          `json:"failureCount"`
+	SkippedCount       int          `json:"skippedCount"`
+	CleanedENIs        []CleanedENI `json:"cleanedEnis"`
+	VerificationFailed []string     `json:"verificationFailed,omitempty"`
+	DeadLetter         []CleanedENI `json:"deadLetter,omitempty"`
+	Errors             []string     `json:"errors,omitempty"`
+}
+
+// publishCleanupCompleted puts a CleanupCompleted event carrying the run
+// summary on the configured event bus. Failures are logged rather than
+// returned, so a missing bus or permission never fails the cleanup itself.
+func publishCleanupCompleted(ctx context.Context, options CleanupOptions, candidates int, result CleanupResult) {
+	if options.EventBusName == nil || *options.EventBusName == "" {
+		return
+	}
+
+	if err := putCleanupCompleted(ctx, *options.EventBusName, CleanupCompletedDetail{
+		DryRun:             options.DryRun,
+		TagOnly:            options.TagOnly,
+		Candidates:         candidates,
+		SuccessCount:       result.SuccessCount,
+		FailureCount:       result.FailureCount,
+		SkippedCount:       result.SkippedCount,
+		CleanedENIs:        result.CleanedENIs,
+		VerificationFailed: result.VerificationFailed,
+		DeadLetter:         result.DeadLetter,
+		Errors:             result.Errors,
+	}); err != nil {
+		logging.V(5).Infof("Failed to publish %s event to %s: %v", EventDetailTypeCleanupCompleted, *options.EventBusName, err)
+		return
+	}
+
+	logging.V(5).Infof("Published %s event to %s", EventDetailTypeCleanupCompleted, *options.EventBusName)
+}
+
+// putCleanupCompleted sends a single CleanupCompleted event
+func putCleanupCompleted(ctx context.Context, busName string, detail CleanupCompletedDetail) error {
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to encode event detail: %w", err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+
+	resp, err := newEventBridgeClient(cfg).PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []ebtypes.PutEventsRequestEntry{{
+			EventBusName: aws.String(busName),
+			Source:       aws.String(EventSource),
+			DetailType:   aws.String(EventDetailTypeCleanupCompleted),
+			Detail:       aws.String(string(data)),
+			Time:         aws.Time(time.Now()),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.FailedEntryCount > 0 && len(resp.Entries) > 0 {
+		entry := resp.Entries[0]
+		return fmt.Errorf("event rejected: %s: %s", aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage))
+	}
+
+	return nil
+}
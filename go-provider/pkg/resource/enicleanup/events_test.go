@@ -0,0 +1,74 @@
+package enicleanup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+)
+
+// fakeEventBridge records the events put on it
+type fakeEventBridge struct {
+	inputs []*eventbridge.PutEventsInput
+	err    error
+}
+
+func (f *fakeEventBridge) PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	f.inputs = append(f.inputs, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &eventbridge.PutEventsOutput{}, nil
+}
+
+func useFakeEventBridge(t *testing.T, fake *fakeEventBridge) {
+	t.Helper()
+	original := newEventBridgeClient
+	newEventBridgeClient = func(cfg aws.Config) eventBridgeAPI { return fake }
+	t.Cleanup(func() { newEventBridgeClient = original })
+}
+
+func TestCleanupPublishesCompletionEvent(t *testing.T) {
+	useFakeEC2(t, newFakeEC2(types.NetworkInterface{NetworkInterfaceId: aws.String("eni-1")}))
+	bus := &fakeEventBridge{}
+	useFakeEventBridge(t, bus)
+
+	enis := []OrphanedENI{{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"}}
+	CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{EventBusName: aws.String("ops")})
+
+	if len(bus.inputs) != 1 || len(bus.inputs[0].Entries) != 1 {
+		t.Fatalf("expected a single event, got %+v", bus.inputs)
+	}
+	entry := bus.inputs[0].Entries[0]
+	if aws.ToString(entry.EventBusName) != "ops" || aws.ToString(entry.Source) != EventSource || aws.ToString(entry.DetailType) != EventDetailTypeCleanupCompleted {
+		t.Errorf("unexpected event envelope: %+v", entry)
+	}
+
+	var detail CleanupCompletedDetail
+	if err := json.Unmarshal([]byte(aws.ToString(entry.Detail)), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if detail.Candidates != 1 || detail.SuccessCount != 1 || len(detail.CleanedENIs) != 1 {
+		t.Errorf("expected the run summary in the detail, got %+v", detail)
+	}
+}
+
+func TestCompletionEventFailureIsTolerated(t *testing.T) {
+	useFakeEC2(t, newFakeEC2(types.NetworkInterface{NetworkInterfaceId: aws.String("eni-1")}))
+	bus := &fakeEventBridge{err: errors.New("AccessDeniedException")}
+	useFakeEventBridge(t, bus)
+
+	enis := []OrphanedENI{{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"}}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{EventBusName: aws.String("ops")})
+
+	if len(bus.inputs) != 1 {
+		t.Fatalf("expected the put to be attempted once, got %d", len(bus.inputs))
+	}
+	if result.SuccessCount != 1 || len(result.Errors) != 0 {
+		t.Errorf("expected the failed put not to affect the result, got %+v", result)
+	}
+}
@@ -0,0 +1,293 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// fakeEC2 is an in-memory ENIClient. Describe calls always return the configured
+// network interfaces, narrowed only by network-interface-id, tag-key, vpc-id
+// and subnet-id filters, like an eventually consistent describe would shortly after a delete.
+type fakeEC2 struct {
+	mu                sync.Mutex
+	networkInterfaces []types.NetworkInterface
+	deleted           map[string]int
+	modified          map[string][]string
+	tags              map[string]map[string]string
+	// securityGroups maps security group IDs to their VPC
+	securityGroups map[string]string
+	// instances are returned by DescribeInstances, narrowed by an instance-id filter
+	instances []types.Instance
+	// vpcs are returned by DescribeVpcs
+	vpcs []types.Vpc
+	// subnets are returned by DescribeSubnets
+	subnets []types.Subnet
+	// natGateways are returned by DescribeNatGateways, narrowed by a nat-gateway-id filter
+	natGateways []types.NatGateway
+	// detached records the attachment IDs DetachNetworkInterface was called with
+	detached []string
+	// createTagsCalls counts CreateTags calls
+	createTagsCalls int
+	// deletedTags maps ENI IDs to the tag keys DeleteTags removed
+	deletedTags map[string][]string
+	// wedged ENIs never finish deleting; the call blocks until ctx is done
+	wedged map[string]bool
+	// failModify and failTags make ModifyNetworkInterfaceAttribute and
+	// CreateTags fail for these ENIs
+	failModify map[string]bool
+	failTags   map[string]bool
+	// describeMaxResults records the MaxResults of each DescribeNetworkInterfaces call
+	describeMaxResults []*int32
+	// describeFilters records the Filters of each DescribeNetworkInterfaces call
+	describeFilters [][]types.Filter
+	// describePageSize, if set, splits DescribeNetworkInterfaces results into
+	// pages of this many ENIs, linked by NextToken
+	describePageSize int
+	// associations maps ENI IDs to the association IDs of their Elastic
+	// IPs. Deleting an ENI fails until DisassociateAddress removes them.
+	associations map[string][]string
+}
+
+// newFakeEC2 creates a fake returning the given network interfaces
+func newFakeEC2(enis ...types.NetworkInterface) *fakeEC2 {
+	return &fakeEC2{
+		networkInterfaces: enis,
+		deleted:           make(map[string]int),
+		modified:          make(map[string][]string),
+		tags:              make(map[string]map[string]string),
+		securityGroups:    make(map[string]string),
+		deletedTags:       make(map[string][]string),
+	}
+}
+
+// useFakeEC2 makes every client created by the package use the fake for the rest of the test
+func useFakeEC2(t *testing.T, fake *fakeEC2) {
+	t.Helper()
+	original := newEC2Client
+	newEC2Client = func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient { return fake }
+	t.Cleanup(func() { newEC2Client = original })
+}
+
+// availableENI builds a detached ENI in a VPC
+func availableENI(id string) types.NetworkInterface {
+	return types.NetworkInterface{
+		NetworkInterfaceId: aws.String(id),
+		VpcId:              aws.String("vpc-1"),
+		SubnetId:           aws.String("subnet-1"),
+		Description:        aws.String("leftover interface"),
+		Status:             types.NetworkInterfaceStatusAvailable,
+		Groups:             []types.GroupIdentifier{{GroupId: aws.String("sg-app")}},
+	}
+}
+
+func (f *fakeEC2) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.describeMaxResults = append(f.describeMaxResults, params.MaxResults)
+	f.describeFilters = append(f.describeFilters, params.Filters)
+	enis := f.networkInterfaces
+	for _, filter := range params.Filters {
+		var matches func(types.NetworkInterface) bool
+		switch aws.ToString(filter.Name) {
+		case "network-interface-id":
+			matches = func(eni types.NetworkInterface) bool {
+				return slices.Contains(filter.Values, aws.ToString(eni.NetworkInterfaceId))
+			}
+		case "tag-key":
+			matches = func(eni types.NetworkInterface) bool {
+				return slices.ContainsFunc(eni.TagSet, func(tag types.Tag) bool {
+					return slices.Contains(filter.Values, aws.ToString(tag.Key))
+				})
+			}
+		case "vpc-id":
+			matches = func(eni types.NetworkInterface) bool {
+				return slices.Contains(filter.Values, aws.ToString(eni.VpcId))
+			}
+		case "subnet-id":
+			matches = func(eni types.NetworkInterface) bool {
+				return slices.Contains(filter.Values, aws.ToString(eni.SubnetId))
+			}
+		default:
+			continue
+		}
+		var matched []types.NetworkInterface
+		for _, eni := range enis {
+			if matches(eni) {
+				matched = append(matched, eni)
+			}
+		}
+		enis = matched
+	}
+	if f.describePageSize > 0 {
+		start, _ := strconv.Atoi(aws.ToString(params.NextToken))
+		end := min(start+f.describePageSize, len(enis))
+		output := &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: enis[start:end]}
+		if end < len(enis) {
+			output.NextToken = aws.String(strconv.Itoa(end))
+		}
+		return output, nil
+	}
+	return &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: enis}, nil
+}
+
+func (f *fakeEC2) DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	return &ec2.DescribeAvailabilityZonesOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	ids := params.GroupIds
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "group-id" {
+			ids = append(ids, filter.Values...)
+		}
+	}
+	if len(ids) > 0 {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var groups []types.SecurityGroup
+		for _, id := range ids {
+			if vpcID, ok := f.securityGroups[id]; ok {
+				groups = append(groups, types.SecurityGroup{GroupId: aws.String(id), VpcId: aws.String(vpcID)})
+			}
+		}
+		return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil
+	}
+	return &ec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []types.SecurityGroup{{GroupId: aws.String("sg-default")}},
+	}, nil
+}
+
+func (f *fakeEC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var instances []types.Instance
+	for _, instance := range f.instances {
+		matched := true
+		for _, filter := range params.Filters {
+			switch aws.ToString(filter.Name) {
+			case "instance-id":
+				matched = matched && slices.Contains(filter.Values, aws.ToString(instance.InstanceId))
+			case "vpc-id":
+				matched = matched && slices.Contains(filter.Values, aws.ToString(instance.VpcId))
+			case "instance-state-name":
+				matched = matched && instance.State != nil && slices.Contains(filter.Values, string(instance.State.Name))
+			}
+		}
+		if matched {
+			instances = append(instances, instance)
+		}
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: instances}}}, nil
+}
+
+func (f *fakeEC2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &ec2.DescribeVpcsOutput{Vpcs: f.vpcs}, nil
+}
+
+func (f *fakeEC2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &ec2.DescribeSubnetsOutput{Subnets: f.subnets}, nil
+}
+
+func (f *fakeEC2) ModifyNetworkInterfaceAttribute(ctx context.Context, params *ec2.ModifyNetworkInterfaceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.ModifyNetworkInterfaceAttributeOutput, error) {
+	if f.failModify[aws.ToString(params.NetworkInterfaceId)] {
+		return nil, errors.New("UnauthorizedOperation")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modified[aws.ToString(params.NetworkInterfaceId)] = params.Groups
+	return &ec2.ModifyNetworkInterfaceAttributeOutput{}, nil
+}
+
+func (f *fakeEC2) DetachNetworkInterface(ctx context.Context, params *ec2.DetachNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DetachNetworkInterfaceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.detached = append(f.detached, aws.ToString(params.AttachmentId))
+	return &ec2.DetachNetworkInterfaceOutput{}, nil
+}
+
+func (f *fakeEC2) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	if f.wedged[aws.ToString(params.NetworkInterfaceId)] {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.associations[aws.ToString(params.NetworkInterfaceId)]) > 0 {
+		return nil, errors.New("InvalidNetworkInterface.InUse: the network interface has an associated address")
+	}
+	f.deleted[aws.ToString(params.NetworkInterfaceId)]++
+	return &ec2.DeleteNetworkInterfaceOutput{}, nil
+}
+
+func (f *fakeEC2) DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, associations := range f.associations {
+		f.associations[id] = slices.DeleteFunc(associations, func(association string) bool {
+			return association == aws.ToString(params.AssociationId)
+		})
+	}
+	return &ec2.DisassociateAddressOutput{}, nil
+}
+
+func (f *fakeEC2) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createTagsCalls++
+	for _, id := range params.Resources {
+		if f.failTags[id] {
+			return nil, errors.New("RequestLimitExceeded")
+		}
+	}
+	for _, id := range params.Resources {
+		if f.tags[id] == nil {
+			f.tags[id] = make(map[string]string)
+		}
+		for _, tag := range params.Tags {
+			f.tags[id][aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (f *fakeEC2) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range params.Resources {
+		for _, tag := range params.Tags {
+			delete(f.tags[id], aws.ToString(tag.Key))
+			f.deletedTags[id] = append(f.deletedTags[id], aws.ToString(tag.Key))
+		}
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var gateways []types.NatGateway
+	for _, gateway := range f.natGateways {
+		matched := true
+		for _, filter := range params.Filter {
+			if aws.ToString(filter.Name) == "nat-gateway-id" && !slices.Contains(filter.Values, aws.ToString(gateway.NatGatewayId)) {
+				matched = false
+			}
+		}
+		if matched {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return &ec2.DescribeNatGatewaysOutput{NatGateways: gateways}, nil
+}
@@ -0,0 +1,61 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// eniFingerprint summarizes the state of an ENI that decides whether it is
+// safe to clean up: its status, attachment and security groups
+func eniFingerprint(eni types.NetworkInterface) string {
+	var attachment string
+	if eni.Attachment != nil {
+		attachment = strings.Join([]string{
+			aws.ToString(eni.Attachment.AttachmentId),
+			string(eni.Attachment.Status),
+			aws.ToString(eni.Attachment.InstanceId),
+		}, "/")
+	}
+
+	var groups []string
+	for _, group := range eni.Groups {
+		groups = append(groups, aws.ToString(group.GroupId))
+	}
+	slices.Sort(groups)
+
+	return fmt.Sprintf("status=%s;attachment=%s;groups=%s", eni.Status, attachment, strings.Join(groups, ","))
+}
+
+// confirmUnchanged re-describes an ENI and reports whether its fingerprint
+// still matches the one recorded at detection. An ENI without a recorded
+// fingerprint, e.g. from an older plan, can't be confirmed and counts as
+// changed, as does one that no longer exists.
+func confirmUnchanged(ctx context.Context, client ENIClient, eni OrphanedENI) (bool, string, error) {
+	if eni.Fingerprint == "" {
+		return false, "no fingerprint was recorded at detection", nil
+	}
+
+	enis, err := findNetworkInterfaces(ctx, client, []types.Filter{
+		{
+			Name:   aws.String("network-interface-id"),
+			Values: []string{eni.ID},
+		},
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("error re-describing ENI %s: %w", eni.ID, err)
+	}
+	if len(enis) == 0 {
+		return false, "no longer exists", nil
+	}
+
+	current := eniFingerprint(enis[0])
+	if current != eni.Fingerprint {
+		return false, fmt.Sprintf("was %s, now %s", eni.Fingerprint, current), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,69 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestConfirmUnchangedSkipsChangedENIs(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-same"), availableENI("eni-attached"), availableENI("eni-regrouped"))
+	fake.securityGroups["sg-default"] = "vpc-1"
+	useFakeEC2(t, fake)
+
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detected.ENIs) != 3 {
+		t.Fatalf("expected 3 candidates, got %+v", detected.ENIs)
+	}
+
+	// Between detection and cleanup, one ENI is attached and another regrouped
+	fake.networkInterfaces[1].Status = types.NetworkInterfaceStatusInUse
+	fake.networkInterfaces[1].Attachment = &types.NetworkInterfaceAttachment{
+		AttachmentId: aws.String("eni-attach-1"),
+		InstanceId:   aws.String("i-new"),
+		Status:       types.AttachmentStatusAttached,
+	}
+	fake.networkInterfaces[2].Groups = append(fake.networkInterfaces[2].Groups, types.GroupIdentifier{GroupId: aws.String("sg-new")})
+
+	reasons := make(map[string]string)
+	result := CleanupOrphanedENIsWithOptions(context.Background(), detected.ENIs, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		ConfirmUnchanged:       true,
+		OnENIResult: func(r ENIResult) {
+			reasons[r.ENI.ID] = r.Reason
+		},
+	})
+
+	if result.SuccessCount != 1 || result.SkippedCount != 2 {
+		t.Errorf("expected 1 cleaned and 2 skipped, got %d and %d", result.SuccessCount, result.SkippedCount)
+	}
+	if fake.deleted["eni-same"] != 1 {
+		t.Errorf("expected the unchanged ENI to be deleted")
+	}
+	for _, id := range []string{"eni-attached", "eni-regrouped"} {
+		if fake.deleted[id] != 0 || len(fake.modified[id]) != 0 {
+			t.Errorf("expected %s not to be touched", id)
+		}
+		if reasons[id] != SkipReasonChangedSinceDetection {
+			t.Errorf("expected %s to be skipped as changed since detection, got %q", id, reasons[id])
+		}
+	}
+}
+
+func TestConfirmUnchangedWithoutFingerprint(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"))
+	useFakeEC2(t, fake)
+
+	unchanged, change, err := confirmUnchanged(context.Background(), fake, OrphanedENI{ID: "eni-1", Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchanged || change == "" {
+		t.Errorf("expected an ENI without a fingerprint to count as changed, got %v %q", unchanged, change)
+	}
+}
@@ -0,0 +1,88 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetOrphanedENIs is a read-only provider function listing the ENIs the
+// ENICleanup resource would act on, without creating a resource in state.
+// It only runs detection, so dryRun and the other cleanup arguments have no
+// effect.
+type GetOrphanedENIs struct{}
+
+// GetOrphanedENIsArgs are the inputs to GetOrphanedENIs. Detection uses the
+// same arguments as the ENICleanup resource; cleanup arguments are ignored.
+type GetOrphanedENIsArgs struct {
+	ResourceArgs
+}
+
+// GetOrphanedENIsResult is the output of GetOrphanedENIs
+type GetOrphanedENIsResult struct {
+	// Enis are the detected orphaned ENIs, ordered by region and ID
+	Enis []DetectedENI `pulumi:"enis"`
+	// Skipped are the ENIs that were deliberately spared, with the reason why
+	Skipped []SkippedENI `pulumi:"skipped,optional"`
+	// FailedAccounts lists the accountRoles accounts that couldn't be scanned
+	FailedAccounts []string `pulumi:"failedAccounts,optional"`
+	// FailedRegions lists the regions that couldn't be scanned
+	FailedRegions []string `pulumi:"failedRegions,optional"`
+}
+
+// Call implements the getOrphanedENIs function.
+func (GetOrphanedENIs) Call(ctx context.Context, args GetOrphanedENIsArgs) (GetOrphanedENIsResult, error) {
+	args.Regions = DefaultRegions(ctx, args.Regions)
+
+	options, err := args.detectOptions()
+	if err != nil {
+		return GetOrphanedENIsResult{}, err
+	}
+
+	if err := Preflight(ctx, args.preflightOptions()); err != nil {
+		return GetOrphanedENIsResult{}, err
+	}
+
+	detected, failedAccounts, err := args.ResourceArgs.detectOnly(ctx, options)
+	if err != nil {
+		return GetOrphanedENIsResult{}, err
+	}
+
+	return GetOrphanedENIsResult{
+		Enis:           newDetectedENIs(detected.ENIs),
+		Skipped:        detected.Skipped,
+		FailedAccounts: failedAccounts,
+		FailedRegions:  failedRegions(detected.Errors),
+	}, nil
+}
+
+// Annotate sets annotations for the function.
+func (GetOrphanedENIs) Annotate() map[string]interface{} {
+	return map[string]interface{}{
+		"pulumi:token": "aws-eni-cleanup:index:getOrphanedENIs",
+		"description":  "Lists the orphaned ENIs ENICleanup would clean up, without modifying anything.",
+	}
+}
+
+// detectOnly detects orphaned ENIs in the resource's regions without cleaning
+// them up, in each of accountRoles when set. It also returns the accounts
+// that couldn't be scanned, failing only if none could.
+func (args ResourceArgs) detectOnly(ctx context.Context, options DetectOptions) (DetectResult, []string, error) {
+	if len(args.AccountRoles) == 0 {
+		detected, err := DetectOrphanedENIsWithDetails(ctx, args.Regions, options)
+		if err != nil {
+			return DetectResult{}, nil, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+		}
+		return detected, nil, nil
+	}
+
+	concurrency := 0
+	if args.AccountConcurrency != nil {
+		concurrency = *args.AccountConcurrency
+	}
+	detected, result := CombineAccountResults(RunAccounts(ctx, args.Regions, args.AccountRoles, options, nil, concurrency))
+	if len(result.FailedAccounts) == len(args.AccountRoles) {
+		return DetectResult{}, nil, fmt.Errorf("failed to scan every AWS account: %s", strings.Join(result.Errors, "; "))
+	}
+	return detected, result.FailedAccounts, nil
+}
@@ -0,0 +1,34 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestGetOrphanedENIs(t *testing.T) {
+	reserved := availableENI("eni-reserved")
+	reserved.Description = aws.String("ELB app/web/0123456789abcdef")
+	fake := newFakeEC2(availableENI("eni-2"), availableENI("eni-1"), reserved)
+	useFakeEC2(t, fake)
+
+	// Without dryRun the ENIs are still only listed
+	dryRun := false
+	result, err := GetOrphanedENIs{}.Call(context.Background(), GetOrphanedENIsArgs{
+		ResourceArgs: ResourceArgs{Regions: []string{"us-east-1"}, DryRun: &dryRun},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Enis) != 2 || result.Enis[0].ID != "eni-1" || result.Enis[1].ID != "eni-2" {
+		t.Errorf("expected eni-1 and eni-2, got %+v", result.Enis)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Reason != SkipReasonReservedDescription {
+		t.Errorf("expected the ELB ENI skipped, got %+v", result.Skipped)
+	}
+	if len(fake.deleted) != 0 || len(fake.modified) != 0 || len(fake.tags) != 0 {
+		t.Error("expected listing not to modify any ENIs")
+	}
+}
@@ -0,0 +1,86 @@
+package enicleanup
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// attachedInstanceID returns the instance an ENI's attachment references, if
+// it is an instance in the ENI's own account that can be looked up
+func attachedInstanceID(eni types.NetworkInterface) string {
+	if eni.Attachment == nil {
+		return ""
+	}
+	instanceID := aws.ToString(eni.Attachment.InstanceId)
+	if instanceID == "" {
+		return ""
+	}
+	owner := aws.ToString(eni.Attachment.InstanceOwnerId)
+	if owner != "" && owner != aws.ToString(eni.OwnerId) {
+		return ""
+	}
+	return instanceID
+}
+
+// attachedInstanceIDs returns the distinct instances the ENIs' attachments
+// reference, in batches small enough for a single instance-id filter
+func attachedInstanceIDs(enis []types.NetworkInterface) [][]string {
+	var instanceIDs []string
+	seen := make(map[string]bool)
+	for _, eni := range enis {
+		if instanceID := attachedInstanceID(eni); instanceID != "" && !seen[instanceID] {
+			seen[instanceID] = true
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+	}
+
+	var batches [][]string
+	for start := 0; start < len(instanceIDs); start += maxFilterValues {
+		batches = append(batches, instanceIDs[start:min(start+maxFilterValues, len(instanceIDs))])
+	}
+	return batches
+}
+
+// liveInstances returns which of the given instances exist and haven't terminated
+func liveInstances(ctx context.Context, client ENIClient, instanceIDs []string) ([]string, error) {
+	// Filtering by ID, unlike passing InstanceIds, doesn't fail on unknown IDs
+	var live []string
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: instanceIDs,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.State != nil && instance.State.Name == types.InstanceStateNameTerminated {
+					continue
+				}
+				live = append(live, aws.ToString(instance.InstanceId))
+			}
+		}
+	}
+	return live, nil
+}
+
+// deadAttachmentOwners returns the ENIs whose attachment references an
+// instance not in live, mapped to that instance
+func deadAttachmentOwners(enis []types.NetworkInterface, live map[string]bool) map[string]string {
+	dead := make(map[string]string)
+	for _, eni := range enis {
+		if instanceID := attachedInstanceID(eni); instanceID != "" && !live[instanceID] {
+			dead[aws.ToString(eni.NetworkInterfaceId)] = instanceID
+		}
+	}
+	return dead
+}
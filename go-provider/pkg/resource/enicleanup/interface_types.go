@@ -0,0 +1,45 @@
+package enicleanup
+
+import (
+	"slices"
+)
+
+// DefaultSkipInterfaceTypes are the interface types of ENIs AWS services
+// create and manage themselves. Unlike descriptions, the interface type is
+// set by EC2, so it identifies these ENIs reliably. Transit gateway, load
+// balancer, trunk and branch ENIs have dedicated handling instead.
+var DefaultSkipInterfaceTypes = []string{
+	"lambda",
+	"vpc_endpoint",
+	"natGateway",
+	"api_gateway_managed",
+	"gateway_load_balancer",
+	"quicksight",
+	"iot_rules_managed",
+	"aws_codestar_connections_managed",
+}
+
+// skipsInterfaceType reports whether detection spares ENIs of an interface
+// type. Types listed in OnlyInterfaceTypes or the detection profile's
+// InterfaceTypes are asked for explicitly, so they override
+// DefaultSkipInterfaceTypes, but never SkipInterfaceTypes.
+func (o DetectOptions) skipsInterfaceType(interfaceType string, profile DetectionProfile) bool {
+	if slices.Contains(o.SkipInterfaceTypes, interfaceType) {
+		return true
+	}
+	if slices.Contains(o.OnlyInterfaceTypes, interfaceType) || slices.Contains(profile.InterfaceTypes, interfaceType) {
+		return false
+	}
+	return slices.Contains(DefaultSkipInterfaceTypes, interfaceType)
+}
+
+// validateInterfaceTypes checks no interface type is both skipped and required
+func (o DetectOptions) validateInterfaceTypes() []error {
+	var errs []error
+	for _, interfaceType := range o.OnlyInterfaceTypes {
+		if slices.Contains(o.SkipInterfaceTypes, interfaceType) {
+			errs = append(errs, invalidOption("skipInterfaceTypes", "interface type %q is in both skipInterfaceTypes and onlyInterfaceTypes", interfaceType))
+		}
+	}
+	return errs
+}
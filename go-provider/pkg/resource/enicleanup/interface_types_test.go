@@ -0,0 +1,82 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestInterfaceTypeFiltering(t *testing.T) {
+	lambda := availableENI("eni-lambda")
+	lambda.InterfaceType = types.NetworkInterfaceType("lambda")
+	plain := availableENI("eni-plain")
+	plain.InterfaceType = types.NetworkInterfaceType("interface")
+
+	tests := []struct {
+		name        string
+		options     DetectOptions
+		wantFound   []string
+		wantSkipped []SkippedENI
+	}{
+		{
+			name:      "managed types are skipped by default",
+			wantFound: []string{"eni-plain"},
+			wantSkipped: []SkippedENI{
+				{ID: "eni-lambda", Region: "us-east-1", Reason: SkipReasonInterfaceType, Detail: "lambda"},
+			},
+		},
+		{
+			name:      "only types opt in to managed types",
+			options:   DetectOptions{OnlyInterfaceTypes: []string{"lambda"}},
+			wantFound: []string{"eni-lambda"},
+		},
+		{
+			name:    "skip types add to the defaults",
+			options: DetectOptions{SkipInterfaceTypes: []string{"interface"}},
+			wantSkipped: []SkippedENI{
+				{ID: "eni-lambda", Region: "us-east-1", Reason: SkipReasonInterfaceType, Detail: "lambda"},
+				{ID: "eni-plain", Region: "us-east-1", Reason: SkipReasonInterfaceType, Detail: "interface"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeEC2(t, newFakeEC2(lambda, plain))
+
+			result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []string
+			for _, eni := range result.ENIs {
+				found = append(found, eni.ID)
+				if eni.InterfaceType == "" {
+					t.Errorf("expected ENI %s to record its interface type", eni.ID)
+				}
+			}
+			if !slices.Equal(found, tt.wantFound) {
+				t.Errorf("found %v, want %v", found, tt.wantFound)
+			}
+			if !slices.Equal(result.Skipped, tt.wantSkipped) {
+				t.Errorf("skipped %+v, want %+v", result.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestInterfaceTypeInBothListsIsInvalid(t *testing.T) {
+	err := DetectOptions{
+		SkipInterfaceTypes: []string{"lambda"},
+		OnlyInterfaceTypes: []string{"lambda"},
+	}.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Property != "skipInterfaceTypes" {
+		t.Errorf("expected a skipInterfaceTypes validation error, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// loadBalancerInterfaceTypes are the ENI interface types created by Elastic Load Balancing
+var loadBalancerInterfaceTypes = map[string]bool{
+	"load_balancer":         true,
+	"network_load_balancer": true,
+	"gateway_load_balancer": true,
+}
+
+// loadBalancerRequesterID is the requester ID used by Elastic Load Balancing
+const loadBalancerRequesterID = "amazon-elb"
+
+// isLoadBalancerENI checks whether an ENI was created by Elastic Load Balancing,
+// using its interface type and requester rather than its description
+func isLoadBalancerENI(eni types.NetworkInterface) bool {
+	if loadBalancerInterfaceTypes[string(eni.InterfaceType)] {
+		return true
+	}
+	return aws.ToString(eni.RequesterId) == loadBalancerRequesterID
+}
+
+// loadBalancerIDFromDescription extracts the "app/name/id" style identifier from
+// an ELBv2 ENI description such as "ELB app/my-alb/50dc6c495c0c9188"
+func loadBalancerIDFromDescription(description string) (string, bool) {
+	id, ok := strings.CutPrefix(description, "ELB ")
+	if !ok {
+		return "", false
+	}
+	for _, prefix := range []string{"app/", "net/", "gwy/"} {
+		if strings.HasPrefix(id, prefix) && strings.Count(id, "/") == 2 {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// loadBalancerLookup resolves ELBv2 load balancers in a region, listing them once
+type loadBalancerLookup struct {
+	client *elasticloadbalancingv2.Client
+	arns   map[string]string
+}
+
+// newLoadBalancerLookup creates a lookup backed by the given ELBv2 client
+func newLoadBalancerLookup(client *elasticloadbalancingv2.Client) *loadBalancerLookup {
+	return &loadBalancerLookup{client: client}
+}
+
+// load lists every load balancer in the region, keyed by its "type/name/id" identifier
+func (l *loadBalancerLookup) load(ctx context.Context) error {
+	if l.arns != nil {
+		return nil
+	}
+
+	arns := make(map[string]string)
+	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(l.client, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+		for _, lb := range page.LoadBalancers {
+			arn := aws.ToString(lb.LoadBalancerArn)
+			if _, id, ok := strings.Cut(arn, ":loadbalancer/"); ok {
+				arns[id] = arn
+			}
+		}
+	}
+
+	l.arns = arns
+	return nil
+}
+
+// check resolves the load balancer owning an ENI. It returns the load balancer's
+// ARN and whether it still exists. When the load balancer is gone, the ARN is
+// reconstructed from the ENI's description so it can still be reported.
+func (l *loadBalancerLookup) check(ctx context.Context, eni types.NetworkInterface, region string) (string, bool, error) {
+	id, ok := loadBalancerIDFromDescription(aws.ToString(eni.Description))
+	if !ok {
+		return "", false, fmt.Errorf("cannot determine load balancer from description %q", aws.ToString(eni.Description))
+	}
+
+	if err := l.load(ctx); err != nil {
+		return "", false, err
+	}
+
+	if arn, exists := l.arns[id]; exists {
+		return arn, true, nil
+	}
+
+	partition := "aws"
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		partition = "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		partition = "aws-us-gov"
+	}
+	arn := fmt.Sprintf("arn:%s:elasticloadbalancing:%s:%s:loadbalancer/%s", partition, region, aws.ToString(eni.OwnerId), id)
+	return arn, false, nil
+}
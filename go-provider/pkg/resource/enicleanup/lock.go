@@ -0,0 +1,169 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultLockTTL is how long a cleanup lock is held before other runs may
+// take it over, so a crashed run can't block cleanup forever
+const DefaultLockTTL = 30 * time.Minute
+
+// lockPollInterval is how often a run waiting for a held lock retries
+const lockPollInterval = 5 * time.Second
+
+// dynamoDBAPI is the subset of the DynamoDB client used for the cleanup lock
+type dynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// newDynamoDBClient creates the DynamoDB client. Tests replace it with a fake.
+var newDynamoDBClient = func(cfg aws.Config) dynamoDBAPI {
+	return dynamodb.NewFromConfig(cfg)
+}
+
+// lookupAccountID returns the AWS account of the caller's credentials. Tests
+// replace it to avoid calling STS.
+var lookupAccountID = func(ctx context.Context, cfg aws.Config) (string, error) {
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(identity.Account), nil
+}
+
+// cleanupLock is an advisory lock held in a DynamoDB table, allowing one
+// cleanup run per account-region at a time. Items are keyed by LockId and
+// carry an ExpiresAt epoch, which can also drive the table's TTL.
+type cleanupLock struct {
+	client  dynamoDBAPI
+	table   string
+	account string
+	owner   string
+	ttl     time.Duration
+	wait    time.Duration
+}
+
+// newCleanupLock returns the lock configured by options, or nil when
+// LockTableName isn't set
+func newCleanupLock(ctx context.Context, options CleanupOptions) (*cleanupLock, error) {
+	if options.LockTableName == nil || *options.LockTableName == "" {
+		return nil, nil
+	}
+
+	cfg, err := loadConfig(ctx, "", options.ConfigOptions, options.credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for the cleanup lock: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+
+	account, err := lookupAccountID(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the AWS account for the cleanup lock: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	ttl := options.LockTTL
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	return &cleanupLock{
+		client:  newDynamoDBClient(cfg),
+		table:   *options.LockTableName,
+		account: account,
+		owner:   fmt.Sprintf("%s/%d/%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		ttl:     ttl,
+		wait:    options.LockWaitTimeout,
+	}, nil
+}
+
+// key returns the lock item key for a region
+func (l *cleanupLock) key(region string) string {
+	return l.account + "/" + region
+}
+
+// acquire takes the lock for a region, waiting up to the configured timeout
+// while another run holds it. ok is false when the lock is still held.
+func (l *cleanupLock) acquire(ctx context.Context, region string) (bool, error) {
+	deadline := time.Now().Add(l.wait)
+	for {
+		ok, err := l.tryAcquire(ctx, region)
+		if err != nil || ok {
+			return ok, err
+		}
+		if time.Now().Add(lockPollInterval).After(deadline) {
+			return false, nil
+		}
+
+		logging.V(5).Infof("Cleanup lock for %s is held by another run, retrying in %s", l.key(region), lockPollInterval)
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryAcquire writes the lock item unless an unexpired one exists
+func (l *cleanupLock) tryAcquire(ctx context.Context, region string) (bool, error) {
+	now := time.Now()
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.table),
+		Item: map[string]ddbtypes.AttributeValue{
+			"LockId":    &ddbtypes.AttributeValueMemberS{Value: l.key(region)},
+			"Owner":     &ddbtypes.AttributeValueMemberS{Value: l.owner},
+			"ExpiresAt": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(l.ttl).Unix(), 10)},
+		},
+		ConditionExpression:      aws.String("attribute_not_exists(LockId) OR #expires < :now"),
+		ExpressionAttributeNames: map[string]string{"#expires": "ExpiresAt"},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{
+			":now": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		var held *ddbtypes.ConditionalCheckFailedException
+		if errors.As(err, &held) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire cleanup lock %s in table %s: %w", l.key(region), l.table, err)
+	}
+
+	logging.V(5).Infof("Acquired cleanup lock %s until %s", l.key(region), now.Add(l.ttl).Format(time.RFC3339))
+	return true, nil
+}
+
+// release deletes the lock item if this run still owns it. Failures are
+// logged, as the lock expires on its own.
+func (l *cleanupLock) release(ctx context.Context, region string) {
+	_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(l.table),
+		Key: map[string]ddbtypes.AttributeValue{
+			"LockId": &ddbtypes.AttributeValueMemberS{Value: l.key(region)},
+		},
+		ConditionExpression:      aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]string{"#owner": "Owner"},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{
+			":owner": &ddbtypes.AttributeValueMemberS{Value: l.owner},
+		},
+	})
+	if err != nil {
+		logging.V(5).Infof("Failed to release cleanup lock %s: %v", l.key(region), err)
+		return
+	}
+
+	logging.V(5).Infof("Released cleanup lock %s", l.key(region))
+}
@@ -0,0 +1,129 @@
+package enicleanup
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeLockTable emulates the conditional writes of the lock table
+type fakeLockTable struct {
+	mu      sync.Mutex
+	items   map[string]map[string]ddbtypes.AttributeValue
+	deletes int
+}
+
+func newFakeLockTable() *fakeLockTable {
+	return &fakeLockTable{items: make(map[string]map[string]ddbtypes.AttributeValue)}
+}
+
+func attrValue(item map[string]ddbtypes.AttributeValue, name string) string {
+	switch v := item[name].(type) {
+	case *ddbtypes.AttributeValueMemberS:
+		return v.Value
+	case *ddbtypes.AttributeValueMemberN:
+		return v.Value
+	}
+	return ""
+}
+
+// hold records a lock held by another run until expiresAt
+func (f *fakeLockTable) hold(key string, expiresAt time.Time) {
+	f.items[key] = map[string]ddbtypes.AttributeValue{
+		"LockId":    &ddbtypes.AttributeValueMemberS{Value: key},
+		"Owner":     &ddbtypes.AttributeValueMemberS{Value: "other-run"},
+		"ExpiresAt": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+}
+
+func (f *fakeLockTable) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := attrValue(params.Item, "LockId")
+	if existing, ok := f.items[key]; ok {
+		expires, _ := strconv.ParseInt(attrValue(existing, "ExpiresAt"), 10, 64)
+		now, _ := strconv.ParseInt(attrValue(params.ExpressionAttributeValues, ":now"), 10, 64)
+		if expires >= now {
+			return nil, &ddbtypes.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+		}
+	}
+	f.items[key] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeLockTable) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := attrValue(params.Key, "LockId")
+	if existing, ok := f.items[key]; ok && attrValue(existing, "Owner") == attrValue(params.ExpressionAttributeValues, ":owner") {
+		delete(f.items, key)
+		f.deletes++
+		return &dynamodb.DeleteItemOutput{}, nil
+	}
+	return nil, &ddbtypes.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+}
+
+func useFakeLockTable(t *testing.T, fake *fakeLockTable) {
+	t.Helper()
+	originalClient, originalAccount := newDynamoDBClient, lookupAccountID
+	newDynamoDBClient = func(cfg aws.Config) dynamoDBAPI { return fake }
+	lookupAccountID = func(ctx context.Context, cfg aws.Config) (string, error) { return "111111111111", nil }
+	t.Cleanup(func() { newDynamoDBClient, lookupAccountID = originalClient, originalAccount })
+}
+
+func TestCleanupLockSkipsRegionHeldByAnotherRun(t *testing.T) {
+	fake := newFakeEC2()
+	useFakeEC2(t, fake)
+	table := newFakeLockTable()
+	table.hold("111111111111/us-east-1", time.Now().Add(time.Hour))
+	useFakeLockTable(t, table)
+
+	enis := []OrphanedENI{
+		{ID: "eni-east", Region: "us-east-1", VPCID: "vpc-1"},
+		{ID: "eni-west", Region: "us-west-2", VPCID: "vpc-2"},
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		LockTableName:          aws.String("eni-cleanup-locks"),
+	})
+
+	if result.SuccessCount != 1 || result.SkippedCount != 1 {
+		t.Fatalf("expected the locked region to be skipped, got %+v", result)
+	}
+	if fake.deleted["eni-east"] != 0 || fake.deleted["eni-west"] != 1 {
+		t.Errorf("expected only the unlocked region to be cleaned, got %v", fake.deleted)
+	}
+	if table.deletes != 1 {
+		t.Errorf("expected the acquired lock to be released, got %d releases", table.deletes)
+	}
+	if attrValue(table.items["111111111111/us-east-1"], "Owner") != "other-run" {
+		t.Error("expected the other run's lock to be left in place")
+	}
+}
+
+func TestExpiredCleanupLockIsTakenOver(t *testing.T) {
+	fake := newFakeEC2()
+	useFakeEC2(t, fake)
+	table := newFakeLockTable()
+	table.hold("111111111111/us-east-1", time.Now().Add(-time.Minute))
+	useFakeLockTable(t, table)
+
+	enis := []OrphanedENI{{ID: "eni-east", Region: "us-east-1", VPCID: "vpc-1"}}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		LockTableName:          aws.String("eni-cleanup-locks"),
+	})
+
+	if result.SuccessCount != 1 {
+		t.Fatalf("expected a crashed run's expired lock not to block cleanup, got %+v", result)
+	}
+	if len(table.items) != 0 {
+		t.Errorf("expected the lock to be released, got %v", table.items)
+	}
+}
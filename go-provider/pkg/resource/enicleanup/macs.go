@@ -0,0 +1,37 @@
+package enicleanup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalizeMAC lowercases a MAC address or prefix and strips ":", "-" and "."
+// separators, so "0A:1B" and "0a-1b" compare equal
+func normalizeMAC(mac string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac))
+}
+
+// validateMACPrefixes checks that each prefix is a non-empty run of hex digits
+func validateMACPrefixes(prefixes []string) error {
+	for _, prefix := range prefixes {
+		normalized := normalizeMAC(prefix)
+		if normalized == "" || strings.Trim(normalized, "0123456789abcdef") != "" {
+			return fmt.Errorf("invalid MAC prefix %q", prefix)
+		}
+	}
+	return nil
+}
+
+// matchingMACPrefix returns the first prefix the MAC address starts with, if any
+func matchingMACPrefix(mac string, prefixes []string) (string, bool) {
+	normalized := normalizeMAC(mac)
+	if normalized == "" {
+		return "", false
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(normalized, normalizeMAC(prefix)) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+package enicleanup
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Default tag keys written to ENIs whose cleanup failed
+const (
+	DefaultManualCleanupTagKey        = "NeedsManualCleanup"
+	DefaultAttemptedCleanupTimeTagKey = "AttemptedCleanupTime"
+	DefaultDeletionErrorTagKey        = "DeletionError"
+)
+
+// maxTagValueLength is the longest tag value AWS accepts, in Unicode characters
+const maxTagValueLength = 256
+
+// tagValuePunctuation are the characters other than letters, digits and
+// spaces allowed in AWS tag values
+const tagValuePunctuation = "+-=._:/@"
+
+// sanitizeTagValue makes an arbitrary string, such as an error message, a
+// valid AWS tag value: disallowed characters become underscores, whitespace
+// becomes a space, and the result is truncated to maxTagValueLength
+func sanitizeTagValue(value string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range value {
+		if length == maxTagValueLength {
+			break
+		}
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == ' ', strings.ContainsRune(tagValuePunctuation, r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		default:
+			b.WriteRune('_')
+		}
+		length++
+	}
+	return b.String()
+}
+
+// manualCleanupTagKey returns the configured manual cleanup tag key or the default
+func (o CleanupOptions) manualCleanupTagKey() string {
+	if o.ManualCleanupTagKey != "" {
+		return o.ManualCleanupTagKey
+	}
+	return DefaultManualCleanupTagKey
+}
+
+// attemptedCleanupTimeTagKey returns the configured attempt time tag key or the default
+func (o CleanupOptions) attemptedCleanupTimeTagKey() string {
+	if o.AttemptedCleanupTimeTagKey != "" {
+		return o.AttemptedCleanupTimeTagKey
+	}
+	return DefaultAttemptedCleanupTimeTagKey
+}
+
+// deletionErrorTagKey returns the configured deletion error tag key or the default
+func (o CleanupOptions) deletionErrorTagKey() string {
+	if o.DeletionErrorTagKey != "" {
+		return o.DeletionErrorTagKey
+	}
+	return DefaultDeletionErrorTagKey
+}
+
+// manualCleanupTags returns the tags marking an ENI for manual cleanup
+func (o CleanupOptions) manualCleanupTags(errorMsg string, now time.Time) []types.Tag {
+	tags := []types.Tag{
+		{
+			Key:   aws.String(o.manualCleanupTagKey()),
+			Value: aws.String("true"),
+		},
+		{
+			Key:   aws.String(o.attemptedCleanupTimeTagKey()),
+			Value: aws.String(now.UTC().Format(time.RFC3339)),
+		},
+	}
+	if !o.OmitDeletionErrorTag {
+		tags = append(tags, types.Tag{
+			Key:   aws.String(o.deletionErrorTagKey()),
+			Value: aws.String(sanitizeTagValue(errorMsg)),
+		})
+	}
+	return tags
+}
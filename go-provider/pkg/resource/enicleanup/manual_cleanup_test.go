@@ -0,0 +1,116 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestSanitizeTagValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "allowed characters",
+			value: "Failed: eni-1 in us-east-1/vpc-1 (retry=2) user@example.com",
+			want:  "Failed: eni-1 in us-east-1/vpc-1 _retry=2_ user@example.com",
+		},
+		{
+			name:  "disallowed characters",
+			value: "api error \"InvalidParameter\": <eni> can't be deleted; 50% done\n\tretry #3 & *",
+			want:  "api error _InvalidParameter_: _eni_ can_t be deleted_ 50_ done  retry _3 _ _",
+		},
+		{
+			name:  "unicode letters",
+			value: "Fehler: Schnittstelle gelöscht",
+			want:  "Fehler: Schnittstelle gelöscht",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeTagValue(tt.value); got != tt.want {
+				t.Errorf("sanitizeTagValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	long := sanitizeTagValue(strings.Repeat("é", 300))
+	if n := utf8.RuneCountInString(long); n != maxTagValueLength {
+		t.Errorf("expected a long value to be truncated to %d characters, got %d", maxTagValueLength, n)
+	}
+}
+
+func TestCustomManualCleanupTags(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-1"), availableENI("eni-2"))
+	fake.failModify = map[string]bool{"eni-1": true, "eni-2": true}
+	useFakeEC2(t, fake)
+
+	CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"}}, CleanupOptions{
+		DefaultSecurityGroupId:     aws.String("sg-default"),
+		ManualCleanupTagKey:        "ops:needs-cleanup",
+		AttemptedCleanupTimeTagKey: "ops:attempted-at",
+		DeletionErrorTagKey:        "ops:error",
+	})
+	tags := fake.tags["eni-1"]
+	if tags["ops:needs-cleanup"] != "true" || tags["ops:attempted-at"] == "" || tags["ops:error"] != "UnauthorizedOperation" {
+		t.Errorf("expected the custom manual cleanup tags, got %v", tags)
+	}
+	if _, ok := tags[DefaultManualCleanupTagKey]; ok {
+		t.Errorf("expected the default tag keys not to be written, got %v", tags)
+	}
+
+	CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{{ID: "eni-2", Region: "us-east-1", VPCID: "vpc-1"}}, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		OmitDeletionErrorTag:   true,
+	})
+	tags = fake.tags["eni-2"]
+	if _, ok := tags[DefaultDeletionErrorTagKey]; ok || tags[DefaultManualCleanupTagKey] != "true" {
+		t.Errorf("expected the manual cleanup tags without the error, got %v", tags)
+	}
+}
+
+func TestReconcileManualCleanupTags(t *testing.T) {
+	manualTags := []types.Tag{
+		{Key: aws.String(DefaultManualCleanupTagKey), Value: aws.String("true")},
+		{Key: aws.String(DefaultDeletionErrorTagKey), Value: aws.String("InvalidNetworkInterface.InUse")},
+	}
+	orphan := availableENI("eni-orphan")
+	orphan.TagSet = manualTags
+	// Since tagged, the ENI was taken over by EKS and is no longer an orphan
+	reclaimed := availableENI("eni-reclaimed")
+	reclaimed.Description = aws.String("Amazon EKS cluster")
+	reclaimed.TagSet = manualTags
+	fake := newFakeEC2(orphan, reclaimed)
+	useFakeEC2(t, fake)
+
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result CleanupResult
+	ReconcileManualCleanupTags(context.Background(), detected, CleanupOptions{}, &result)
+	if len(fake.deletedTags) != 0 || len(result.ReconciledENIs) != 0 {
+		t.Fatalf("expected no reconciliation without ReconcileTags, got %v", fake.deletedTags)
+	}
+
+	ReconcileManualCleanupTags(context.Background(), detected, CleanupOptions{ReconcileTags: true}, &result)
+	if !slices.Equal(result.ReconciledENIs, []string{"eni-reclaimed"}) {
+		t.Errorf("expected only eni-reclaimed to be reconciled, got %v", result.ReconciledENIs)
+	}
+	if _, ok := fake.deletedTags["eni-orphan"]; ok {
+		t.Errorf("expected the still orphaned ENI to keep its tags")
+	}
+	want := []string{DefaultManualCleanupTagKey, DefaultAttemptedCleanupTimeTagKey, DefaultDeletionErrorTagKey}
+	if got := fake.deletedTags["eni-reclaimed"]; !slices.Equal(got, want) {
+		t.Errorf("expected tags %v to be removed, got %v", want, got)
+	}
+}
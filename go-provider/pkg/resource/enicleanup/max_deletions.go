@@ -0,0 +1,55 @@
+package enicleanup
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SkipReasonMaxDeletions marks a candidate cleanup spared because
+// CleanupOptions.MaxDeletions ENIs were already deleted
+const SkipReasonMaxDeletions = "max-deletions-reached"
+
+// deletionCap enforces MaxDeletions across a run. Deletions in flight count
+// toward the cap until they fail, so concurrent workers never overshoot it.
+type deletionCap struct {
+	mu       sync.Mutex
+	max      *int
+	reserved int
+	reached  bool
+}
+
+// newDeletionCap creates a cap of max deletions, or no cap when max is nil
+func newDeletionCap(max *int) *deletionCap {
+	return &deletionCap{max: max}
+}
+
+// reserve claims a deletion. Once the cap is reached it returns false, and
+// the message explaining so the first time only, to record in Errors once.
+func (c *deletionCap) reserve() (bool, string) {
+	if c.max == nil {
+		return true, ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reserved < *c.max {
+		c.reserved++
+		return true, ""
+	}
+	if c.reached {
+		return false, ""
+	}
+	c.reached = true
+	return false, fmt.Sprintf("Reached maxDeletions of %d: stopped deleting and skipped the remaining candidates", *c.max)
+}
+
+// release returns a reserved deletion that didn't happen
+func (c *deletionCap) release() {
+	if c.max == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reserved--
+}
@@ -0,0 +1,58 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestMaxDeletions(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxDeletions *int
+		failModify   map[string]bool
+		concurrency  int
+		wantDeleted  int
+		wantSkipped  int
+		wantCapHit   bool
+	}{
+		{name: "no limit when unset", wantDeleted: 5},
+		{name: "stops at the cap", maxDeletions: aws.Int(2), wantDeleted: 2, wantSkipped: 3, wantCapHit: true},
+		{name: "concurrent workers don't overshoot", maxDeletions: aws.Int(2), concurrency: 4, wantDeleted: 2, wantSkipped: 3, wantCapHit: true},
+		{name: "zero deletes nothing", maxDeletions: aws.Int(0), wantSkipped: 5, wantCapHit: true},
+		{name: "failed deletions don't count", maxDeletions: aws.Int(4), failModify: map[string]bool{"eni-0": true}, wantDeleted: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var enis []OrphanedENI
+			for i := range 5 {
+				enis = append(enis, OrphanedENI{ID: fmt.Sprintf("eni-%d", i), Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}})
+			}
+			fake := newFakeEC2()
+			fake.failModify = tt.failModify
+
+			result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{
+				DefaultSecurityGroupId: aws.String("sg-default"),
+				ClientFactory:          stubFactory(fake),
+				MaxDeletions:           tt.maxDeletions,
+				DeleteConcurrency:      tt.concurrency,
+			})
+
+			if len(fake.deleted) != tt.wantDeleted || result.SkippedCount != tt.wantSkipped {
+				t.Errorf("expected %d deleted and %d skipped, got %d and %d", tt.wantDeleted, tt.wantSkipped, len(fake.deleted), result.SkippedCount)
+			}
+			capHit := 0
+			for _, errMsg := range result.Errors {
+				if strings.Contains(errMsg, "maxDeletions") {
+					capHit++
+				}
+			}
+			if capHit > 1 || (capHit == 1) != tt.wantCapHit {
+				t.Errorf("expected the cap reported %v, once, got %v", tt.wantCapHit, result.Errors)
+			}
+		})
+	}
+}
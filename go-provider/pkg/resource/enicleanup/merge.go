@@ -0,0 +1,61 @@
+package enicleanup
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// mergeDetectResults merges per-region results into one, reporting each ENI
+// once. An ENI can be seen by more than one scan when regions overlap, and the
+// scans may disagree about it; the most conservative decision wins, so an ENI
+// skipped by any scan is never a candidate. Otherwise the first occurrence is
+// kept, preserving scan order.
+func mergeDetectResults(results []DetectResult) DetectResult {
+	skippedBy := make(map[string]SkippedENI)
+	for _, result := range results {
+		for _, eni := range result.Skipped {
+			if _, seen := skippedBy[eni.ID]; !seen {
+				skippedBy[eni.ID] = eni
+			}
+		}
+	}
+
+	var merged DetectResult
+	candidates := make(map[string]bool)
+	skipped := make(map[string]bool)
+	for _, result := range results {
+		for _, eni := range result.ENIs {
+			if skip, ok := skippedBy[eni.ID]; ok {
+				logging.V(5).Infof("ENI %s is a candidate in %s but was skipped in %s (%s); skipping it",
+					eni.ID, eni.Region, skip.Region, skip.Reason)
+				continue
+			}
+			if candidates[eni.ID] {
+				continue
+			}
+			candidates[eni.ID] = true
+			merged.ENIs = append(merged.ENIs, eni)
+		}
+		for _, eni := range result.Skipped {
+			if skipped[eni.ID] {
+				continue
+			}
+			skipped[eni.ID] = true
+			merged.Skipped = append(merged.Skipped, eni)
+		}
+		merged.AbortedVPCs = append(merged.AbortedVPCs, result.AbortedVPCs...)
+	}
+	return merged
+}
+
+// sortOrphanedENIs orders ENIs by region and then ID, so results don't depend
+// on which region's scan finished first
+func sortOrphanedENIs(enis []OrphanedENI) {
+	sort.Slice(enis, func(i, j int) bool {
+		if enis[i].Region != enis[j].Region {
+			return enis[i].Region < enis[j].Region
+		}
+		return enis[i].ID < enis[j].ID
+	})
+}
@@ -0,0 +1,62 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestMergeDetectResultsProtectedWins(t *testing.T) {
+	results := []DetectResult{
+		{
+			ENIs: []OrphanedENI{
+				{ID: "eni-1", Region: "us-east-1"},
+				{ID: "eni-2", Region: "us-east-1"},
+			},
+		},
+		{
+			ENIs:    []OrphanedENI{{ID: "eni-2", Region: "us-east-1"}},
+			Skipped: []SkippedENI{{ID: "eni-1", Region: "us-east-1", Reason: SkipReasonProtectedTag, Detail: "keep"}},
+		},
+		{
+			Skipped: []SkippedENI{{ID: "eni-1", Region: "us-east-1", Reason: SkipReasonExcludedCIDR, Detail: "10.0.0.0/8"}},
+		},
+	}
+
+	merged := mergeDetectResults(results)
+
+	if len(merged.ENIs) != 1 || merged.ENIs[0].ID != "eni-2" {
+		t.Errorf("expected only eni-2 to remain a candidate, got %+v", merged.ENIs)
+	}
+	want := []SkippedENI{{ID: "eni-1", Region: "us-east-1", Reason: SkipReasonProtectedTag, Detail: "keep"}}
+	if !slices.Equal(merged.Skipped, want) {
+		t.Errorf("expected the first skip to be reported once, got %+v", merged.Skipped)
+	}
+}
+
+func TestOverlappingRegionsReportProtectedENIOnce(t *testing.T) {
+	protected := availableENI("eni-protected")
+	protected.TagSet = []types.Tag{{Key: aws.String("keep"), Value: aws.String("true")}}
+	// The fake returns the same ENIs in every region, so both scans see them
+	useFakeEC2(t, newFakeEC2(protected))
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1", "us-west-2"}, DetectOptions{
+		PerRegionOptions: map[string]DetectOptions{
+			"us-west-2": {ExcludeTagKeys: []string{"keep"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.ENIs) != 0 {
+		t.Errorf("expected the protected ENI not to be a candidate, got %+v", result.ENIs)
+	}
+	want := []SkippedENI{{ID: "eni-protected", Region: "us-west-2", Reason: SkipReasonProtectedTag, Detail: "keep"}}
+	if !slices.Equal(result.Skipped, want) {
+		t.Errorf("expected %+v, got %+v", want, result.Skipped)
+	}
+}
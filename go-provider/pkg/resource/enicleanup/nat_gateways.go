@@ -0,0 +1,91 @@
+package enicleanup
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// natGatewayIDPattern matches the NAT gateway ID in a NAT gateway ENI's
+// description, e.g. "Interface for NAT Gateway nat-0123456789abcdef0"
+var natGatewayIDPattern = regexp.MustCompile(`\bnat-[0-9a-f]+\b`)
+
+// natGatewayID returns the NAT gateway an ENI was created for, identified by
+// its interface type or its "NAT Gateway" description
+func natGatewayID(eni types.NetworkInterface) (string, bool) {
+	description := aws.ToString(eni.Description)
+	if eni.InterfaceType != types.NetworkInterfaceTypeNatGateway && !strings.Contains(description, "NAT Gateway") {
+		return "", false
+	}
+	id := natGatewayIDPattern.FindString(description)
+	return id, id != ""
+}
+
+// natGatewayIDs returns the distinct NAT gateways the available ENIs were
+// created for, in batches small enough for a single nat-gateway-id filter.
+// Attached NAT gateway ENIs are in use, so they are never looked up.
+func natGatewayIDs(enis []types.NetworkInterface) [][]string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, eni := range enis {
+		if eni.Status != types.NetworkInterfaceStatusAvailable {
+			continue
+		}
+		if id, ok := natGatewayID(eni); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var batches [][]string
+	for start := 0; start < len(ids); start += maxFilterValues {
+		batches = append(batches, ids[start:min(start+maxFilterValues, len(ids))])
+	}
+	return batches
+}
+
+// liveNATGateways returns which of the given NAT gateways still exist. NAT
+// gateways that are deleted, failed or no longer returned at all are dead;
+// one that is still deleting counts as live until it is gone.
+func liveNATGateways(ctx context.Context, client ENIClient, ids []string) ([]string, error) {
+	// Filtering by ID, unlike passing NatGatewayIds, doesn't fail on unknown IDs
+	var live []string
+	paginator := ec2.NewDescribeNatGatewaysPaginator(client, &ec2.DescribeNatGatewaysInput{
+		Filter: []types.Filter{
+			{
+				Name:   aws.String("nat-gateway-id"),
+				Values: ids,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, gateway := range page.NatGateways {
+			if gateway.State == types.NatGatewayStateDeleted || gateway.State == types.NatGatewayStateFailed {
+				continue
+			}
+			live = append(live, aws.ToString(gateway.NatGatewayId))
+		}
+	}
+	return live, nil
+}
+
+// deadNATGateway returns the NAT gateway an available ENI was left behind by,
+// if VerifyNATGateways confirmed it no longer exists
+func deadNATGateway(eni types.NetworkInterface, live map[string]bool, options DetectOptions) (string, bool) {
+	if !options.VerifyNATGateways || eni.Status != types.NetworkInterfaceStatusAvailable {
+		return "", false
+	}
+	id, ok := natGatewayID(eni)
+	if !ok || live[id] {
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,214 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// OnDestroyResource is a two-phase ENI cleanup resource. Create only detects
+// and records candidate ENIs; Delete cleans up the recorded candidates, so
+// cleanup happens when the resources it is attached to are destroyed.
+type OnDestroyResource struct{}
+
+// OnDestroyState represents the state of the two-phase ENI cleanup resource.
+type OnDestroyState struct {
+	// Input fields
+	ResourceArgs
+
+	// Output fields
+	Candidates []DetectedENI `pulumi:"candidates"`
+}
+
+// DetectedENI is the recorded form of an orphaned ENI detected at create time.
+type DetectedENI struct {
+	ID                  string            `pulumi:"id"`
+	Region              string            `pulumi:"region"`
+	VpcID               string            `pulumi:"vpcId"`
+	SubnetID            string            `pulumi:"subnetId"`
+	AvailabilityZone    string            `pulumi:"availabilityZone"`
+	Description         string            `pulumi:"description"`
+	InterfaceType       string            `pulumi:"interfaceType,optional"`
+	RequesterID         string            `pulumi:"requesterId,optional"`
+	AttachmentState     string            `pulumi:"attachmentState,optional"`
+	AttachmentID        string            `pulumi:"attachmentId,optional"`
+	SecurityGroups      []string          `pulumi:"securityGroups"`
+	Tags                map[string]string `pulumi:"tags"`
+	Reason              string            `pulumi:"reason,optional"`
+	MacAddress          string            `pulumi:"macAddress,optional"`
+	DeleteOnTermination bool              `pulumi:"deleteOnTermination,optional"`
+	// AccountID is the account the ENI was found in, when scanning accountRoles
+	AccountID string `pulumi:"accountId,optional"`
+	// Tier is the escalationPolicy tier the ENI was classified into
+	Tier string `pulumi:"tier,optional"`
+	// NatGatewayID is the deleted NAT gateway that left the ENI behind
+	NatGatewayID string `pulumi:"natGatewayId,optional"`
+	// Fingerprint summarizes the ENI at detection, for confirmUnchanged
+	Fingerprint string `pulumi:"fingerprint,optional"`
+}
+
+// Create detects orphaned ENIs and records them without taking any action.
+func (r OnDestroyResource) Create(ctx context.Context, name string, input ResourceArgs, preview bool) (string, OnDestroyState, error) {
+	input.Regions = DefaultRegions(ctx, input.Regions)
+
+	options, err := input.detectOptions()
+	if err != nil {
+		return "", OnDestroyState{}, err
+	}
+
+	if preview {
+		return name, OnDestroyState{ResourceArgs: input}, nil
+	}
+
+	if err := Preflight(ctx, input.preflightOptions()); err != nil {
+		return "", OnDestroyState{}, err
+	}
+
+	candidates, err := detectCandidates(ctx, input.Regions, options)
+	if err != nil {
+		return "", OnDestroyState{}, err
+	}
+
+	return name, OnDestroyState{ResourceArgs: input, Candidates: candidates}, nil
+}
+
+// Read implements the read operation for the two-phase ENI cleanup resource.
+func (r OnDestroyResource) Read(ctx context.Context, id string, oldState OnDestroyState) (OnDestroyState, error) {
+	return oldState, nil
+}
+
+// Update re-detects and records candidates for the new arguments, still without taking action.
+func (r OnDestroyResource) Update(ctx context.Context, id string, oldState OnDestroyState, newArgs ResourceArgs, preview bool) (OnDestroyState, error) {
+	newArgs.Regions = DefaultRegions(ctx, newArgs.Regions)
+
+	options, err := newArgs.detectOptions()
+	if err != nil {
+		return OnDestroyState{}, err
+	}
+
+	if preview {
+		return OnDestroyState{ResourceArgs: newArgs, Candidates: oldState.Candidates}, nil
+	}
+
+	if err := Preflight(ctx, newArgs.preflightOptions()); err != nil {
+		return OnDestroyState{}, err
+	}
+
+	candidates, err := detectCandidates(ctx, newArgs.Regions, options)
+	if err != nil {
+		return OnDestroyState{}, err
+	}
+
+	return OnDestroyState{ResourceArgs: newArgs, Candidates: candidates}, nil
+}
+
+// Delete cleans up the candidates recorded at create time.
+func (r OnDestroyResource) Delete(ctx context.Context, id string, state OnDestroyState) error {
+	if state.skipDeleteTimeCleanup() {
+		logging.V(5).Infof("Skipping destroy-time ENI cleanup: skipDeleteTimeCleanup is set")
+		return nil
+	}
+
+	if len(state.Candidates) == 0 {
+		logging.V(5).Infof("No recorded ENI candidates to clean up")
+		return nil
+	}
+
+	if err := Preflight(ctx, state.preflightOptions()); err != nil {
+		// Never clean up in the wrong account, but don't block deletion either
+		logging.V(5).Infof("Skipping destroy-time ENI cleanup: %v", err)
+		return nil
+	}
+
+	enis := make([]OrphanedENI, 0, len(state.Candidates))
+	for _, candidate := range state.Candidates {
+		enis = append(enis, candidate.toOrphanedENI())
+	}
+
+	result := CleanupOrphanedENIsWithOptions(ctx, enis, state.cleanupOptions())
+	logging.V(5).Infof("Destroy-time cleanup results: %d processed, %d failed, %d skipped",
+		result.SuccessCount, result.FailureCount, result.SkippedCount)
+
+	// Don't block deletion on cleanup failures; they are tagged for manual cleanup
+	return nil
+}
+
+// Annotate sets annotations for the resource.
+func (r OnDestroyResource) Annotate() map[string]interface{} {
+	return map[string]interface{}{
+		"pulumi:token": "aws-eni-cleanup:index:ENICleanupOnDestroy",
+		"description":  "Records orphaned ENIs when created and cleans them up when destroyed.",
+	}
+}
+
+// detectCandidates runs detection and converts the results to their recorded form
+func detectCandidates(ctx context.Context, regions []string, options DetectOptions) ([]DetectedENI, error) {
+	orphanedENIs, err := DetectOrphanedENIs(ctx, regions, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+	}
+
+	logging.V(5).Infof("Recorded %d orphaned ENIs for destroy-time cleanup", len(orphanedENIs))
+
+	return newDetectedENIs(orphanedENIs), nil
+}
+
+// newDetectedENIs converts OrphanedENIs to their recorded form
+func newDetectedENIs(enis []OrphanedENI) []DetectedENI {
+	detected := make([]DetectedENI, 0, len(enis))
+	for _, eni := range enis {
+		detected = append(detected, newDetectedENI(eni))
+	}
+	return detected
+}
+
+// newDetectedENI converts an OrphanedENI to its recorded form
+func newDetectedENI(eni OrphanedENI) DetectedENI {
+	return DetectedENI{
+		ID:                  eni.ID,
+		Region:              eni.Region,
+		VpcID:               eni.VPCID,
+		SubnetID:            eni.SubnetID,
+		AvailabilityZone:    eni.AvailabilityZone,
+		Description:         eni.Description,
+		InterfaceType:       eni.InterfaceType,
+		RequesterID:         eni.RequesterID,
+		AttachmentState:     eni.AttachmentState,
+		AttachmentID:        eni.AttachmentID,
+		SecurityGroups:      eni.SecurityGroups,
+		Tags:                eni.Tags,
+		Reason:              eni.Reason,
+		MacAddress:          eni.MacAddress,
+		DeleteOnTermination: eni.DeleteOnTermination,
+		AccountID:           eni.AccountID,
+		Tier:                eni.Tier,
+		NatGatewayID:        eni.NATGatewayID,
+		Fingerprint:         eni.Fingerprint,
+	}
+}
+
+// toOrphanedENI converts a recorded ENI back to the form used by cleanup
+func (d DetectedENI) toOrphanedENI() OrphanedENI {
+	return OrphanedENI{
+		ID:                  d.ID,
+		Region:              d.Region,
+		VPCID:               d.VpcID,
+		SubnetID:            d.SubnetID,
+		AvailabilityZone:    d.AvailabilityZone,
+		Description:         d.Description,
+		InterfaceType:       d.InterfaceType,
+		RequesterID:         d.RequesterID,
+		AttachmentState:     d.AttachmentState,
+		AttachmentID:        d.AttachmentID,
+		SecurityGroups:      d.SecurityGroups,
+		Tags:                d.Tags,
+		Reason:              d.Reason,
+		MacAddress:          d.MacAddress,
+		DeleteOnTermination: d.DeleteOnTermination,
+		AccountID:           d.AccountID,
+		Tier:                d.Tier,
+		NATGatewayID:        d.NatGatewayID,
+		Fingerprint:         d.Fingerprint,
+	}
+}
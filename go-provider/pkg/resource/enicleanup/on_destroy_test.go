@@ -0,0 +1,92 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestOnDestroyDeleteKeepsEscalationTier(t *testing.T) {
+	dangling := availableENI("eni-dangling")
+	dangling.SubnetId = aws.String("subnet-gone")
+	fake := newFakeEC2(dangling)
+	fake.subnets = []types.Subnet{{SubnetId: aws.String("subnet-1")}}
+	useFakeEC2(t, fake)
+
+	highAfterDays := 30.0
+	args := ResourceArgs{
+		Regions:                []string{"us-east-1"},
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		EscalationPolicy:       &EscalationPolicyArgs{HighAfterDays: &highAfterDays},
+	}
+	_, state, err := OnDestroyResource{}.Create(context.Background(), "cleanup", args, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Candidates) != 1 || state.Candidates[0].Tier != TierHigh {
+		t.Fatalf("expected one high tier candidate, got %+v", state.Candidates)
+	}
+
+	if err := (OnDestroyResource{}).Delete(context.Background(), "cleanup", state); err != nil {
+		t.Fatal(err)
+	}
+	if fake.deleted["eni-dangling"] != 1 {
+		t.Errorf("expected the high tier candidate to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestOnDestroyDeleteConfirmsUnchanged(t *testing.T) {
+	fake := newFakeEC2(availableENI("eni-same"), availableENI("eni-attached"))
+	useFakeEC2(t, fake)
+
+	confirm := true
+	args := ResourceArgs{
+		Regions:                []string{"us-east-1"},
+		DefaultSecurityGroupId: aws.String("sg-default"),
+		ConfirmUnchanged:       &confirm,
+	}
+	_, state, err := OnDestroyResource{}.Create(context.Background(), "cleanup", args, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", state.Candidates)
+	}
+
+	// Between create and destroy, one ENI is attached
+	fake.networkInterfaces[1].Status = types.NetworkInterfaceStatusInUse
+	fake.networkInterfaces[1].Attachment = &types.NetworkInterfaceAttachment{
+		AttachmentId: aws.String("eni-attach-1"),
+		InstanceId:   aws.String("i-new"),
+		Status:       types.AttachmentStatusAttached,
+	}
+
+	if err := (OnDestroyResource{}).Delete(context.Background(), "cleanup", state); err != nil {
+		t.Fatal(err)
+	}
+	if fake.deleted["eni-same"] != 1 {
+		t.Errorf("expected the unchanged candidate to be deleted, got %v", fake.deleted)
+	}
+	if fake.deleted["eni-attached"] != 0 || len(fake.modified["eni-attached"]) != 0 {
+		t.Errorf("expected the changed candidate not to be touched")
+	}
+}
+
+func TestOnDestroyCreateDefaultsRegions(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-west-1")
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1")))
+
+	_, state, err := OnDestroyResource{}.Create(context.Background(), "cleanup", ResourceArgs{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(state.Regions, []string{"eu-west-1"}) {
+		t.Errorf("expected the AWS_REGION region to be recorded, got %v", state.Regions)
+	}
+	if len(state.Candidates) != 1 || state.Candidates[0].Region != "eu-west-1" {
+		t.Errorf("expected one candidate in eu-west-1, got %+v", state.Candidates)
+	}
+}
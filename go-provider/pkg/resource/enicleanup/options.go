@@ -0,0 +1,164 @@
+package enicleanup
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// validLogLevels lists the accepted values for DetectOptions.LogLevel
+var validLogLevels = []string{"debug", "info", "warn", "error"}
+
+// ValidationError is an invalid option, naming the resource property it
+// corresponds to so Check can report it against that property
+type ValidationError struct {
+	Property string
+	Err      error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalidOption creates a ValidationError for a property
+func invalidOption(property string, format string, args ...any) error {
+	return &ValidationError{Property: property, Err: fmt.Errorf(format, args...)}
+}
+
+// Validate checks DetectOptions for contradictory or out-of-range values.
+// All problems found are returned together so they can be fixed in one pass,
+// each as a *ValidationError.
+func (o DetectOptions) Validate() error {
+	var errs []error
+
+	// Tag keys can't be both required and excluded
+	for _, includeKey := range o.IncludeTagKeys {
+		for _, excludeKey := range o.ExcludeTagKeys {
+			if includeKey == excludeKey {
+				errs = append(errs, invalidOption("excludeTagKeys", "tag key %q is in both includeTagKeys and excludeTagKeys; no ENI could match", includeKey))
+			}
+		}
+	}
+
+	if o.OlderThanDays != nil {
+		if *o.OlderThanDays < 0 {
+			errs = append(errs, invalidOption("olderThanDays", "olderThanDays must not be negative, got %v", *o.OlderThanDays))
+		}
+		if (o.CreatedTagKey == nil || *o.CreatedTagKey == "") && !o.UseCloudTrailForAge {
+			errs = append(errs, invalidOption("olderThanDays", "olderThanDays requires createdTagKey or useCloudTrailForAge since the describe doesn't include ENI creation time"))
+		}
+	}
+
+	if (o.CreatedTagKey == nil || *o.CreatedTagKey == "") && !o.UseCloudTrailForAge {
+		if o.CreatedAfter != nil || o.CreatedBefore != nil {
+			errs = append(errs, invalidOption("createdAfter", "createdAfter and createdBefore require createdTagKey or useCloudTrailForAge"))
+		}
+	}
+
+	if o.CreatedAfter != nil && o.CreatedBefore != nil && !o.CreatedAfter.Before(*o.CreatedBefore) {
+		errs = append(errs, invalidOption("createdAfter", "createdAfter (%s) must be before createdBefore (%s)", o.CreatedAfter, o.CreatedBefore))
+	}
+
+	if o.DetachGracePeriod < 0 {
+		errs = append(errs, invalidOption("detachGracePeriodMinutes", "detach grace period must not be negative, got %s", o.DetachGracePeriod))
+	}
+
+	if o.RecentDeployWindow < 0 {
+		errs = append(errs, invalidOption("recentDeployWindowMinutes", "recent deploy window must not be negative, got %s", o.RecentDeployWindow))
+	}
+
+	if o.MaxVPCDeletionRatio < 0 || o.MaxVPCDeletionRatio > 1 {
+		errs = append(errs, invalidOption("maxVpcDeletionRatio", "maxVpcDeletionRatio must be between 0 and 1, got %v", o.MaxVPCDeletionRatio))
+	}
+
+	if o.ExcludePublicIP && o.OnlyPublicIP {
+		errs = append(errs, invalidOption("onlyPublicIp", "excludePublicIp and onlyPublicIp are mutually exclusive"))
+	}
+
+	if o.DescribeBatchSize < 0 {
+		errs = append(errs, invalidOption("describeBatchSize", "describeBatchSize must not be negative, got %d", o.DescribeBatchSize))
+	}
+
+	if o.EnrichmentConcurrency < 0 {
+		errs = append(errs, invalidOption("enrichmentConcurrency", "enrichmentConcurrency must not be negative, got %d", o.EnrichmentConcurrency))
+	}
+
+	if o.RegionConcurrency < 0 {
+		errs = append(errs, invalidOption("regionConcurrency", "regionConcurrency must not be negative, got %d", o.RegionConcurrency))
+	}
+
+	if o.QPS < 0 {
+		errs = append(errs, invalidOption("qps", "qps must not be negative, got %v", o.QPS))
+	}
+
+	if o.IntraRegionParallelism < 0 {
+		errs = append(errs, invalidOption("intraRegionParallelism", "intraRegionParallelism must not be negative, got %d", o.IntraRegionParallelism))
+	}
+
+	if _, err := parseCIDRs(o.ExcludeCIDRs); err != nil {
+		errs = append(errs, invalidOption("excludeCidrs", "excludeCidrs: %w", err))
+	}
+
+	if _, err := compileDescriptionPatterns(o.ReservedDescriptionPatterns); err != nil {
+		errs = append(errs, invalidOption("reservedDescriptionPatterns", "reservedDescriptionPatterns: %w", err))
+	}
+
+	if err := validateMACPrefixes(o.ExcludeMacPrefixes); err != nil {
+		errs = append(errs, invalidOption("excludeMacPrefixes", "excludeMacPrefixes: %w", err))
+	}
+
+	if o.RecordAPIPath != nil && *o.RecordAPIPath != "" && o.ReplayAPIPath != nil && *o.ReplayAPIPath != "" {
+		errs = append(errs, invalidOption("recordApiPath", "recordApiPath and replayApiPath are mutually exclusive"))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(o.RegionGroups)) {
+		if isRegionSentinel(name) {
+			errs = append(errs, invalidOption("regionGroups", "regionGroups: %q is a reserved region sentinel", name))
+		}
+		if len(o.RegionGroups[name]) == 0 {
+			errs = append(errs, invalidOption("regionGroups", "regionGroups: group %q has no regions", name))
+		}
+	}
+
+	if o.DetectionProfile != "" {
+		if profile, ok := o.detectionProfile(); !ok {
+			errs = append(errs, invalidOption("detectionProfile", "detectionProfile %q is not defined; built-in profiles are %v", o.DetectionProfile, slices.Sorted(maps.Keys(DefaultDetectionProfiles))))
+		} else if profile.MinAge > 0 && (o.CreatedTagKey == nil || *o.CreatedTagKey == "") {
+			errs = append(errs, invalidOption("detectionProfile", "detectionProfile %q has a minimum age, which requires createdTagKey", o.DetectionProfile))
+		}
+	}
+
+	if o.LogLevel != "" {
+		valid := false
+		for _, level := range validLogLevels {
+			if o.LogLevel == level {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			errs = append(errs, invalidOption("logLevel", "logLevel %q is invalid; must be one of %v", o.LogLevel, validLogLevels))
+		}
+	}
+
+	errs = append(errs, o.EscalationPolicy.validate()...)
+	errs = append(errs, o.RoleOptions.validate()...)
+	errs = append(errs, o.validateInterfaceTypes()...)
+
+	// Each region's overrides must be valid once merged with the base options
+	for _, region := range slices.Sorted(maps.Keys(o.PerRegionOptions)) {
+		if o.PerRegionOptions[region].RoleOptions != (RoleOptions{}) {
+			errs = append(errs, invalidOption("perRegionOptions", "perRegionOptions[%s]: roleArn applies to every region and can't be overridden", region))
+		}
+		if err := o.forRegion(region).Validate(); err != nil {
+			errs = append(errs, invalidOption("perRegionOptions", "perRegionOptions[%s]: %w", region, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,165 @@
+package enicleanup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectOptionsValidate(t *testing.T) {
+	tagKey := "CreatedAt"
+	emptyTagKey := ""
+	days := 7.0
+	negativeDays := -1.0
+	earlier := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		options DetectOptions
+		wantErr string
+	}{
+		{
+			name:    "empty options are valid",
+			options: DetectOptions{},
+		},
+		{
+			name: "fully specified options are valid",
+			options: DetectOptions{
+				IncludeTagKeys:     []string{"Team"},
+				ExcludeTagKeys:     []string{"Keep"},
+				OlderThanDays:      &days,
+				CreatedTagKey:      &tagKey,
+				CreatedAfter:       &earlier,
+				CreatedBefore:      &later,
+				DetachGracePeriod:  time.Minute,
+				ExcludeCIDRs:       []string{"10.0.0.0/16", "fd00::/8"},
+				ExcludeMacPrefixes: []string{"0a:1b:2c", "0A-1B"},
+				LogLevel:           "debug",
+			},
+		},
+		{
+			name: "overlapping include and exclude tag keys",
+			options: DetectOptions{
+				IncludeTagKeys: []string{"Team", "Keep"},
+				ExcludeTagKeys: []string{"Keep"},
+			},
+			wantErr: `tag key "Keep" is in both includeTagKeys and excludeTagKeys`,
+		},
+		{
+			name:    "negative olderThanDays",
+			options: DetectOptions{OlderThanDays: &negativeDays, CreatedTagKey: &tagKey},
+			wantErr: "olderThanDays must not be negative",
+		},
+		{
+			name:    "olderThanDays without a creation tag",
+			options: DetectOptions{OlderThanDays: &days},
+			wantErr: "olderThanDays requires createdTagKey",
+		},
+		{
+			name:    "created bounds with an empty creation tag",
+			options: DetectOptions{CreatedTagKey: &emptyTagKey, CreatedAfter: &earlier},
+			wantErr: "createdAfter and createdBefore require createdTagKey",
+		},
+		{
+			name:    "createdAfter not before createdBefore",
+			options: DetectOptions{CreatedTagKey: &tagKey, CreatedAfter: &later, CreatedBefore: &earlier},
+			wantErr: "must be before createdBefore",
+		},
+		{
+			name:    "negative detach grace period",
+			options: DetectOptions{DetachGracePeriod: -time.Minute},
+			wantErr: "detach grace period must not be negative",
+		},
+		{
+			name:    "excluding and requiring public IPs",
+			options: DetectOptions{ExcludePublicIP: true, OnlyPublicIP: true},
+			wantErr: "excludePublicIp and onlyPublicIp are mutually exclusive",
+		},
+		{
+			name:    "negative intra-region parallelism",
+			options: DetectOptions{IntraRegionParallelism: -1},
+			wantErr: "intraRegionParallelism must not be negative",
+		},
+		{
+			name:    "invalid excluded CIDR",
+			options: DetectOptions{ExcludeCIDRs: []string{"10.0.0.0/16", "10.1.0.0"}},
+			wantErr: `invalid CIDR "10.1.0.0"`,
+		},
+		{
+			name:    "invalid excluded MAC prefix",
+			options: DetectOptions{ExcludeMacPrefixes: []string{"0a:zz"}},
+			wantErr: `invalid MAC prefix "0a:zz"`,
+		},
+		{
+			name:    "negative qps",
+			options: DetectOptions{ConfigOptions: ConfigOptions{QPS: -1}},
+			wantErr: "qps must not be negative",
+		},
+		{
+			name:    "invalid reserved description pattern",
+			options: DetectOptions{ReservedDescriptionPatterns: []string{"^ok", "(unclosed"}},
+			wantErr: `invalid pattern "(unclosed"`,
+		},
+		{
+			name: "invalid per-region override",
+			options: DetectOptions{
+				ExcludePublicIP:  true,
+				PerRegionOptions: map[string]DetectOptions{"us-west-2": {OnlyPublicIP: true}},
+			},
+			wantErr: "perRegionOptions[us-west-2]: excludePublicIp and onlyPublicIp are mutually exclusive",
+		},
+		{
+			name:    "unknown log level",
+			options: DetectOptions{LogLevel: "verbose"},
+			wantErr: `logLevel "verbose" is invalid`,
+		},
+		{
+			name:    "region group named like a sentinel",
+			options: DetectOptions{RegionGroups: map[string][]string{AllEnabledRegions: {"us-east-1"}}},
+			wantErr: `regionGroups: "all-enabled" is a reserved region sentinel`,
+		},
+		{
+			name:    "empty region group",
+			options: DetectOptions{RegionGroups: map[string][]string{"core": nil}},
+			wantErr: `regionGroups: group "core" has no regions`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDetectOptionsValidateReportsAllProblems(t *testing.T) {
+	negativeDays := -1.0
+	options := DetectOptions{
+		IncludeTagKeys: []string{"Keep"},
+		ExcludeTagKeys: []string{"Keep"},
+		OlderThanDays:  &negativeDays,
+	}
+
+	err := options.Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"includeTagKeys", "must not be negative", "requires createdTagKey"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
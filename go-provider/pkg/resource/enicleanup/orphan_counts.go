@@ -0,0 +1,67 @@
+package enicleanup
+
+import (
+	"context"
+)
+
+// OrphanCountsByVPC is a read-only provider function counting orphaned ENIs
+// per VPC, for dashboards of where orphans accumulate. It only runs
+// detection, so it is safe to call on a schedule.
+type OrphanCountsByVPC struct{}
+
+// OrphanCountsArgs are the inputs to OrphanCountsByVPC. Detection uses the
+// same arguments as the ENICleanup resource; cleanup arguments are ignored.
+type OrphanCountsArgs struct {
+	ResourceArgs
+}
+
+// OrphanCounts is the output of OrphanCountsByVPC
+type OrphanCounts struct {
+	// Counts maps "<account>/<region>/<vpc-id>" to the number of orphaned ENIs
+	Counts map[string]int `pulumi:"counts"`
+	// Total is the number of orphaned ENIs across every VPC
+	Total int `pulumi:"total"`
+}
+
+// Call implements the orphanCountsByVPC function.
+func (OrphanCountsByVPC) Call(ctx context.Context, args OrphanCountsArgs) (OrphanCounts, error) {
+	args.Regions = DefaultRegions(ctx, args.Regions)
+
+	options, err := args.detectOptions()
+	if err != nil {
+		return OrphanCounts{}, err
+	}
+
+	if err := Preflight(ctx, args.preflightOptions()); err != nil {
+		return OrphanCounts{}, err
+	}
+
+	detected, _, err := args.ResourceArgs.detectOnly(ctx, options)
+	if err != nil {
+		return OrphanCounts{}, err
+	}
+
+	return OrphanCounts{Counts: countByVPC(detected.ENIs), Total: len(detected.ENIs)}, nil
+}
+
+// countByVPC counts ENIs by account, region and VPC. The account is the one
+// scanned via accountRoles, or else the ENI's owner.
+func countByVPC(enis []OrphanedENI) map[string]int {
+	counts := make(map[string]int)
+	for _, eni := range enis {
+		account := eni.AccountID
+		if account == "" {
+			account = eni.OwnerID
+		}
+		counts[account+"/"+eni.Region+"/"+eni.VPCID]++
+	}
+	return counts
+}
+
+// Annotate sets annotations for the function.
+func (OrphanCountsByVPC) Annotate() map[string]interface{} {
+	return map[string]interface{}{
+		"pulumi:token": "aws-eni-cleanup:index:orphanCountsByVPC",
+		"description":  "Counts orphaned ENIs per account, region and VPC without modifying anything.",
+	}
+}
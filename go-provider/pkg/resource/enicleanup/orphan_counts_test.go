@@ -0,0 +1,50 @@
+package enicleanup
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestOrphanCountsByVPC(t *testing.T) {
+	other := availableENI("eni-3")
+	other.VpcId = aws.String("vpc-2")
+	fake := newFakeEC2(availableENI("eni-1"), availableENI("eni-2"), other)
+	for i := range fake.networkInterfaces {
+		fake.networkInterfaces[i].OwnerId = aws.String("111111111111")
+	}
+	useFakeEC2(t, fake)
+
+	counts, err := OrphanCountsByVPC{}.Call(context.Background(), OrphanCountsArgs{
+		ResourceArgs: ResourceArgs{Regions: []string{"us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{
+		"111111111111/us-east-1/vpc-1": 2,
+		"111111111111/us-east-1/vpc-2": 1,
+	}
+	if !maps.Equal(counts.Counts, want) || counts.Total != 3 {
+		t.Errorf("expected %v with a total of 3, got %+v", want, counts)
+	}
+	if len(fake.deleted) != 0 || len(fake.modified) != 0 {
+		t.Error("expected counting not to modify any ENIs")
+	}
+}
+
+func TestOrphanCountsByVPCDefaultsRegions(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-west-1")
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1")))
+
+	counts, err := OrphanCountsByVPC{}.Call(context.Background(), OrphanCountsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.Total != 1 || counts.Counts["/eu-west-1/vpc-1"] != 1 {
+		t.Errorf("expected the ENI to be counted in the AWS_REGION region, got %+v", counts)
+	}
+}
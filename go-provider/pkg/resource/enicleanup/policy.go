@@ -0,0 +1,185 @@
+package enicleanup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// policyQuery evaluates the policy's eni_cleanup.decision rule once per ENI,
+// so a policy is written against a single ENI but a region needs one opa call
+const policyQuery = `[{"id": eni.id, "decision": decision} | eni := input.network_interfaces[_]; decision := data.eni_cleanup.decision with input as eni]`
+
+// PolicyDecision is a policy's verdict on a candidate ENI
+type PolicyDecision struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+// policyDocument is the JSON input a policy sees for each ENI
+type policyDocument struct {
+	ID                  string            `json:"id"`
+	Region              string            `json:"region"`
+	VpcID               string            `json:"vpcId"`
+	SubnetID            string            `json:"subnetId"`
+	AvailabilityZone    string            `json:"availabilityZone"`
+	Description         string            `json:"description"`
+	InterfaceType       string            `json:"interfaceType"`
+	Status              string            `json:"status"`
+	RequesterID         string            `json:"requesterId"`
+	RequesterManaged    bool              `json:"requesterManaged"`
+	PrivateIPAddress    string            `json:"privateIpAddress"`
+	MacAddress          string            `json:"macAddress"`
+	HasPublicIP         bool              `json:"hasPublicIp"`
+	AttachmentID        string            `json:"attachmentId"`
+	AttachmentStatus    string            `json:"attachmentStatus"`
+	InstanceID          string            `json:"instanceId"`
+	DeleteOnTermination bool              `json:"deleteOnTermination"`
+	SecurityGroups      []string          `json:"securityGroups"`
+	Tags                map[string]string `json:"tags"`
+}
+
+// runOPA evaluates a query against a Rego policy file with the opa CLI and
+// returns its JSON output. It is a variable so tests can stub out opa.
+var runOPA = func(ctx context.Context, policyFile string, query string, input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "opa", "eval", "--format", "json", "--stdin-input", "--data", policyFile, query)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("opa eval failed: %w: %s", err, stderr.String())
+	}
+	return output, nil
+}
+
+// newPolicyDocument builds the policy input for a described ENI
+func newPolicyDocument(eni types.NetworkInterface, region string) policyDocument {
+	orphanedENI := newOrphanedENI(eni, region)
+	doc := policyDocument{
+		ID:                  orphanedENI.ID,
+		Region:              region,
+		VpcID:               orphanedENI.VPCID,
+		SubnetID:            orphanedENI.SubnetID,
+		AvailabilityZone:    orphanedENI.AvailabilityZone,
+		Description:         orphanedENI.Description,
+		InterfaceType:       orphanedENI.InterfaceType,
+		Status:              string(eni.Status),
+		RequesterID:         aws.ToString(eni.RequesterId),
+		RequesterManaged:    aws.ToBool(eni.RequesterManaged),
+		PrivateIPAddress:    aws.ToString(eni.PrivateIpAddress),
+		MacAddress:          orphanedENI.MacAddress,
+		HasPublicIP:         orphanedENI.HasPublicIP,
+		AttachmentID:        orphanedENI.AttachmentID,
+		AttachmentStatus:    orphanedENI.AttachmentState,
+		DeleteOnTermination: orphanedENI.DeleteOnTermination,
+		SecurityGroups:      orphanedENI.SecurityGroups,
+		Tags:                orphanedENI.Tags,
+	}
+	if eni.Attachment != nil {
+		doc.InstanceID = aws.ToString(eni.Attachment.InstanceId)
+	}
+	return doc
+}
+
+// evaluatePolicy asks the policy for a decision on each ENI, keyed by ENI ID.
+// ENIs the policy makes no decision for are absent from the result.
+func evaluatePolicy(ctx context.Context, policyFile string, docs []policyDocument) (map[string]PolicyDecision, error) {
+	input, err := json.Marshal(map[string]any{"network_interfaces": docs})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runOPA(ctx, policyFile, policyQuery, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Result []struct {
+			Expressions []struct {
+				Value []struct {
+					ID       string         `json:"id"`
+					Decision PolicyDecision `json:"decision"`
+				} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	decisions := make(map[string]PolicyDecision)
+	for _, result := range parsed.Result {
+		for _, expression := range result.Expressions {
+			for _, value := range expression.Value {
+				decisions[value.ID] = value.Decision
+			}
+		}
+	}
+	return decisions, nil
+}
+
+// detectByPolicy selects orphaned ENIs in a region using only the policy's
+// decisions in place of the built-in filters. Transit gateway, VPN, GWLB
+// endpoint and Global Accelerator ENIs and ENIs already deleted in this run
+// are still never candidates, and ENIs the policy makes no decision for are
+// spared.
+func detectByPolicy(ctx context.Context, region string, enis []types.NetworkInterface, options DetectOptions) ([]OrphanedENI, []SkippedENI, error) {
+	var orphanedENIs []OrphanedENI
+	var skipped []SkippedENI
+
+	var evaluated []types.NetworkInterface
+	var docs []policyDocument
+	for _, eni := range enis {
+		id := aws.ToString(eni.NetworkInterfaceId)
+		if options.DeletedENIs.Contains(id) {
+			continue
+		}
+		if isTransitGatewayOrVPNENI(eni) {
+			skipped = append(skipped, SkippedENI{ID: id, Region: region, Reason: SkipReasonTransitGatewayOrVPN, Detail: vpnAttachmentID(eni)})
+			continue
+		}
+		if reason, owner, ok := protectedEndpoint(eni); ok {
+			skipped = append(skipped, SkippedENI{ID: id, Region: region, Reason: reason, Detail: owner})
+			continue
+		}
+		evaluated = append(evaluated, eni)
+		docs = append(docs, newPolicyDocument(eni, region))
+	}
+
+	if len(docs) == 0 {
+		return nil, skipped, nil
+	}
+
+	decisions, err := evaluatePolicy(ctx, aws.ToString(options.PolicyFile), docs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error evaluating policy %s: %w", aws.ToString(options.PolicyFile), err)
+	}
+
+	for _, eni := range evaluated {
+		id := aws.ToString(eni.NetworkInterfaceId)
+		decision, ok := decisions[id]
+		if !ok {
+			decision.Reason = "no policy decision"
+		}
+		if !decision.Allow {
+			logging.V(9).Infof("Skipping ENI %s denied by policy: %s", id, decision.Reason)
+			skipped = append(skipped, SkippedENI{ID: id, Region: region, Reason: SkipReasonPolicy, Detail: decision.Reason})
+			continue
+		}
+
+		logging.V(5).Infof("ENI %s allowed by policy: %s", id, decision.Reason)
+		orphanedENI := newOrphanedENI(eni, region)
+		orphanedENI.Reason = ReasonPolicy
+		orphanedENIs = append(orphanedENIs, orphanedENI)
+	}
+
+	return orphanedENIs, skipped, nil
+}
@@ -0,0 +1,63 @@
+package enicleanup
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestDetectByPolicy(t *testing.T) {
+	transitGateway := availableENI("eni-tgw")
+	transitGateway.InterfaceType = "transit_gateway"
+	useFakeEC2(t, newFakeEC2(availableENI("eni-allowed"), availableENI("eni-denied"), availableENI("eni-undecided"), transitGateway))
+
+	var evaluated []string
+	original := runOPA
+	runOPA = func(ctx context.Context, policyFile string, query string, input []byte) ([]byte, error) {
+		if policyFile != "policy.rego" {
+			t.Errorf("unexpected policy file %q", policyFile)
+		}
+		var parsed struct {
+			NetworkInterfaces []policyDocument `json:"network_interfaces"`
+		}
+		if err := json.Unmarshal(input, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		for _, doc := range parsed.NetworkInterfaces {
+			evaluated = append(evaluated, doc.ID)
+		}
+		return []byte(`{"result": [{"expressions": [{"value": [
+			{"id": "eni-allowed", "decision": {"allow": true, "reason": "no owner tag"}},
+			{"id": "eni-denied", "decision": {"allow": false, "reason": "owned by platform team"}}
+		]}]}]}`), nil
+	}
+	t.Cleanup(func() { runOPA = original })
+
+	result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{PolicyFile: aws.String("policy.rego")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(evaluated) != 3 {
+		t.Errorf("expected the transit gateway ENI to be withheld from the policy, evaluated %v", evaluated)
+	}
+	if len(result.ENIs) != 1 || result.ENIs[0].ID != "eni-allowed" || result.ENIs[0].Reason != ReasonPolicy {
+		t.Errorf("expected only eni-allowed to be detected, got %+v", result.ENIs)
+	}
+
+	want := map[string]SkippedENI{
+		"eni-tgw":       {ID: "eni-tgw", Region: "us-east-1", Reason: SkipReasonTransitGatewayOrVPN},
+		"eni-denied":    {ID: "eni-denied", Region: "us-east-1", Reason: SkipReasonPolicy, Detail: "owned by platform team"},
+		"eni-undecided": {ID: "eni-undecided", Region: "us-east-1", Reason: SkipReasonPolicy, Detail: "no policy decision"},
+	}
+	if len(result.Skipped) != len(want) {
+		t.Fatalf("expected %d skipped ENIs, got %+v", len(want), result.Skipped)
+	}
+	for _, skipped := range result.Skipped {
+		if skipped != want[skipped.ID] {
+			t.Errorf("got %+v, want %+v", skipped, want[skipped.ID])
+		}
+	}
+}
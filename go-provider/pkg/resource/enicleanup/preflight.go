@@ -0,0 +1,57 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// PreflightOptions contains the checks run before any detection or cleanup
+type PreflightOptions struct {
+	// ExpectedAccountId aborts the run unless the caller's credentials belong
+	// to this AWS account
+	ExpectedAccountId *string
+	// RoleOptions assume a role in place of the default credentials, so the
+	// role's account is the one checked
+	RoleOptions
+	// ConfigOptions select the AWS profile and endpoint in place of the
+	// default config
+	ConfigOptions
+}
+
+// Preflight runs the configured safety checks. Every entry point calls it
+// before detecting or cleaning up ENIs.
+func Preflight(ctx context.Context, options PreflightOptions) error {
+	if options.ExpectedAccountId == nil || *options.ExpectedAccountId == "" {
+		return nil
+	}
+
+	credentials, err := options.assume(ctx, options.ConfigOptions)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(ctx, "", options.ConfigOptions, credentials)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config for preflight checks: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to verify AWS account: %w", err)
+	}
+
+	account := aws.ToString(identity.Account)
+	if account != *options.ExpectedAccountId {
+		return fmt.Errorf("refusing to run: credentials belong to AWS account %s (%s), but expectedAccountId is %s",
+			account, aws.ToString(identity.Arn), *options.ExpectedAccountId)
+	}
+
+	logging.V(5).Infof("Verified AWS account %s", account)
+	return nil
+}
@@ -0,0 +1,93 @@
+package enicleanup
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// DetectionProfile is a named set of positive filters identifying leftover
+// ENIs from a known source. An ENI matches when it satisfies every field that
+// is set.
+type DetectionProfile struct {
+	// NameTagPrefix matches the start of the ENI's Name tag
+	NameTagPrefix string
+	// DescriptionPrefix matches the start of the ENI's description
+	DescriptionPrefix string
+	// InterfaceTypes matches the ENI's interface type, e.g. "lambda"
+	InterfaceTypes []string
+	// SubnetIDs matches the ENI's subnet
+	SubnetIDs []string
+	// RequireAvailable only matches ENIs that are available and unattached
+	RequireAvailable bool
+	// MinAge only matches ENIs whose DetectOptions.CreatedTagKey tag is at
+	// least this old. ENIs without the tag don't match.
+	MinAge time.Duration
+	// IncludeTrunkBranchENIs lets the profile match CNI-managed trunk and
+	// branch ENIs, as if DetectOptions.IncludeTrunkBranchENIs were set
+	IncludeTrunkBranchENIs bool
+}
+
+// DefaultDetectionProfiles are the built-in detection profiles, encoding
+// criteria known to be safe for their sources
+var DefaultDetectionProfiles = map[string]DetectionProfile{
+	// Hyperplane ENIs Lambda leaves behind once no function uses them
+	"lambda-leftovers": {
+		DescriptionPrefix: "AWS Lambda VPC ENI",
+		InterfaceTypes:    []string{"lambda"},
+		RequireAvailable:  true,
+	},
+	// Branch ENIs the VPC CNI left behind after their pod or trunk went away
+	"eks-sg-for-pods": {
+		DescriptionPrefix:      "aws-k8s-branch-eni",
+		InterfaceTypes:         []string{branchInterfaceType},
+		RequireAvailable:       true,
+		IncludeTrunkBranchENIs: true,
+	},
+}
+
+// detectionProfile returns the selected profile, looking in
+// DetectionProfiles before DefaultDetectionProfiles. ok is false when no
+// profile is selected or it is unknown.
+func (o DetectOptions) detectionProfile() (DetectionProfile, bool) {
+	if o.DetectionProfile == "" {
+		return DetectionProfile{}, false
+	}
+	if profile, ok := o.DetectionProfiles[o.DetectionProfile]; ok {
+		return profile, true
+	}
+	profile, ok := DefaultDetectionProfiles[o.DetectionProfile]
+	return profile, ok
+}
+
+// matches reports whether an ENI satisfies every filter set in the profile
+func (p DetectionProfile) matches(eni types.NetworkInterface, tags map[string]string, createdTagKey *string) bool {
+	if p.NameTagPrefix != "" && !strings.HasPrefix(tags["Name"], p.NameTagPrefix) {
+		return false
+	}
+	if p.DescriptionPrefix != "" && !strings.HasPrefix(aws.ToString(eni.Description), p.DescriptionPrefix) {
+		return false
+	}
+	if len(p.InterfaceTypes) > 0 && !slices.Contains(p.InterfaceTypes, string(eni.InterfaceType)) {
+		return false
+	}
+	if len(p.SubnetIDs) > 0 && !slices.Contains(p.SubnetIDs, aws.ToString(eni.SubnetId)) {
+		return false
+	}
+	if p.RequireAvailable && (eni.Status != types.NetworkInterfaceStatusAvailable || eni.Attachment != nil) {
+		return false
+	}
+	if p.MinAge > 0 {
+		if createdTagKey == nil || *createdTagKey == "" {
+			return false
+		}
+		createdTime, err := parseCreatedTag(tags, *createdTagKey)
+		if err != nil || time.Since(createdTime) < p.MinAge {
+			return false
+		}
+	}
+	return true
+}
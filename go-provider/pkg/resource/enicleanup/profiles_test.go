@@ -0,0 +1,80 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestDetectionProfiles(t *testing.T) {
+	lambdaENI := availableENI("eni-lambda")
+	lambdaENI.InterfaceType = "lambda"
+	lambdaENI.Description = aws.String("AWS Lambda VPC ENI-worker")
+
+	inUseLambdaENI := lambdaENI
+	inUseLambdaENI.NetworkInterfaceId = aws.String("eni-lambda-in-use")
+	inUseLambdaENI.Status = types.NetworkInterfaceStatusInUse
+
+	branchENI := availableENI("eni-branch")
+	branchENI.InterfaceType = branchInterfaceType
+	branchENI.Description = aws.String("aws-k8s-branch-eni")
+
+	batchENI := availableENI("eni-batch")
+	batchENI.TagSet = []types.Tag{{Key: aws.String("Name"), Value: aws.String("batch-worker-7")}}
+	batchENI.SubnetId = aws.String("subnet-batch")
+
+	useFakeEC2(t, newFakeEC2(lambdaENI, inUseLambdaENI, branchENI, batchENI, availableENI("eni-other")))
+
+	tests := []struct {
+		profile string
+		want    []string
+	}{
+		{profile: "lambda-leftovers", want: []string{"eni-lambda"}},
+		{profile: "eks-sg-for-pods", want: []string{"eni-branch"}},
+		{profile: "batch", want: []string{"eni-batch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+				DetectionProfile: tt.profile,
+				DetectionProfiles: map[string]DetectionProfile{
+					"batch": {NameTagPrefix: "batch-", SubnetIDs: []string{"subnet-batch"}},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, eni := range result.ENIs {
+				got = append(got, eni.ID)
+				if eni.Reason != ReasonProfile {
+					t.Errorf("expected %s to be selected by the profile, got reason %s", eni.ID, eni.Reason)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsUnknownDetectionProfile(t *testing.T) {
+	err := DetectOptions{DetectionProfile: "nope"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "lambda-leftovers") {
+		t.Errorf("expected an error listing the built-in profiles, got %v", err)
+	}
+
+	err = DetectOptions{
+		DetectionProfile:  "aged",
+		DetectionProfiles: map[string]DetectionProfile{"aged": {MinAge: time.Hour}},
+	}.Validate()
+	if err == nil {
+		t.Error("expected a profile with a minimum age to require createdTagKey")
+	}
+}
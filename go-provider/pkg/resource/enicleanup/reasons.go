@@ -0,0 +1,106 @@
+package enicleanup
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Reasons an ENI was selected as a cleanup candidate
+const (
+	// ReasonDeletedLoadBalancer marks an ENI whose load balancer no longer exists
+	ReasonDeletedLoadBalancer = "deleted-load-balancer"
+	// ReasonDeletedNATGateway marks an ENI left behind by a NAT gateway that
+	// no longer exists
+	ReasonDeletedNATGateway = "deleted-nat-gateway"
+	// ReasonMatchedSecurityGroup marks an ENI selected by the target security group
+	ReasonMatchedSecurityGroup = "matched-security-group"
+	// ReasonProfile marks an ENI selected by the configured detection profile
+	ReasonProfile = "profile"
+	// ReasonStale marks an ENI whose creation tag is older than the configured bounds
+	ReasonStale = "stale"
+	// ReasonDeadAttachmentOwner marks an ENI still attached to an instance that
+	// no longer exists or has terminated
+	ReasonDeadAttachmentOwner = "dead-attachment-owner"
+	// ReasonDetached marks an ENI that is available and not attached to anything
+	ReasonDetached = "detached"
+	// ReasonUntagged marks an ENI without any tags
+	ReasonUntagged = "untagged"
+	// ReasonMatchedFilters marks an ENI selected only by the configured filters
+	ReasonMatchedFilters = "matched-filters"
+	// ReasonPolicy marks an ENI allowed by the policy file
+	ReasonPolicy = "policy"
+)
+
+// Reasons an ENI seen during detection was deliberately spared
+const (
+	// SkipReasonTransitGatewayOrVPN marks a transit gateway or VPN attachment ENI
+	SkipReasonTransitGatewayOrVPN = "transit-gateway-or-vpn"
+	// SkipReasonGatewayLoadBalancerEndpoint marks a Gateway Load Balancer endpoint ENI
+	SkipReasonGatewayLoadBalancerEndpoint = "gateway-load-balancer-endpoint"
+	// SkipReasonGlobalAccelerator marks a Global Accelerator ENI
+	SkipReasonGlobalAccelerator = "global-accelerator"
+	// SkipReasonTrunkOrBranch marks a CNI-managed trunk or branch ENI
+	SkipReasonTrunkOrBranch = "trunk-or-branch"
+	// SkipReasonSharedSubnet marks an ENI in a RAM-shared subnet owned by
+	// another account than the ENI
+	SkipReasonSharedSubnet = "shared-subnet"
+	// SkipReasonDeleteOnTermination marks an ENI AWS deletes along with its instance
+	SkipReasonDeleteOnTermination = "delete-on-termination"
+	// SkipReasonLoadBalancer marks an ENI owned by a load balancer that exists or
+	// could not be verified
+	SkipReasonLoadBalancer = "load-balancer"
+	// SkipReasonReservedRequester marks an ENI created by a reserved requester ID
+	SkipReasonReservedRequester = "reserved-requester"
+	// SkipReasonReservedDescription marks an ENI whose description matched a reserved pattern
+	SkipReasonReservedDescription = "reserved-description"
+	// SkipReasonExcludedCIDR marks an ENI whose primary private IP is in an excluded CIDR
+	SkipReasonExcludedCIDR = "excluded-cidr"
+	// SkipReasonExcludedMAC marks an ENI whose MAC address matched an excluded prefix
+	SkipReasonExcludedMAC = "excluded-mac-prefix"
+	// SkipReasonPublicIP marks an ENI with a public IP when public IPs are excluded
+	SkipReasonPublicIP = "public-ip"
+	// SkipReasonRecentlyDetached marks an ENI within the detach grace period
+	SkipReasonRecentlyDetached = "recently-detached"
+	// SkipReasonRecentDeploy marks an ENI in a VPC deployed to within the
+	// quiet period of RespectRecentDeploys
+	SkipReasonRecentDeploy = "recent-deploy"
+	// SkipReasonVPCDeletionRatio marks a candidate withheld because its VPC
+	// exceeded MaxVPCDeletionRatio
+	SkipReasonVPCDeletionRatio = "vpc-deletion-ratio"
+	// SkipReasonProtectedTag marks an ENI carrying one of the excluded tag keys
+	SkipReasonProtectedTag = "protected-tag"
+	// SkipReasonPolicy marks an ENI the policy file denied or made no decision for
+	SkipReasonPolicy = "policy"
+	// SkipReasonInterfaceType marks an ENI of an interface type in
+	// DefaultSkipInterfaceTypes or SkipInterfaceTypes
+	SkipReasonInterfaceType = "interface-type"
+	// SkipReasonRequesterManaged marks an ENI AWS manages on the requester's behalf
+	SkipReasonRequesterManaged = "requester-managed"
+)
+
+// SkipReasonChangedSinceDetection marks a candidate cleanup spared because
+// ConfirmUnchanged found its status, attachment or security groups changed
+// since detection
+const SkipReasonChangedSinceDetection = "changed-since-detection"
+
+// classifyReason determines the most specific reason an ENI is a cleanup candidate
+func classifyReason(eni types.NetworkInterface, tags map[string]string, loadBalancerARN string, deadOwner bool, options DetectOptions) string {
+	switch {
+	case loadBalancerARN != "":
+		return ReasonDeletedLoadBalancer
+	case deadOwner:
+		return ReasonDeadAttachmentOwner
+	case options.SecurityGroupId != nil && *options.SecurityGroupId != "":
+		return ReasonMatchedSecurityGroup
+	case options.DetectionProfile != "":
+		return ReasonProfile
+	case options.CreatedTagKey != nil && *options.CreatedTagKey != "" &&
+		(options.OlderThanDays != nil || options.CreatedBefore != nil):
+		return ReasonStale
+	case eni.Status == types.NetworkInterfaceStatusAvailable && eni.Attachment == nil:
+		return ReasonDetached
+	case len(tags) == 0:
+		return ReasonUntagged
+	default:
+		return ReasonMatchedFilters
+	}
+}
@@ -0,0 +1,117 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// ReconcileManualCleanupTags removes the manual cleanup tags from ENIs that
+// carry them but no longer match the orphan criteria, e.g. because they were
+// reattached or an operator fixed them by hand. Only the regions detected
+// scanned successfully are reconciled, using the same scan filters, and ENIs
+// withheld by MaxVPCDeletionRatio still count as orphans. It does nothing
+// unless ReconcileTags is set; in dry run mode the ENIs are only logged.
+func ReconcileManualCleanupTags(ctx context.Context, detected DetectResult, options CleanupOptions, result *CleanupResult) {
+	if !options.ReconcileTags {
+		return
+	}
+
+	orphans := make(map[string]bool, len(detected.ENIs))
+	for _, eni := range detected.ENIs {
+		orphans[eni.ID] = true
+	}
+	for _, skipped := range detected.Skipped {
+		if skipped.Reason == SkipReasonVPCDeletionRatio {
+			orphans[skipped.ID] = true
+		}
+	}
+
+	regions := make([]string, 0, len(detected.scanned))
+	for region := range detected.scanned {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	if options.credentials == nil {
+		credentials, err := options.assume(ctx, options.ConfigOptions)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Error reconciling manual cleanup tags: %v", err))
+			return
+		}
+		options.credentials = credentials
+	}
+
+	for _, region := range regions {
+		reconciled, err := reconcileRegionTags(ctx, region, detected.scanned[region], orphans, options)
+		result.ReconciledENIs = append(result.ReconciledENIs, reconciled...)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Error reconciling manual cleanup tags in region %s: %v", region, err))
+		}
+	}
+}
+
+// reconcileRegionTags removes stale manual cleanup tags in one region and
+// returns the ENIs whose tags were removed
+func reconcileRegionTags(ctx context.Context, region string, filters []types.Filter, orphans map[string]bool, options CleanupOptions) ([]string, error) {
+	cfg, err := loadConfig(ctx, region, options.ConfigOptions, options.credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	captureOptions, err := apiCaptureOptions(options.RecordAPIPath, options.ReplayAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	ec2Client := options.ClientFactory.newClient(cfg, options.ConfigOptions, captureOptions...)
+
+	tagKey := options.manualCleanupTagKey()
+	filters = append(slices.Clone(filters), types.Filter{
+		Name:   aws.String("tag-key"),
+		Values: []string{tagKey},
+	})
+	enis, err := findNetworkInterfaces(ctx, ec2Client, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tagged network interfaces: %w", err)
+	}
+
+	var reconciled []string
+	var errs []error
+	for _, eni := range enis {
+		id := aws.ToString(eni.NetworkInterfaceId)
+		if orphans[id] {
+			continue
+		}
+		if _, ok := eniTags(eni)[tagKey]; !ok {
+			continue
+		}
+
+		if options.DryRun {
+			logging.V(5).Infof("[DRY RUN] Would remove stale manual cleanup tags from ENI %s in region %s", id, region)
+			reconciled = append(reconciled, id)
+			continue
+		}
+
+		_, err := ec2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{
+			Resources: []string{id},
+			Tags: []types.Tag{
+				{Key: aws.String(tagKey)},
+				{Key: aws.String(options.attemptedCleanupTimeTagKey())},
+				{Key: aws.String(options.deletionErrorTagKey())},
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove manual cleanup tags from ENI %s: %w", id, err))
+			continue
+		}
+		logging.V(5).Infof("Removed stale manual cleanup tags from ENI %s in region %s", id, region)
+		reconciled = append(reconciled, id)
+	}
+	return reconciled, errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package enicleanup
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// forRegion returns the options to scan a region with: the base options, with
+// every field set in the region's PerRegionOptions entry overriding the base
+// value. Unset (zero) override fields keep the base value, so an override can
+// enable but not disable a boolean option.
+func (o DetectOptions) forRegion(region string) DetectOptions {
+	override, ok := o.PerRegionOptions[region]
+	if !ok {
+		return o
+	}
+
+	merged := o
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	overrideValue := reflect.ValueOf(override)
+	for i := 0; i < overrideValue.NumField(); i++ {
+		if field := overrideValue.Field(i); !field.IsZero() {
+			mergedValue.Field(i).Set(field)
+		}
+	}
+	// Overrides don't nest
+	merged.PerRegionOptions = nil
+	return merged
+}
+
+// validatePerRegionOptions checks that every PerRegionOptions key is one of
+// the regions being scanned, so a typo doesn't silently apply the base options
+func validatePerRegionOptions(options DetectOptions, regions []string) error {
+	var unknown []string
+	for region := range options.PerRegionOptions {
+		if !slices.Contains(regions, region) {
+			unknown = append(unknown, region)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("perRegionOptions has regions that are not being scanned: %v", unknown)
+	}
+	return nil
+}
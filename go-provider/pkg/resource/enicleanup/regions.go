@@ -0,0 +1,195 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultRegionConcurrency is the default bound on regions scanned at once
+const DefaultRegionConcurrency = 4
+
+// regionConcurrency returns the configured region concurrency or the default
+func (o DetectOptions) regionConcurrency() int {
+	if o.RegionConcurrency > 0 {
+		return o.RegionConcurrency
+	}
+	return DefaultRegionConcurrency
+}
+
+// Region sentinels that expand at runtime via DescribeRegions
+const (
+	// AllEnabledRegions expands to every region enabled for the account,
+	// including opt-in regions the account has opted into
+	AllEnabledRegions = "all-enabled"
+	// AllOptInRegions expands to only the opt-in regions the account has opted into
+	AllOptInRegions = "all-optin"
+)
+
+// DefaultRegionGroups are the built-in region groups, e.g. Regions: ["eu"].
+// Opt-in regions are left out, as they aren't enabled for every account.
+var DefaultRegionGroups = map[string][]string{
+	"us":       {"us-east-1", "us-east-2", "us-west-1", "us-west-2"},
+	"eu":       {"eu-central-1", "eu-north-1", "eu-west-1", "eu-west-2", "eu-west-3"},
+	"apac":     {"ap-northeast-1", "ap-northeast-2", "ap-northeast-3", "ap-south-1", "ap-southeast-1", "ap-southeast-2"},
+	"govcloud": {"us-gov-east-1", "us-gov-west-1"},
+}
+
+// FallbackRegion is scanned when no region is configured anywhere
+const FallbackRegion = "us-east-1"
+
+// discoveryRegion is used to call DescribeRegions when no default region is configured
+const discoveryRegion = FallbackRegion
+
+// defaultConfigRegion returns the region of the default AWS config, such as
+// the shared config profile's region; a variable so tests can replace it
+var defaultConfigRegion = func(ctx context.Context) string {
+	cfg, err := loadConfig(ctx, "", ConfigOptions{}, nil)
+	if err != nil {
+		return ""
+	}
+	return cfg.Region
+}
+
+// DefaultRegions resolves the regions to scan when none may be given. It
+// returns, in order of precedence: regions when not empty, AWS_REGION,
+// AWS_DEFAULT_REGION, the default AWS config's region, and finally
+// FallbackRegion with a warning.
+func DefaultRegions(ctx context.Context, regions []string) []string {
+	if len(regions) > 0 {
+		return regions
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(env); region != "" {
+			return []string{region}
+		}
+	}
+	if region := defaultConfigRegion(ctx); region != "" {
+		return []string{region}
+	}
+
+	logging.Warningf("No region is configured; scanning %s. Set regions, AWS_REGION or a profile region to choose one", FallbackRegion)
+	return []string{FallbackRegion}
+}
+
+// regionSentinelStatuses maps each sentinel to the opt-in statuses it includes
+var regionSentinelStatuses = map[string][]string{
+	AllEnabledRegions: {"opt-in-not-required", "opted-in"},
+	AllOptInRegions:   {"opted-in"},
+}
+
+// ExpandRegions expands region groups and sentinels using the built-in groups
+func ExpandRegions(ctx context.Context, regions []string) ([]string, error) {
+	return ExpandRegionsWithGroups(ctx, regions, nil)
+}
+
+// ExpandRegionsWithGroups replaces region groups with their regions and
+// region sentinels with the matching regions from DescribeRegions, leaving
+// explicit regions untouched. groups extends DefaultRegionGroups, replacing
+// built-in groups of the same name. Disabled regions are never included by a
+// sentinel. The result is de-duplicated and keeps the input order.
+func ExpandRegionsWithGroups(ctx context.Context, regions []string, groups map[string][]string) ([]string, error) {
+	return expandRegions(ctx, regions, groups, ConfigOptions{}, nil)
+}
+
+// ExpandRegionsWithOptions is ExpandRegionsWithGroups with the region groups
+// of options, discovering regions with the profile, endpoint and role
+// detection with options uses
+func ExpandRegionsWithOptions(ctx context.Context, regions []string, options DetectOptions) ([]string, error) {
+	credentials := options.credentials
+	if credentials == nil {
+		var err error
+		credentials, err = options.assume(ctx, options.ConfigOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expandRegions(ctx, regions, options.RegionGroups, options.ConfigOptions, credentials)
+}
+
+// expandRegions is ExpandRegionsWithGroups, discovering regions with the
+// config source selects and credentials in place of the default credential
+// chain when set
+func expandRegions(ctx context.Context, regions []string, groups map[string][]string, source ConfigOptions, credentials aws.CredentialsProvider) ([]string, error) {
+	regions = expandRegionGroups(regions, groups)
+	if !slices.ContainsFunc(regions, isRegionSentinel) {
+		return regions, nil
+	}
+
+	cfg, err := loadConfig(ctx, "", source, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config to discover regions: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = discoveryRegion
+	}
+	client := ec2.NewFromConfig(cfg)
+
+	var expanded []string
+	for _, region := range regions {
+		sentinelStatuses, ok := regionSentinelStatuses[region]
+		if !ok {
+			if !slices.Contains(expanded, region) {
+				expanded = append(expanded, region)
+			}
+			continue
+		}
+
+		output, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+			AllRegions: aws.Bool(true),
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("opt-in-status"),
+					Values: sentinelStatuses,
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand %q: %w", region, err)
+		}
+
+		for _, r := range output.Regions {
+			name := aws.ToString(r.RegionName)
+			if name != "" && !slices.Contains(expanded, name) {
+				expanded = append(expanded, name)
+			}
+		}
+	}
+
+	return expanded, nil
+}
+
+// expandRegionGroups replaces group names with the group's regions, checking
+// groups before DefaultRegionGroups. The result is de-duplicated.
+func expandRegionGroups(regions []string, groups map[string][]string) []string {
+	var expanded []string
+	for _, region := range regions {
+		members, ok := groups[region]
+		if !ok {
+			members, ok = DefaultRegionGroups[region]
+		}
+		if !ok {
+			members = []string{region}
+		}
+
+		for _, member := range members {
+			if !slices.Contains(expanded, member) {
+				expanded = append(expanded, member)
+			}
+		}
+	}
+
+	return expanded
+}
+
+// isRegionSentinel checks whether a region is a sentinel expanded by ExpandRegions
+func isRegionSentinel(region string) bool {
+	_, ok := regionSentinelStatuses[region]
+	return ok
+}
@@ -0,0 +1,157 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+func TestExpandRegionsWithGroups(t *testing.T) {
+	groups := map[string][]string{
+		"core": {"us-east-1", "eu-west-1"},
+		"us":   {"us-east-1"},
+	}
+
+	tests := []struct {
+		name    string
+		regions []string
+		want    []string
+	}{
+		{
+			name:    "explicit regions are untouched",
+			regions: []string{"us-west-2", "eu-west-1"},
+			want:    []string{"us-west-2", "eu-west-1"},
+		},
+		{
+			name:    "built-in group",
+			regions: []string{"govcloud"},
+			want:    []string{"us-gov-east-1", "us-gov-west-1"},
+		},
+		{
+			name:    "configured group replaces the built-in one",
+			regions: []string{"us"},
+			want:    []string{"us-east-1"},
+		},
+		{
+			name:    "groups and regions are de-duplicated in order",
+			regions: []string{"eu-west-1", "core", "us-east-1"},
+			want:    []string{"eu-west-1", "us-east-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandRegionsWithGroups(context.Background(), tt.regions, groups)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultRegions(t *testing.T) {
+	original := defaultConfigRegion
+	t.Cleanup(func() { defaultConfigRegion = original })
+
+	tests := []struct {
+		name          string
+		regions       []string
+		awsRegion     string
+		defaultRegion string
+		configRegion  string
+		want          []string
+	}{
+		{name: "explicit", regions: []string{"eu-west-1"}, awsRegion: "us-west-2", want: []string{"eu-west-1"}},
+		{name: "AWS_REGION", awsRegion: "us-west-2", defaultRegion: "eu-central-1", configRegion: "ap-south-1", want: []string{"us-west-2"}},
+		{name: "AWS_DEFAULT_REGION", defaultRegion: "eu-central-1", configRegion: "ap-south-1", want: []string{"eu-central-1"}},
+		{name: "config", configRegion: "ap-south-1", want: []string{"ap-south-1"}},
+		{name: "fallback", want: []string{FallbackRegion}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", tt.defaultRegion)
+			defaultConfigRegion = func(context.Context) string { return tt.configRegion }
+
+			if got := DefaultRegions(context.Background(), tt.regions); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// failingDescribeEC2 fails every DescribeNetworkInterfaces call
+type failingDescribeEC2 struct {
+	*fakeEC2
+}
+
+func (f failingDescribeEC2) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	return nil, errors.New("UnauthorizedOperation")
+}
+
+func TestDetectScansRegionsConcurrently(t *testing.T) {
+	clients := map[string]ENIClient{
+		"us-west-2":    newFakeEC2(availableENI("eni-b"), availableENI("eni-a")),
+		"eu-west-1":    newFakeEC2(availableENI("eni-c")),
+		"ap-south-1":   failingDescribeEC2{newFakeEC2()},
+		"ca-central-1": newFakeEC2(availableENI("eni-d")),
+	}
+	factory := func(cfg aws.Config, optFns ...func(*ec2.Options)) ENIClient { return clients[cfg.Region] }
+
+	regions := []string{"us-west-2", "ap-south-1", "eu-west-1", "ca-central-1"}
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), regions, DetectOptions{
+		ClientFactory:     factory,
+		RegionConcurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed region doesn't stop the others, and the ENIs are ordered by
+	// region and ID whichever scan finished first
+	var got []string
+	for _, eni := range detected.ENIs {
+		got = append(got, eni.Region+"/"+eni.ID)
+	}
+	want := []string{"ca-central-1/eni-d", "eu-west-1/eni-c", "us-west-2/eni-a", "us-west-2/eni-b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if _, ok := detected.RegionTimings["ap-south-1"]; !ok {
+		t.Errorf("expected a scan timing for the failed region, got %v", detected.RegionTimings)
+	}
+	if len(detected.Errors) != 1 || detected.Errors[0].Region != "ap-south-1" {
+		t.Errorf("expected ap-south-1 reported as failed, got %+v", detected.Errors)
+	}
+}
+
+func TestDetectFailsWhenEveryRegionFails(t *testing.T) {
+	factory := stubFactory(failingDescribeEC2{newFakeEC2()})
+
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1", "us-east-2"}, DetectOptions{ClientFactory: factory})
+	if err == nil {
+		t.Fatal("expected an error when no region could be scanned")
+	}
+	if len(detected.Errors) != 2 {
+		t.Errorf("expected both regions reported as failed, got %+v", detected.Errors)
+	}
+
+	// Create and Update fail rather than reporting zero orphaned ENIs
+	args := ResourceArgs{Regions: []string{"us-east-1", "us-east-2"}}
+	if _, _, err := args.detectAndCleanup(context.Background(), DetectOptions{ClientFactory: factory}, CleanupOptions{}); err == nil {
+		t.Error("expected the resource to fail when no region could be scanned")
+	}
+}
+
+func TestRegionConcurrencyMustNotBeNegative(t *testing.T) {
+	if err := (DetectOptions{RegionConcurrency: -1}).Validate(); err == nil {
+		t.Error("expected a negative regionConcurrency to be rejected")
+	}
+}
@@ -0,0 +1,48 @@
+package enicleanup
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// DefaultSkipRequesterIDs are the pseudo-account requester IDs AWS services
+// use for the ENIs they manage. Requester IDs are stable where descriptions
+// are not, so they are checked before reserved descriptions.
+var DefaultSkipRequesterIDs = []string{
+	"amazon-elb",
+	"amazon-rds",
+	"amazon-redshift",
+	"amazon-elasticache",
+	"amazon-elasticsearch",
+	"amazon-aws",
+}
+
+// reservedRequester returns the requester ID an ENI was created by if it
+// matches one of requesterIDs, compared case-insensitively
+func reservedRequester(eni types.NetworkInterface, requesterIDs []string) (string, bool) {
+	requester := aws.ToString(eni.RequesterId)
+	if requester == "" {
+		return "", false
+	}
+	for _, id := range requesterIDs {
+		if strings.EqualFold(requester, id) {
+			return requester, true
+		}
+	}
+	return "", false
+}
+
+// skipsRequesterManaged reports whether detection spares an ENI because AWS
+// manages it on the requester's behalf. Interface types asked for explicitly
+// by OnlyInterfaceTypes or the detection profile, such as Lambda's, are
+// requester-managed too, so they aren't skipped.
+func (o DetectOptions) skipsRequesterManaged(eni types.NetworkInterface, profile DetectionProfile) bool {
+	if o.IncludeRequesterManaged || !aws.ToBool(eni.RequesterManaged) {
+		return false
+	}
+	interfaceType := string(eni.InterfaceType)
+	return !slices.Contains(o.OnlyInterfaceTypes, interfaceType) && !slices.Contains(profile.InterfaceTypes, interfaceType)
+}
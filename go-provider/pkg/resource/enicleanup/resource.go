@@ -3,6 +3,9 @@ package enicleanup
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 )
@@ -12,47 +15,194 @@ type Resource struct{}
 
 // ResourceArgs defines the arguments for the ENI cleanup resource.
 type ResourceArgs struct {
-	Regions                  []string `pulumi:"regions"`
+	Regions                  []string `pulumi:"regions,optional"`
 	SecurityGroupId          *string  `pulumi:"securityGroupId,optional"`
 	DefaultSecurityGroupId   *string  `pulumi:"defaultSecurityGroupId,optional"`
 	DryRun                   *bool    `pulumi:"dryRun,optional"`
 	SkipReservedDescriptions []string `pulumi:"skipReservedDescriptions,optional"`
+	SkipRequesterIds         []string `pulumi:"skipRequesterIds,optional"`
 	LogLevel                 *string  `pulumi:"logLevel,optional"`
 	IncludeTagKeys           []string `pulumi:"includeTagKeys,optional"`
 	ExcludeTagKeys           []string `pulumi:"excludeTagKeys,optional"`
 	OlderThanDays            *float64 `pulumi:"olderThanDays,optional"`
 	DisassociateOnly         *bool    `pulumi:"disassociateOnly,optional"`
+	RequireDefaultOnEmpty    *bool    `pulumi:"requireDefaultOnEmpty,optional"`
+	CreatedTagKey            *string  `pulumi:"createdTagKey,optional"`
+	CreatedAfter             *string  `pulumi:"createdAfter,optional"`
+	CreatedBefore            *string  `pulumi:"createdBefore,optional"`
+	UseCloudTrailForAge      *bool    `pulumi:"useCloudTrailForAge,optional"`
+	DetachGracePeriodMinutes *float64 `pulumi:"detachGracePeriodMinutes,optional"`
+	VerifyLoadBalancers      *bool    `pulumi:"verifyLoadBalancers,optional"`
+	VerifyNatGateways        *bool    `pulumi:"verifyNatGateways,optional"`
+	TagWithStackInfo         *bool    `pulumi:"tagWithStackInfo,optional"`
+	StackName                *string  `pulumi:"stackName,optional"`
+	ProjectName              *string  `pulumi:"projectName,optional"`
+	ExcludePublicIp          *bool    `pulumi:"excludePublicIp,optional"`
+	OnlyPublicIp             *bool    `pulumi:"onlyPublicIp,optional"`
+	// DetachWaitSecondsByType overrides the post-detach wait per interface type
+	DetachWaitSecondsByType    map[string]float64              `pulumi:"detachWaitSecondsByType,optional"`
+	DetachTimeoutSeconds       *float64                        `pulumi:"detachTimeoutSeconds,optional"`
+	NetworkInterfaceIds        []string                        `pulumi:"networkInterfaceIds,optional"`
+	VpcIds                     []string                        `pulumi:"vpcIds,optional"`
+	SubnetIds                  []string                        `pulumi:"subnetIds,optional"`
+	AvailabilityZones          []string                        `pulumi:"availabilityZones,optional"`
+	IntraRegionParallelism     *int                            `pulumi:"intraRegionParallelism,optional"`
+	RegionConcurrency          *int                            `pulumi:"regionConcurrency,optional"`
+	DeletionStrikesRequired    *int                            `pulumi:"deletionStrikesRequired,optional"`
+	MaxDeletions               *int                            `pulumi:"maxDeletions,optional"`
+	ExpectedAccountId          *string                         `pulumi:"expectedAccountId,optional"`
+	AccountRoles               []AccountRole                   `pulumi:"accountRoles,optional"`
+	RoleArn                    *string                         `pulumi:"roleArn,optional"`
+	ExternalId                 *string                         `pulumi:"externalId,optional"`
+	SessionName                *string                         `pulumi:"sessionName,optional"`
+	Profile                    *string                         `pulumi:"profile,optional"`
+	Endpoint                   *string                         `pulumi:"endpoint,optional"`
+	AccountConcurrency         *int                            `pulumi:"accountConcurrency,optional"`
+	ExcludeCidrs               []string                        `pulumi:"excludeCidrs,optional"`
+	ExcludeMacPrefixes         []string                        `pulumi:"excludeMacPrefixes,optional"`
+	SkipInterfaceTypes         []string                        `pulumi:"skipInterfaceTypes,optional"`
+	OnlyInterfaceTypes         []string                        `pulumi:"onlyInterfaceTypes,optional"`
+	SkipDeleteTimeCleanup      *bool                           `pulumi:"skipDeleteTimeCleanup,optional"`
+	IncludeDeleteOnTermination *bool                           `pulumi:"includeDeleteOnTermination,optional"`
+	IncludeSharedSubnets       *bool                           `pulumi:"includeSharedSubnets,optional"`
+	IncludeRequesterManaged    *bool                           `pulumi:"includeRequesterManaged,optional"`
+	PolicyFile                 *string                         `pulumi:"policyFile,optional"`
+	IncludeTrunkBranchEnis     *bool                           `pulumi:"includeTrunkBranchEnis,optional"`
+	RecordApiPath              *string                         `pulumi:"recordApiPath,optional"`
+	RestoreSecurityGroupTagKey *string                         `pulumi:"restoreSecurityGroupTagKey,optional"`
+	ManualCleanupTagKey        *string                         `pulumi:"manualCleanupTagKey,optional"`
+	AttemptedCleanupTimeTagKey *string                         `pulumi:"attemptedCleanupTimeTagKey,optional"`
+	DeletionErrorTagKey        *string                         `pulumi:"deletionErrorTagKey,optional"`
+	OmitDeletionErrorTag       *bool                           `pulumi:"omitDeletionErrorTag,optional"`
+	ReconcileTags              *bool                           `pulumi:"reconcileTags,optional"`
+	ConfirmUnchanged           *bool                           `pulumi:"confirmUnchanged,optional"`
+	TagOnly                    *bool                           `pulumi:"tagOnly,optional"`
+	AuditRunId                 *string                         `pulumi:"auditRunId,optional"`
+	TagConcurrency             *int                            `pulumi:"tagConcurrency,optional"`
+	DeleteConcurrency          *int                            `pulumi:"deleteConcurrency,optional"`
+	DeleteAttempts             *int                            `pulumi:"deleteAttempts,optional"`
+	VerifyDeletion             *bool                           `pulumi:"verifyDeletion,optional"`
+	RegionGroups               map[string][]string             `pulumi:"regionGroups,optional"`
+	RespectRecentDeploys       *bool                           `pulumi:"respectRecentDeploys,optional"`
+	RecentDeployWindowMinutes  *float64                        `pulumi:"recentDeployWindowMinutes,optional"`
+	DeployTimestampTagKey      *string                         `pulumi:"deployTimestampTagKey,optional"`
+	VerifyAttachmentOwners     *bool                           `pulumi:"verifyAttachmentOwners,optional"`
+	MaxVpcDeletionRatio        *float64                        `pulumi:"maxVpcDeletionRatio,optional"`
+	VpcDeletionRatioOverrides  []string                        `pulumi:"vpcDeletionRatioOverrides,optional"`
+	EventBusName               *string                         `pulumi:"eventBusName,optional"`
+	PerEniTimeoutSeconds       *float64                        `pulumi:"perEniTimeoutSeconds,optional"`
+	DetectionProfile           *string                         `pulumi:"detectionProfile,optional"`
+	DetectionProfiles          map[string]DetectionProfileArgs `pulumi:"detectionProfiles,optional"`
+	EnrichmentConcurrency      *int                            `pulumi:"enrichmentConcurrency,optional"`
+	DescribeBatchSize          *int                            `pulumi:"describeBatchSize,optional"`
+	RequireEmptyVpc            *bool                           `pulumi:"requireEmptyVpc,optional"`
+	LockTableName              *string                         `pulumi:"lockTableName,optional"`
+	LockTtlSeconds             *float64                        `pulumi:"lockTtlSeconds,optional"`
+	LockWaitSeconds            *float64                        `pulumi:"lockWaitSeconds,optional"`
+	DecisionLog                *bool                           `pulumi:"decisionLog,optional"`
+	HandleAssociations         *bool                           `pulumi:"handleAssociations,optional"`
+	EscalationPolicy           *EscalationPolicyArgs           `pulumi:"escalationPolicy,optional"`
+	// ReservedDescriptionPatterns are regular expressions for descriptions to skip
+	ReservedDescriptionPatterns []string `pulumi:"reservedDescriptionPatterns,optional"`
+	// OutputFile receives the full cleanup result as JSON after each run
+	OutputFile *string `pulumi:"outputFile,optional"`
+	// DeleteRetryBaseDelaySeconds is the wait before the first delete retry
+	DeleteRetryBaseDelaySeconds *float64 `pulumi:"deleteRetryBaseDelaySeconds,optional"`
+	// Qps caps the EC2 calls per second in each region
+	Qps *float64 `pulumi:"qps,optional"`
+}
+
+// EscalationPolicyArgs defines the escalation policy; see EscalationPolicy
+type EscalationPolicyArgs struct {
+	MediumAfterDays *float64          `pulumi:"mediumAfterDays,optional"`
+	HighAfterDays   *float64          `pulumi:"highAfterDays,optional"`
+	Actions         map[string]string `pulumi:"actions,optional"`
+}
+
+// DetectionProfileArgs defines a named detection profile; see DetectionProfile
+type DetectionProfileArgs struct {
+	NameTagPrefix          *string  `pulumi:"nameTagPrefix,optional"`
+	DescriptionPrefix      *string  `pulumi:"descriptionPrefix,optional"`
+	InterfaceTypes         []string `pulumi:"interfaceTypes,optional"`
+	SubnetIds              []string `pulumi:"subnetIds,optional"`
+	RequireAvailable       *bool    `pulumi:"requireAvailable,optional"`
+	MinAgeDays             *float64 `pulumi:"minAgeDays,optional"`
+	IncludeTrunkBranchEnis *bool    `pulumi:"includeTrunkBranchEnis,optional"`
 }
 
 // ResourceState represents the state of the ENI cleanup resource.
 type ResourceState struct {
 	// Input fields
-	Regions                  []string `pulumi:"regions"`
-	SecurityGroupId          *string  `pulumi:"securityGroupId,optional"`
-	DefaultSecurityGroupId   *string  `pulumi:"defaultSecurityGroupId,optional"`
-	DryRun                   *bool    `pulumi:"dryRun,optional"`
-	SkipReservedDescriptions []string `pulumi:"skipReservedDescriptions,optional"`
-	LogLevel                 *string  `pulumi:"logLevel,optional"`
-	IncludeTagKeys           []string `pulumi:"includeTagKeys,optional"`
-	ExcludeTagKeys           []string `pulumi:"excludeTagKeys,optional"`
-	OlderThanDays            *float64 `pulumi:"olderThanDays,optional"`
-	DisassociateOnly         *bool    `pulumi:"disassociateOnly,optional"`
+	ResourceArgs
 
 	// Output fields
 	SuccessCount int          `pulumi:"successCount"`
 	FailureCount int          `pulumi:"failureCount"`
 	SkippedCount int          `pulumi:"skippedCount"`
 	CleanedENIs  []CleanedENI `pulumi:"cleanedENIs"`
+	// SkippedDetails lists the ENIs detection saw but deliberately spared
+	SkippedDetails []SkippedENI `pulumi:"skippedDetails"`
+	// VerificationFailed lists deleted ENIs that verifyDeletion found still exist
+	VerificationFailed []string `pulumi:"verificationFailed,optional"`
+	// AbortedVPCs lists the VPCs whose candidates maxVpcDeletionRatio withheld
+	AbortedVPCs []AbortedVPC `pulumi:"abortedVpcs,optional"`
+	// DeadLetter lists ENIs that failed cleanup and couldn't even be tagged
+	// for manual cleanup, to escalate
+	DeadLetter []CleanedENI `pulumi:"deadLetter,optional"`
+	// FailedAccounts lists the accountRoles accounts that couldn't be scanned
+	FailedAccounts []string `pulumi:"failedAccounts,optional"`
+	// FailedRegions lists the regions that couldn't be scanned, keyed
+	// "<account>/<region>" when scanning accountRoles
+	FailedRegions []string `pulumi:"failedRegions,optional"`
+	// RegionTimings breaks the run down by region, see RegionTimings
+	RegionTimings map[string]RegionTiming `pulumi:"regionTimings,optional"`
+	// ReconciledENIs lists the ENIs reconcileTags removed stale manual
+	// cleanup tags from
+	ReconciledENIs []string `pulumi:"reconciledEnis,optional"`
+	// DetectedENIs is every orphaned ENI detection found, whether or not
+	// cleanup acted on it, e.g. with dryRun or disassociateOnly
+	DetectedENIs []DetectedENI `pulumi:"detectedEnis,optional"`
 }
 
 // CleanedENI represents information about a cleaned ENI.
 type CleanedENI struct {
-	ID            string `pulumi:"id"`
-	Region        string `pulumi:"region"`
-	VpcID         string `pulumi:"vpcId"`
-	Description   string `pulumi:"description"`
-	ActionTaken   string `pulumi:"actionTaken"` // "disassociated" or "deleted"
-	SecurityGroup string `pulumi:"securityGroup,optional"`
+	ID            string `pulumi:"id" json:"id"`
+	Region        string `pulumi:"region" json:"region"`
+	VpcID         string `pulumi:"vpcId" json:"vpcId"`
+	Description   string `pulumi:"description" json:"description"`
+	ActionTaken   string `pulumi:"actionTaken" json:"actionTaken"` // "disassociated" or "deleted"
+	SecurityGroup string `pulumi:"securityGroup,optional" json:"securityGroup,omitempty"`
+	Reason        string `pulumi:"reason,optional" json:"reason,omitempty"`
+	// Errors lists every error encountered, for dead-lettered ENIs
+	Errors []string `pulumi:"errors,optional" json:"errors,omitempty"`
+	// DisassociatedAddresses lists the public IPs of the Elastic IPs
+	// handleAssociations disassociated before deleting the ENI
+	DisassociatedAddresses []string `pulumi:"disassociatedAddresses,optional" json:"disassociatedAddresses,omitempty"`
+	// AccountID is the account the ENI was found in, when scanning accountRoles
+	AccountID string `pulumi:"accountId,optional" json:"accountId,omitempty"`
+	// NatGatewayID is the deleted NAT gateway that left the ENI behind
+	NatGatewayID string `pulumi:"natGatewayId,optional" json:"natGatewayId,omitempty"`
+	// Timestamp is when the ENI was cleaned up or dead-lettered, in RFC3339 format
+	Timestamp string `pulumi:"timestamp,optional" json:"timestamp,omitempty"`
+}
+
+// SkippedENI represents an ENI that was seen but deliberately spared.
+type SkippedENI struct {
+	ID     string `pulumi:"id" json:"id"`
+	Region string `pulumi:"region" json:"region"`
+	Reason string `pulumi:"reason" json:"reason"` // e.g. "reserved-description"
+	Detail string `pulumi:"detail,optional" json:"detail,omitempty"`
+	// AccountID is the account the ENI was found in, when scanning accountRoles
+	AccountID string `pulumi:"accountId,optional" json:"accountId,omitempty"`
+}
+
+// AbortedVPC is a VPC whose cleanup was withheld because too large a share of
+// its ENIs were candidates.
+type AbortedVPC struct {
+	VpcID      string `pulumi:"vpcId"`
+	Region     string `pulumi:"region"`
+	Candidates int    `pulumi:"candidates"`
+	Total      int    `pulumi:"total"`
 }
 
 // Create implements the create operation for the ENI cleanup resource.
@@ -62,83 +212,47 @@ func (r Resource) Create(ctx context.Context, name string, input ResourceArgs, p
 		return "", ResourceState{}, fmt.Errorf("at least one region must be specified")
 	}
 
+	options, err := input.detectOptions()
+	if err != nil {
+		return "", ResourceState{}, err
+	}
+
 	if preview {
-		return name, ResourceState{
-			Regions:                  input.Regions,
-			SecurityGroupId:          input.SecurityGroupId,
-			DefaultSecurityGroupId:   input.DefaultSecurityGroupId,
-			DryRun:                   input.DryRun,
-			SkipReservedDescriptions: input.SkipReservedDescriptions,
-			LogLevel:                 input.LogLevel,
-			IncludeTagKeys:           input.IncludeTagKeys,
-			ExcludeTagKeys:           input.ExcludeTagKeys,
-			OlderThanDays:            input.OlderThanDays,
-			DisassociateOnly:         input.DisassociateOnly,
-		}, nil
+		return name, ResourceState{ResourceArgs: input}, nil
 	}
 
 	// Set default values for the state
 	state := ResourceState{
-		Regions:                  input.Regions,
-		SecurityGroupId:          input.SecurityGroupId,
-		DefaultSecurityGroupId:   input.DefaultSecurityGroupId,
-		DryRun:                   input.DryRun,
-		SkipReservedDescriptions: input.SkipReservedDescriptions,
-		LogLevel:                 input.LogLevel,
-		IncludeTagKeys:           input.IncludeTagKeys,
-		ExcludeTagKeys:           input.ExcludeTagKeys,
-		OlderThanDays:            input.OlderThanDays,
-		DisassociateOnly:         input.DisassociateOnly,
-		SuccessCount:             0,
-		FailureCount:             0,
-		SkippedCount:             0,
-		CleanedENIs:              []CleanedENI{},
-	}
-
-	// Determine if this is a disassociate-only operation
-	disassociateOnly := false
-	if state.DisassociateOnly != nil {
-		disassociateOnly = *state.DisassociateOnly
-	}
-
-	// Perform ENI detection and cleanup
-	logLevel := "info"
-	if state.LogLevel != nil {
-		logLevel = *state.LogLevel
+		ResourceArgs: input,
+		SuccessCount: 0,
+		FailureCount: 0,
+		SkippedCount: 0,
+		CleanedENIs:  []CleanedENI{},
 	}
 
-	// Setup detection options
-	options := DetectOptions{
-		SkipReservedDescriptions: state.SkipReservedDescriptions,
-		IncludeTagKeys:           state.IncludeTagKeys,
-		ExcludeTagKeys:           state.ExcludeTagKeys,
-		OlderThanDays:            state.OlderThanDays,
-		LogLevel:                 logLevel,
-		SecurityGroupId:          state.SecurityGroupId,
+	if err := Preflight(ctx, input.preflightOptions()); err != nil {
+		return "", ResourceState{}, err
 	}
 
-	// Detect orphaned ENIs
-	orphanedENIs, err := DetectOrphanedENIs(ctx, state.Regions, options)
+	// Detect and clean up orphaned ENIs
+	detected, result, err := input.detectAndCleanup(ctx, options, input.cleanupOptions())
 	if err != nil {
-		return "", ResourceState{}, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+		return "", ResourceState{}, err
 	}
 
-	// Log detection results
-	logging.V(5).Infof("Detected %d orphaned ENIs", len(orphanedENIs))
-
-	// Determine if this is a dry run
-	dryRun := false
-	if state.DryRun != nil {
-		dryRun = *state.DryRun
-	}
-
-	// Perform cleanup
-	result := CleanupOrphanedENIs(ctx, orphanedENIs, dryRun, disassociateOnly, state.DefaultSecurityGroupId, state.SecurityGroupId)
-
 	// Update state with results
 	state.SuccessCount = result.SuccessCount
 	state.FailureCount = result.FailureCount
 	state.SkippedCount = result.SkippedCount
+	state.SkippedDetails = result.SkippedDetails
+	state.VerificationFailed = result.VerificationFailed
+	state.AbortedVPCs = detected.AbortedVPCs
+	state.DeadLetter = result.DeadLetter
+	state.FailedAccounts = result.FailedAccounts
+	state.FailedRegions = failedRegions(detected.Errors)
+	state.RegionTimings = RegionTimings(detected, result)
+	state.ReconciledENIs = result.ReconciledENIs
+	state.DetectedENIs = newDetectedENIs(detected.ENIs)
 
 	// Convert cleanup results to output state
 	for _, eni := range result.CleanedENIs {
@@ -157,81 +271,60 @@ func (r Resource) Read(ctx context.Context, id string, oldState ResourceState) (
 
 // Update implements the update operation for the ENI cleanup resource.
 func (r Resource) Update(ctx context.Context, id string, oldState ResourceState, newArgs ResourceArgs, preview bool) (ResourceState, error) {
+	options, err := newArgs.detectOptions()
+	if err != nil {
+		return ResourceState{}, err
+	}
+
 	// If this is a preview, just return the new args without taking action
 	if preview {
 		return ResourceState{
-			Regions:                  newArgs.Regions,
-			SecurityGroupId:          newArgs.SecurityGroupId,
-			DefaultSecurityGroupId:   newArgs.DefaultSecurityGroupId,
-			DryRun:                   newArgs.DryRun,
-			SkipReservedDescriptions: newArgs.SkipReservedDescriptions,
-			LogLevel:                 newArgs.LogLevel,
-			IncludeTagKeys:           newArgs.IncludeTagKeys,
-			ExcludeTagKeys:           newArgs.ExcludeTagKeys,
-			OlderThanDays:            newArgs.OlderThanDays,
-			DisassociateOnly:         newArgs.DisassociateOnly,
-			SuccessCount:             oldState.SuccessCount,
-			FailureCount:             oldState.FailureCount,
-			SkippedCount:             oldState.SkippedCount,
-			CleanedENIs:              oldState.CleanedENIs,
+			ResourceArgs:       newArgs,
+			SuccessCount:       oldState.SuccessCount,
+			FailureCount:       oldState.FailureCount,
+			SkippedCount:       oldState.SkippedCount,
+			CleanedENIs:        oldState.CleanedENIs,
+			SkippedDetails:     oldState.SkippedDetails,
+			VerificationFailed: oldState.VerificationFailed,
+			AbortedVPCs:        oldState.AbortedVPCs,
+			DeadLetter:         oldState.DeadLetter,
+			FailedAccounts:     oldState.FailedAccounts,
+			FailedRegions:      oldState.FailedRegions,
+			RegionTimings:      oldState.RegionTimings,
+			ReconciledENIs:     oldState.ReconciledENIs,
+			DetectedENIs:       oldState.DetectedENIs,
 		}, nil
 	}
 
-	// Determine if this is a disassociate-only operation
-	disassociateOnly := false
-	if newArgs.DisassociateOnly != nil {
-		disassociateOnly = *newArgs.DisassociateOnly
-	}
-
 	// Perform update by basically doing a new create operation
 	logging.V(5).Infof("Updating ENI cleanup resource")
 
-	// Setup detection options
-	logLevel := "info"
-	if newArgs.LogLevel != nil {
-		logLevel = *newArgs.LogLevel
-	}
-
-	options := DetectOptions{
-		SkipReservedDescriptions: newArgs.SkipReservedDescriptions,
-		IncludeTagKeys:           newArgs.IncludeTagKeys,
-		ExcludeTagKeys:           newArgs.ExcludeTagKeys,
-		OlderThanDays:            newArgs.OlderThanDays,
-		LogLevel:                 logLevel,
-		SecurityGroupId:          newArgs.SecurityGroupId,
+	if err := Preflight(ctx, newArgs.preflightOptions()); err != nil {
+		return ResourceState{}, err
 	}
 
-	// Detect orphaned ENIs
-	orphanedENIs, err := DetectOrphanedENIs(ctx, newArgs.Regions, options)
+	// Detect and clean up orphaned ENIs
+	detected, result, err := newArgs.detectAndCleanup(ctx, options, newArgs.cleanupOptions())
 	if err != nil {
-		return ResourceState{}, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+		return ResourceState{}, err
 	}
 
-	// Determine if this is a dry run
-	dryRun := false
-	if newArgs.DryRun != nil {
-		dryRun = *newArgs.DryRun
-	}
-
-	// Perform cleanup
-	result := CleanupOrphanedENIs(ctx, orphanedENIs, dryRun, disassociateOnly, newArgs.DefaultSecurityGroupId, newArgs.SecurityGroupId)
-
 	// Create new state with updated values
 	newState := ResourceState{
-		Regions:                  newArgs.Regions,
-		SecurityGroupId:          newArgs.SecurityGroupId,
-		DefaultSecurityGroupId:   newArgs.DefaultSecurityGroupId,
-		DryRun:                   newArgs.DryRun,
-		SkipReservedDescriptions: newArgs.SkipReservedDescriptions,
-		LogLevel:                 newArgs.LogLevel,
-		IncludeTagKeys:           newArgs.IncludeTagKeys,
-		ExcludeTagKeys:           newArgs.ExcludeTagKeys,
-		OlderThanDays:            newArgs.OlderThanDays,
-		DisassociateOnly:         newArgs.DisassociateOnly,
-		SuccessCount:             result.SuccessCount,
-		FailureCount:             result.FailureCount,
-		SkippedCount:             result.SkippedCount,
-		CleanedENIs:              []CleanedENI{},
+		ResourceArgs:       newArgs,
+		SuccessCount:       result.SuccessCount,
+		FailureCount:       result.FailureCount,
+		SkippedCount:       result.SkippedCount,
+		CleanedENIs:        []CleanedENI{},
+		SkippedDetails:     result.SkippedDetails,
+		VerificationFailed: result.VerificationFailed,
+		AbortedVPCs:        detected.AbortedVPCs,
+		DeadLetter:         result.DeadLetter,
+		FailedAccounts:     result.FailedAccounts,
+		FailedRegions:      failedRegions(detected.Errors),
+		RegionTimings:      RegionTimings(detected, result),
+		ReconciledENIs:     result.ReconciledENIs,
+		DetectedENIs:       newDetectedENIs(detected.ENIs),
 	}
 
 	// Convert cleanup results to output state
@@ -244,25 +337,44 @@ func (r Resource) Update(ctx context.Context, id string, oldState ResourceState,
 
 // Delete implements the delete operation for the ENI cleanup resource.
 func (r Resource) Delete(ctx context.Context, id string, state ResourceState) error {
+	if state.skipDeleteTimeCleanup() {
+		logging.V(5).Infof("Skipping delete-time ENI cleanup: skipDeleteTimeCleanup is set")
+		return nil
+	}
+
 	// Special delete-time ENI cleanup logic
 	logging.V(5).Infof("Running delete-time ENI cleanup for resource")
 
-	// Always use disassociate-only for delete operations
-	disassociateOnly := true
+	options, err := state.detectOptions()
+	if err != nil {
+		// Don't block deletion on options that no longer validate
+		logging.V(5).Infof("Skipping delete-time ENI cleanup due to invalid options: %v", err)
+		return nil
+	}
 
-	// Setup detection options
-	logLevel := "info"
-	if state.LogLevel != nil {
-		logLevel = *state.LogLevel
+	if err := Preflight(ctx, state.preflightOptions()); err != nil {
+		// Never clean up in the wrong account, but don't block deletion either
+		logging.V(5).Infof("Skipping delete-time ENI cleanup: %v", err)
+		return nil
 	}
 
-	options := DetectOptions{
-		SkipReservedDescriptions: state.SkipReservedDescriptions,
-		IncludeTagKeys:           state.IncludeTagKeys,
-		ExcludeTagKeys:           state.ExcludeTagKeys,
-		OlderThanDays:            state.OlderThanDays,
-		LogLevel:                 logLevel,
-		SecurityGroupId:          state.SecurityGroupId,
+	// Always use disassociate-only for delete operations, and always perform cleanup
+	// regardless of DryRun setting. This ensures resources are cleaned up when the
+	// stack is destroyed
+	cleanupOptions := state.cleanupOptions()
+	cleanupOptions.DisassociateOnly = true
+	cleanupOptions.DryRun = false
+
+	if len(state.AccountRoles) > 0 {
+		_, result, err := state.detectAndCleanup(ctx, options, cleanupOptions)
+		if err != nil {
+			// Don't block deletion
+			logging.V(5).Infof("Delete-time cleanup failed: %v", err)
+			return nil
+		}
+		logging.V(5).Infof("Delete-time cleanup results: %d processed, %d failed, %d skipped, %d accounts failed",
+			result.SuccessCount, result.FailureCount, result.SkippedCount, len(result.FailedAccounts))
+		return nil
 	}
 
 	// Detect orphaned ENIs
@@ -272,11 +384,8 @@ func (r Resource) Delete(ctx context.Context, id string, state ResourceState) er
 		// Continue even if detection fails - we don't want to block deletion
 	}
 
-	// Always perform cleanup on resource deletion, regardless of DryRun setting
-	// This ensures resources are cleaned up when the stack is destroyed
-	dryRun := false
 	if len(orphanedENIs) > 0 {
-		result := CleanupOrphanedENIs(ctx, orphanedENIs, dryRun, disassociateOnly, state.DefaultSecurityGroupId, state.SecurityGroupId)
+		result := CleanupOrphanedENIsWithOptions(ctx, orphanedENIs, cleanupOptions)
 		logging.V(5).Infof("Delete-time cleanup results: %d processed, %d failed, %d skipped",
 			result.SuccessCount, result.FailureCount, result.SkippedCount)
 	} else {
@@ -286,6 +395,371 @@ func (r Resource) Delete(ctx context.Context, id string, state ResourceState) er
 	return nil
 }
 
+// detectAndCleanup detects orphaned ENIs in the resource's regions and cleans
+// them up, in each of accountRoles when set
+func (args ResourceArgs) detectAndCleanup(ctx context.Context, options DetectOptions, cleanupOptions CleanupOptions) (DetectResult, CleanupResult, error) {
+	if len(args.AccountRoles) > 0 {
+		concurrency := 0
+		if args.AccountConcurrency != nil {
+			concurrency = *args.AccountConcurrency
+		}
+		detected, result := CombineAccountResults(RunAccounts(ctx, args.Regions, args.AccountRoles, options, &cleanupOptions, concurrency))
+		if len(result.FailedAccounts) == len(args.AccountRoles) {
+			return DetectResult{}, CleanupResult{}, fmt.Errorf("failed to scan every AWS account: %s", strings.Join(result.Errors, "; "))
+		}
+		logging.V(5).Infof("Detected %d orphaned ENIs across %d accounts, spared %d",
+			len(detected.ENIs), len(args.AccountRoles)-len(result.FailedAccounts), len(detected.Skipped))
+		args.writeOutputFile(&result)
+		return detected, result, nil
+	}
+
+	detected, err := DetectOrphanedENIsWithDetails(ctx, args.Regions, options)
+	if err != nil {
+		return DetectResult{}, CleanupResult{}, fmt.Errorf("failed to detect orphaned ENIs: %w", err)
+	}
+	logging.V(5).Infof("Detected %d orphaned ENIs, spared %d", len(detected.ENIs), len(detected.Skipped))
+
+	result := CleanupOrphanedENIsWithOptions(ctx, detected.ENIs, cleanupOptions)
+	result.SkippedDetails = detected.Skipped
+	ReconcileManualCleanupTags(ctx, detected, cleanupOptions, &result)
+	args.writeOutputFile(&result)
+	return detected, result, nil
+}
+
+// failedRegions lists the regions of detection errors for the resource state
+func failedRegions(errs []RegionError) []string {
+	var regions []string
+	for _, regionErr := range errs {
+		if regionErr.AccountID != "" {
+			regions = append(regions, regionErr.AccountID+"/"+regionErr.Region)
+			continue
+		}
+		regions = append(regions, regionErr.Region)
+	}
+	return regions
+}
+
+// detectOptions builds and validates the detection options for the resource arguments
+func (args ResourceArgs) detectOptions() (DetectOptions, error) {
+	logLevel := "info"
+	if args.LogLevel != nil {
+		logLevel = *args.LogLevel
+	}
+
+	options := DetectOptions{
+		SkipReservedDescriptions:   args.SkipReservedDescriptions,
+		SkipRequesterIds:           args.SkipRequesterIds,
+		SkipInterfaceTypes:         args.SkipInterfaceTypes,
+		OnlyInterfaceTypes:         args.OnlyInterfaceTypes,
+		IncludeTagKeys:             args.IncludeTagKeys,
+		ExcludeTagKeys:             args.ExcludeTagKeys,
+		OlderThanDays:              args.OlderThanDays,
+		LogLevel:                   logLevel,
+		SecurityGroupId:            args.SecurityGroupId,
+		CreatedTagKey:              args.CreatedTagKey,
+		UseCloudTrailForAge:        args.UseCloudTrailForAge != nil && *args.UseCloudTrailForAge,
+		DetachGracePeriod:          detachGracePeriod(args.DetachGracePeriodMinutes),
+		VerifyLoadBalancers:        args.VerifyLoadBalancers != nil && *args.VerifyLoadBalancers,
+		VerifyNATGateways:          args.VerifyNatGateways != nil && *args.VerifyNatGateways,
+		ExcludePublicIP:            args.ExcludePublicIp != nil && *args.ExcludePublicIp,
+		OnlyPublicIP:               args.OnlyPublicIp != nil && *args.OnlyPublicIp,
+		NetworkInterfaceIds:        args.NetworkInterfaceIds,
+		VpcIds:                     args.VpcIds,
+		SubnetIds:                  args.SubnetIds,
+		AvailabilityZones:          args.AvailabilityZones,
+		ExcludeCIDRs:               args.ExcludeCidrs,
+		ExcludeMacPrefixes:         args.ExcludeMacPrefixes,
+		IncludeDeleteOnTermination: args.IncludeDeleteOnTermination != nil && *args.IncludeDeleteOnTermination,
+		IncludeSharedSubnets:       args.IncludeSharedSubnets != nil && *args.IncludeSharedSubnets,
+		IncludeRequesterManaged:    args.IncludeRequesterManaged != nil && *args.IncludeRequesterManaged,
+		PolicyFile:                 args.PolicyFile,
+		IncludeTrunkBranchENIs:     args.IncludeTrunkBranchEnis != nil && *args.IncludeTrunkBranchEnis,
+		RecordAPIPath:              args.RecordApiPath,
+		RegionGroups:               args.RegionGroups,
+		DetectionProfiles:          detectionProfiles(args.DetectionProfiles),
+		EscalationPolicy:           escalationPolicy(args.EscalationPolicy),
+		RespectRecentDeploys:       args.RespectRecentDeploys != nil && *args.RespectRecentDeploys,
+		VerifyAttachmentOwners:     args.VerifyAttachmentOwners != nil && *args.VerifyAttachmentOwners,
+		VPCDeletionRatioOverrides:  args.VpcDeletionRatioOverrides,
+		DecisionLog:                args.DecisionLog != nil && *args.DecisionLog,
+		RoleOptions:                args.roleOptions(),
+		ConfigOptions:              args.configOptions(),
+	}
+
+	if args.DescribeBatchSize != nil {
+		options.DescribeBatchSize = *args.DescribeBatchSize
+	}
+
+	if args.EnrichmentConcurrency != nil {
+		options.EnrichmentConcurrency = *args.EnrichmentConcurrency
+	}
+
+	if args.RegionConcurrency != nil {
+		options.RegionConcurrency = *args.RegionConcurrency
+	}
+
+	if args.DetectionProfile != nil {
+		options.DetectionProfile = *args.DetectionProfile
+	}
+
+	if args.MaxVpcDeletionRatio != nil {
+		options.MaxVPCDeletionRatio = *args.MaxVpcDeletionRatio
+	}
+
+	if args.RecentDeployWindowMinutes != nil {
+		options.RecentDeployWindow = time.Duration(*args.RecentDeployWindowMinutes * float64(time.Minute))
+	}
+
+	if args.DeployTimestampTagKey != nil {
+		options.DeployTimestampTagKey = *args.DeployTimestampTagKey
+	}
+
+	if args.IntraRegionParallelism != nil {
+		options.IntraRegionParallelism = *args.IntraRegionParallelism
+	}
+
+	options.ReservedDescriptionPatterns = args.ReservedDescriptionPatterns
+
+	// Parse creation time bounds
+	createdAfter, err := parseTimeBound("createdAfter", args.CreatedAfter)
+	if err != nil {
+		return DetectOptions{}, err
+	}
+	createdBefore, err := parseTimeBound("createdBefore", args.CreatedBefore)
+	if err != nil {
+		return DetectOptions{}, err
+	}
+	options.CreatedAfter = createdAfter
+	options.CreatedBefore = createdBefore
+
+	if err := options.Validate(); err != nil {
+		return DetectOptions{}, fmt.Errorf("invalid ENI cleanup options: %w", err)
+	}
+
+	return options, nil
+}
+
+// cleanupOptions builds the cleanup options for the resource arguments
+func (args ResourceArgs) cleanupOptions() CleanupOptions {
+	options := CleanupOptions{
+		DryRun:                 args.DryRun != nil && *args.DryRun,
+		DisassociateOnly:       args.DisassociateOnly != nil && *args.DisassociateOnly,
+		DefaultSecurityGroupId: args.DefaultSecurityGroupId,
+		TargetSecurityGroupId:  args.SecurityGroupId,
+		RequireDefaultOnEmpty:  args.RequireDefaultOnEmpty != nil && *args.RequireDefaultOnEmpty,
+		TagWithStackInfo:       args.TagWithStackInfo != nil && *args.TagWithStackInfo,
+		StackName:              stringOrEnv(args.StackName, "PULUMI_STACK"),
+		ProjectName:            stringOrEnv(args.ProjectName, "PULUMI_PROJECT"),
+		DetachWaitByType:       detachWaitByType(args.DetachWaitSecondsByType),
+		RecordAPIPath:          args.RecordApiPath,
+		TagOnly:                args.TagOnly != nil && *args.TagOnly,
+		VerifyDeletion:         args.VerifyDeletion != nil && *args.VerifyDeletion,
+		EventBusName:           args.EventBusName,
+		RequireEmptyVPC:        args.RequireEmptyVpc != nil && *args.RequireEmptyVpc,
+		LockTableName:          args.LockTableName,
+		DecisionLog:            args.DecisionLog != nil && *args.DecisionLog,
+		HandleAssociations:     args.HandleAssociations != nil && *args.HandleAssociations,
+		EscalationPolicy:       escalationPolicy(args.EscalationPolicy),
+		MaxDeletions:           args.MaxDeletions,
+		RoleOptions:            args.roleOptions(),
+		ConfigOptions:          args.configOptions(),
+	}
+
+	if args.AuditRunId != nil {
+		options.AuditRunID = *args.AuditRunId
+	}
+
+	if args.TagConcurrency != nil {
+		options.TagConcurrency = *args.TagConcurrency
+	}
+
+	if args.DeleteConcurrency != nil {
+		options.DeleteConcurrency = *args.DeleteConcurrency
+	}
+
+	if args.DeleteAttempts != nil {
+		options.DeleteAttempts = *args.DeleteAttempts
+	}
+
+	if args.DeleteRetryBaseDelaySeconds != nil {
+		options.DeleteRetryBaseDelay = time.Duration(*args.DeleteRetryBaseDelaySeconds * float64(time.Second))
+	}
+
+	if args.DeletionStrikesRequired != nil {
+		options.DeletionStrikesRequired = *args.DeletionStrikesRequired
+	}
+
+	if args.RestoreSecurityGroupTagKey != nil {
+		options.RestoreSecurityGroupTagKey = *args.RestoreSecurityGroupTagKey
+	}
+
+	if args.ManualCleanupTagKey != nil {
+		options.ManualCleanupTagKey = *args.ManualCleanupTagKey
+	}
+
+	if args.AttemptedCleanupTimeTagKey != nil {
+		options.AttemptedCleanupTimeTagKey = *args.AttemptedCleanupTimeTagKey
+	}
+
+	if args.DeletionErrorTagKey != nil {
+		options.DeletionErrorTagKey = *args.DeletionErrorTagKey
+	}
+
+	if args.OmitDeletionErrorTag != nil {
+		options.OmitDeletionErrorTag = *args.OmitDeletionErrorTag
+	}
+
+	if args.ReconcileTags != nil {
+		options.ReconcileTags = *args.ReconcileTags
+	}
+
+	if args.ConfirmUnchanged != nil {
+		options.ConfirmUnchanged = *args.ConfirmUnchanged
+	}
+
+	if args.DetachTimeoutSeconds != nil {
+		options.DetachTimeout = time.Duration(*args.DetachTimeoutSeconds * float64(time.Second))
+	}
+
+	if args.PerEniTimeoutSeconds != nil {
+		options.PerENITimeout = time.Duration(*args.PerEniTimeoutSeconds * float64(time.Second))
+	}
+
+	if args.LockTtlSeconds != nil {
+		options.LockTTL = time.Duration(*args.LockTtlSeconds * float64(time.Second))
+	}
+
+	if args.LockWaitSeconds != nil {
+		options.LockWaitTimeout = time.Duration(*args.LockWaitSeconds * float64(time.Second))
+	}
+
+	return options
+}
+
+// preflightOptions builds the preflight checks for the resource arguments
+func (args ResourceArgs) preflightOptions() PreflightOptions {
+	return PreflightOptions{
+		ExpectedAccountId: args.ExpectedAccountId,
+		RoleOptions:       args.roleOptions(),
+		ConfigOptions:     args.configOptions(),
+	}
+}
+
+// roleOptions returns the role to assume in place of the default credentials
+func (args ResourceArgs) roleOptions() RoleOptions {
+	var role RoleOptions
+	if args.RoleArn != nil {
+		role.RoleArn = *args.RoleArn
+	}
+	if args.ExternalId != nil {
+		role.ExternalId = *args.ExternalId
+	}
+	if args.SessionName != nil {
+		role.SessionName = *args.SessionName
+	}
+	return role
+}
+
+// configOptions returns the AWS profile and endpoint to load config from
+func (args ResourceArgs) configOptions() ConfigOptions {
+	options := ConfigOptions{Profile: args.Profile, Endpoint: args.Endpoint}
+	if args.Qps != nil {
+		options.QPS = *args.Qps
+	}
+	return options
+}
+
+// skipDeleteTimeCleanup reports whether Delete should remove the resource without cleaning up ENIs
+func (args ResourceArgs) skipDeleteTimeCleanup() bool {
+	return args.SkipDeleteTimeCleanup != nil && *args.SkipDeleteTimeCleanup
+}
+
+// stringOrEnv returns the optional argument, falling back to an environment variable.
+// The provider protocol doesn't expose the calling stack to Create, so programs pass
+// ctx.Stack() and ctx.Project() explicitly, or the engine's environment is used.
+func stringOrEnv(value *string, envVar string) string {
+	if value != nil && *value != "" {
+		return *value
+	}
+	return os.Getenv(envVar)
+}
+
+// detachGracePeriod converts the optional grace period argument, applying the default
+func detachGracePeriod(minutes *float64) time.Duration {
+	if minutes == nil {
+		return DefaultDetachGracePeriod
+	}
+	return time.Duration(*minutes * float64(time.Minute))
+}
+
+// detachWaitByType converts the optional per-type wait argument from seconds
+func detachWaitByType(seconds map[string]float64) map[string]time.Duration {
+	if len(seconds) == 0 {
+		return nil
+	}
+	waits := make(map[string]time.Duration, len(seconds))
+	for interfaceType, s := range seconds {
+		waits[interfaceType] = time.Duration(s * float64(time.Second))
+	}
+	return waits
+}
+
+// escalationPolicy converts the optional escalation policy definition
+func escalationPolicy(args *EscalationPolicyArgs) *EscalationPolicy {
+	if args == nil {
+		return nil
+	}
+	policy := &EscalationPolicy{Actions: args.Actions}
+	if args.MediumAfterDays != nil {
+		policy.MediumAfter = time.Duration(*args.MediumAfterDays * 24 * float64(time.Hour))
+	}
+	if args.HighAfterDays != nil {
+		policy.HighAfter = time.Duration(*args.HighAfterDays * 24 * float64(time.Hour))
+	}
+	return policy
+}
+
+// detectionProfiles converts the optional detection profile definitions
+func detectionProfiles(args map[string]DetectionProfileArgs) map[string]DetectionProfile {
+	if len(args) == 0 {
+		return nil
+	}
+	converted := make(map[string]DetectionProfile, len(args))
+	for name, profile := range args {
+		p := DetectionProfile{
+			InterfaceTypes:         profile.InterfaceTypes,
+			SubnetIDs:              profile.SubnetIds,
+			RequireAvailable:       profile.RequireAvailable != nil && *profile.RequireAvailable,
+			IncludeTrunkBranchENIs: profile.IncludeTrunkBranchEnis != nil && *profile.IncludeTrunkBranchEnis,
+		}
+		if profile.NameTagPrefix != nil {
+			p.NameTagPrefix = *profile.NameTagPrefix
+		}
+		if profile.DescriptionPrefix != nil {
+			p.DescriptionPrefix = *profile.DescriptionPrefix
+		}
+		if profile.MinAgeDays != nil {
+			p.MinAge = time.Duration(*profile.MinAgeDays * 24 * float64(time.Hour))
+		}
+		converted[name] = p
+	}
+	return converted
+}
+
+// parseTimeBound parses an optional RFC3339 timestamp argument
+func parseTimeBound(name string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, invalidOption(name, "invalid %s %q: must be an RFC3339 timestamp: %w", name, *value, err)
+	}
+
+	return &t, nil
+}
+
 // Annotate sets annotations for the resource.
 func (r Resource) Annotate() map[string]interface{} {
 	return map[string]interface{}{
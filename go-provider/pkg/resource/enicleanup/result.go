@@ -0,0 +1,129 @@
+package enicleanup
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// resultAccumulator collects a CleanupResult and reports per-ENI results. It
+// is safe for concurrent use by cleanup workers, and OnENIResult callbacks
+// are serialized.
+type resultAccumulator struct {
+	mu       sync.Mutex
+	result   CleanupResult
+	onResult func(ENIResult)
+}
+
+// newResultAccumulator creates an empty accumulator reporting to onResult, if set
+func newResultAccumulator(onResult func(ENIResult)) *resultAccumulator {
+	return &resultAccumulator{
+		result: CleanupResult{
+			CleanedENIs: make([]CleanedENI, 0),
+			Errors:      make([]string, 0),
+		},
+		onResult: onResult,
+	}
+}
+
+// skip counts a skipped ENI
+func (a *resultAccumulator) skip() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.SkippedCount++
+}
+
+// fail counts failed ENIs and records the error
+func (a *resultAccumulator) fail(count int, errMsg string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.FailureCount += count
+	a.result.Errors = append(a.result.Errors, errMsg)
+}
+
+// addError records an error without changing the counts
+func (a *resultAccumulator) addError(errMsg string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.Errors = append(a.result.Errors, errMsg)
+}
+
+// verificationFailed records deleted ENIs that could not be confirmed gone
+func (a *resultAccumulator) verificationFailed(ids []string, errMsg string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.VerificationFailed = append(a.result.VerificationFailed, ids...)
+	a.result.Errors = append(a.result.Errors, errMsg)
+}
+
+// deadLetter records an ENI that failed every cleanup step, including tagging
+func (a *resultAccumulator) deadLetter(eni CleanedENI) {
+	stamp(&eni)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.DeadLetter = append(a.result.DeadLetter, eni)
+}
+
+// manualCleanup records an ENI tagged NeedsManualCleanup
+func (a *resultAccumulator) manualCleanup(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.ManualCleanup = append(a.result.ManualCleanup, id)
+}
+
+// truncate records that the run stopped before processing every candidate
+func (a *resultAccumulator) truncate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.Truncated = true
+}
+
+// regionTook records how long cleanup took in a region
+func (a *resultAccumulator) regionTook(region string, duration time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.result.RegionTimings == nil {
+		a.result.RegionTimings = make(map[string]time.Duration)
+	}
+	a.result.RegionTimings[region] = duration
+}
+
+// clean counts a cleaned ENI
+func (a *resultAccumulator) clean(eni CleanedENI) {
+	stamp(&eni)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result.SuccessCount++
+	a.result.CleanedENIs = append(a.result.CleanedENIs, eni)
+}
+
+// stamp records the current time on an ENI that doesn't have one yet
+func stamp(eni *CleanedENI) {
+	if eni.Timestamp == "" {
+		eni.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+}
+
+// report passes a per-ENI result to the callback, one call at a time
+func (a *resultAccumulator) report(result ENIResult) {
+	if a.onResult == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onResult(result)
+}
+
+// snapshot returns a copy of the result accumulated so far
+func (a *resultAccumulator) snapshot() CleanupResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	result := a.result
+	result.CleanedENIs = append(make([]CleanedENI, 0, len(a.result.CleanedENIs)), a.result.CleanedENIs...)
+	result.Errors = append(make([]string, 0, len(a.result.Errors)), a.result.Errors...)
+	result.VerificationFailed = append([]string(nil), a.result.VerificationFailed...)
+	result.DeadLetter = append([]CleanedENI(nil), a.result.DeadLetter...)
+	result.ManualCleanup = append([]string(nil), a.result.ManualCleanup...)
+	result.RegionTimings = maps.Clone(a.result.RegionTimings)
+	return result
+}
@@ -0,0 +1,93 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Result codes summarising a run for orchestrators, so they can retry, alert
+// or page accordingly. The CLI exits with them and the Lambda returns them.
+const (
+	// ResultCodeClean means every candidate was cleaned up or deliberately skipped
+	ResultCodeClean = 0
+	// ResultCodeManualCleanup means some ENIs failed and were tagged NeedsManualCleanup
+	ResultCodeManualCleanup = 1
+	// ResultCodeFailed means hard failures: ENIs that failed without being
+	// tagged, dead-lettered ENIs, deletions that didn't take effect, or
+	// accounts that couldn't be scanned
+	ResultCodeFailed = 2
+	// ResultCodePermissionDenied means AWS rejected the credentials or an action
+	ResultCodePermissionDenied = 3
+	// ResultCodeTruncated means the run stopped before processing every candidate
+	ResultCodeTruncated = 4
+)
+
+// permissionErrorMarkers are the AWS error codes and SDK messages that mean
+// the credentials are missing, expired or lack a permission
+var permissionErrorMarkers = []string{
+	"UnauthorizedOperation",
+	"AccessDenied",
+	"AuthFailure",
+	"ExpiredToken",
+	"InvalidClientTokenId",
+	"UnrecognizedClientException",
+	"failed to retrieve credentials",
+}
+
+// isPermissionError reports whether an error message is a permission or credential error
+func isPermissionError(msg string) bool {
+	for _, marker := range permissionErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultCode maps a cleanup result to the most severe result code that applies
+func ResultCode(result CleanupResult) int {
+	for _, errMsg := range result.Errors {
+		if isPermissionError(errMsg) {
+			return ResultCodePermissionDenied
+		}
+	}
+
+	if result.Truncated {
+		return ResultCodeTruncated
+	}
+
+	// Tagged ENIs still in CleanedENIs were only partly cleaned, not failed
+	cleaned := make(map[string]bool, len(result.CleanedENIs))
+	for _, eni := range result.CleanedENIs {
+		cleaned[eni.ID] = true
+	}
+	taggedFailures := 0
+	for _, id := range result.ManualCleanup {
+		if !cleaned[id] {
+			taggedFailures++
+		}
+	}
+	if len(result.DeadLetter) > 0 || len(result.VerificationFailed) > 0 || len(result.FailedAccounts) > 0 || result.FailureCount > taggedFailures {
+		return ResultCodeFailed
+	}
+
+	if len(result.ManualCleanup) > 0 {
+		return ResultCodeManualCleanup
+	}
+	return ResultCodeClean
+}
+
+// ErrorResultCode maps an error that stopped a run to a result code
+func ErrorResultCode(err error) int {
+	switch {
+	case err == nil:
+		return ResultCodeClean
+	case isPermissionError(err.Error()):
+		return ResultCodePermissionDenied
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ResultCodeTruncated
+	default:
+		return ResultCodeFailed
+	}
+}
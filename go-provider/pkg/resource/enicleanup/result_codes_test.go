@@ -0,0 +1,117 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestResultCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		result CleanupResult
+		want   int
+	}{
+		{
+			name:   "clean",
+			result: CleanupResult{SuccessCount: 2, SkippedCount: 1},
+			want:   ResultCodeClean,
+		},
+		{
+			name:   "tagged for manual cleanup",
+			result: CleanupResult{FailureCount: 1, ManualCleanup: []string{"eni-1"}},
+			want:   ResultCodeManualCleanup,
+		},
+		{
+			name: "delete failed after disassociation",
+			result: CleanupResult{
+				SuccessCount:  1,
+				CleanedENIs:   []CleanedENI{{ID: "eni-1"}},
+				ManualCleanup: []string{"eni-1"},
+			},
+			want: ResultCodeManualCleanup,
+		},
+		{
+			name: "region failure alongside a partly cleaned ENI",
+			result: CleanupResult{
+				SuccessCount:  1,
+				FailureCount:  1,
+				CleanedENIs:   []CleanedENI{{ID: "eni-1"}},
+				ManualCleanup: []string{"eni-1"},
+				Errors:        []string{"Error loading AWS config for region us-west-2"},
+			},
+			want: ResultCodeFailed,
+		},
+		{
+			name:   "dead letter",
+			result: CleanupResult{FailureCount: 1, DeadLetter: []CleanedENI{{ID: "eni-1"}}},
+			want:   ResultCodeFailed,
+		},
+		{
+			name:   "verification failed",
+			result: CleanupResult{SuccessCount: 1, VerificationFailed: []string{"eni-1"}},
+			want:   ResultCodeFailed,
+		},
+		{
+			name:   "truncated",
+			result: CleanupResult{FailureCount: 1, Truncated: true},
+			want:   ResultCodeTruncated,
+		},
+		{
+			name: "permission denied",
+			result: CleanupResult{
+				FailureCount: 1,
+				Truncated:    true,
+				Errors:       []string{"Failed to modify security groups for ENI eni-1: UnauthorizedOperation"},
+			},
+			want: ResultCodePermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResultCode(tt.result); got != tt.want {
+				t.Errorf("ResultCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResultCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: ResultCodeClean},
+		{err: errors.New("failed to verify AWS account: ExpiredToken: token expired"), want: ResultCodePermissionDenied},
+		{err: fmt.Errorf("interrupted before region us-east-1: %w", context.Canceled), want: ResultCodeTruncated},
+		{err: errors.New("failed to detect orphaned ENIs: throttled"), want: ResultCodeFailed},
+	}
+
+	for _, tt := range tests {
+		if got := ErrorResultCode(tt.err); got != tt.want {
+			t.Errorf("ErrorResultCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCancelledCleanupIsTruncated(t *testing.T) {
+	fake := newFakeEC2()
+	useFakeEC2(t, fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	enis := []OrphanedENI{{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1"}}
+	result := CleanupOrphanedENIsWithOptions(ctx, enis, CleanupOptions{DefaultSecurityGroupId: aws.String("sg-default")})
+
+	if !result.Truncated || ResultCode(result) != ResultCodeTruncated {
+		t.Errorf("expected a truncated result, got %+v", result)
+	}
+	if fake.deleted["eni-1"] != 0 {
+		t.Error("expected no ENIs to be processed after cancellation")
+	}
+}
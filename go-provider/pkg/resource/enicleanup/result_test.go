@@ -0,0 +1,63 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Run with -race to verify concurrent workers can share the accumulator
+func TestResultAccumulatorConcurrentCleanup(t *testing.T) {
+	const enis = 200
+	const workers = 16
+
+	fake := newFakeEC2()
+	candidates := make(chan OrphanedENI, enis)
+	for i := 0; i < enis; i++ {
+		eni := OrphanedENI{ID: fmt.Sprintf("eni-%d", i), Region: "us-east-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-app"}}
+		if i%4 == 0 {
+			// Lacks the target group, so it is skipped
+			eni.SecurityGroups = []string{"sg-other"}
+		}
+		candidates <- eni
+	}
+	close(candidates)
+
+	// The callback deliberately has no locking of its own
+	reported := 0
+	options := CleanupOptions{
+		DisassociateOnly:      true,
+		TargetSecurityGroupId: aws.String("sg-app"),
+		DeletedENIs:           NewDeletedENIs(),
+		OnENIResult:           func(ENIResult) { reported++ },
+	}
+	results := newResultAccumulator(options.OnENIResult)
+	cache := newSGVPCCache()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for eni := range candidates {
+				action, outcome, errMsg := cleanupENI(context.Background(), fake, eni, options, "sg-default", cache, results)
+				results.report(ENIResult{ENI: eni, Action: action, Outcome: outcome, Error: errMsg})
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := results.snapshot()
+	if result.SuccessCount != 150 || result.SkippedCount != 50 || result.FailureCount != 0 {
+		t.Errorf("expected 150 cleaned and 50 skipped, got %+v", result)
+	}
+	if len(result.CleanedENIs) != result.SuccessCount {
+		t.Errorf("expected %d cleaned ENIs, got %d", result.SuccessCount, len(result.CleanedENIs))
+	}
+	if reported != enis {
+		t.Errorf("expected %d reported results, got %d", enis, reported)
+	}
+}
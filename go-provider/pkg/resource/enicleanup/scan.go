@@ -0,0 +1,93 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// EC2's bounds on the DescribeNetworkInterfaces page size
+const (
+	minDescribeBatchSize = 5
+	maxDescribeBatchSize = 1000
+)
+
+// describeBatchSize returns the DescribeBatchSize clamped to EC2's bounds, or
+// nil to use EC2's default page size
+func (o DetectOptions) describeBatchSize() *int32 {
+	if o.DescribeBatchSize <= 0 {
+		return nil
+	}
+	size := min(max(o.DescribeBatchSize, minDescribeBatchSize), maxDescribeBatchSize)
+	if size != o.DescribeBatchSize {
+		logging.Warningf("describeBatchSize %d is outside EC2's range of %d to %d; using %d", o.DescribeBatchSize, minDescribeBatchSize, maxDescribeBatchSize, size)
+	}
+	return aws.Int32(int32(size))
+}
+
+// scanRegion lists the ENIs in a region matching the filters, in pages of
+// batchSize when set. With a parallelism above one, the scan is partitioned by
+// availability zone and up to that many zones are described concurrently,
+// limited to the zones of an availability-zone filter if there is one.
+func scanRegion(ctx context.Context, client ENIClient, filters []types.Filter, parallelism int, batchSize *int32) ([]types.NetworkInterface, error) {
+	if parallelism <= 1 {
+		return findNetworkInterfacesPaged(ctx, client, filters, batchSize)
+	}
+
+	zones, err := client.DescribeAvailabilityZones(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe availability zones: %w", err)
+	}
+	if scope, rest, ok := zoneScope(filters); ok {
+		zones.AvailabilityZones = slices.DeleteFunc(slices.Clone(zones.AvailabilityZones), func(zone types.AvailabilityZone) bool {
+			return !slices.Contains(scope, aws.ToString(zone.ZoneName))
+		})
+		filters = rest
+	}
+
+	results := make([][]types.NetworkInterface, len(zones.AvailabilityZones))
+	errs := make([]error, len(zones.AvailabilityZones))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, zone := range zones.AvailabilityZones {
+		zoneName := aws.ToString(zone.ZoneName)
+		zoneFilters := append(append([]types.Filter{}, filters...), types.Filter{
+			Name:   aws.String(availabilityZoneFilter),
+			Values: []string{zoneName},
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			enis, err := findNetworkInterfacesPaged(ctx, client, zoneFilters, batchSize)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", zoneName, err)
+				return
+			}
+			results[i] = enis
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Merge in zone order so results are stable between runs
+	var enis []types.NetworkInterface
+	for _, zoneENIs := range results {
+		enis = append(enis, zoneENIs...)
+	}
+	return enis, nil
+}
@@ -0,0 +1,202 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DefaultRestoreSecurityGroupTagKey is the tag an ENI's owner can set to the
+// security group to assign when cleanup removes the ENI's groups
+const DefaultRestoreSecurityGroupTagKey = "eni-cleanup:restore-sg"
+
+// lookupDefaultSecurityGroup finds the ID of the "default" security group of a VPC
+func lookupDefaultSecurityGroup(ctx context.Context, client ENIClient, vpcID string) (string, error) {
+	resp, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("group-name"),
+				Values: []string{"default"},
+			},
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []string{vpcID},
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe default security group for VPC %s: %w", vpcID, err)
+	}
+
+	for _, group := range resp.SecurityGroups {
+		if group.GroupId != nil {
+			return *group.GroupId, nil
+		}
+	}
+
+	return "", fmt.Errorf("no default security group found for VPC %s", vpcID)
+}
+
+// sgVPCCache caches the security group metadata a cleanup run looks up: the
+// VPC each security group belongs to, and each VPC's default security group.
+// Entries are keyed by region, failed lookups are cached too, so each is made
+// at most once per run, and it is safe for concurrent use.
+type sgVPCCache struct {
+	mu sync.Mutex
+	// lookupDefault and lookupVPC are replaced by tests
+	lookupDefault func(ctx context.Context, client ENIClient, vpcID string) (string, error)
+	lookupVPC     func(ctx context.Context, client ENIClient, groupID string) (string, bool, error)
+	defaults      map[regionalID]defaultSecurityGroupEntry
+	groups        map[regionalID]securityGroupVPCEntry
+}
+
+// regionalID identifies a VPC or security group within a region
+type regionalID struct {
+	region string
+	id     string
+}
+
+// defaultSecurityGroupEntry is the cached result of a default security group lookup
+type defaultSecurityGroupEntry struct {
+	groupID string
+	err     error
+}
+
+// securityGroupVPCEntry is the cached result of a security group's VPC lookup
+type securityGroupVPCEntry struct {
+	vpcID string
+	found bool
+	err   error
+}
+
+// newSGVPCCache creates an empty cache for a cleanup run
+func newSGVPCCache() *sgVPCCache {
+	return &sgVPCCache{
+		lookupDefault: lookupDefaultSecurityGroup,
+		lookupVPC:     lookupSecurityGroupVPC,
+		defaults:      make(map[regionalID]defaultSecurityGroupEntry),
+		groups:        make(map[regionalID]securityGroupVPCEntry),
+	}
+}
+
+// defaultGroup returns the default security group of a VPC, looking it up on
+// first use. The lock is held during the lookup so concurrent callers wait
+// for it rather than repeat it.
+func (c *sgVPCCache) defaultGroup(ctx context.Context, client ENIClient, region string, vpcID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := regionalID{region: region, id: vpcID}
+	if entry, ok := c.defaults[key]; ok {
+		return entry.groupID, entry.err
+	}
+
+	groupID, err := c.lookupDefault(ctx, client, vpcID)
+	if err == nil {
+		logging.V(5).Infof("Discovered default security group %s for VPC %s in %s", groupID, vpcID, region)
+		c.groups[regionalID{region: region, id: groupID}] = securityGroupVPCEntry{vpcID: vpcID, found: true}
+	}
+	c.defaults[key] = defaultSecurityGroupEntry{groupID: groupID, err: err}
+	return groupID, err
+}
+
+// vpcOf returns the VPC a security group belongs to, looking it up on first
+// use. found is false when the group doesn't exist.
+func (c *sgVPCCache) vpcOf(ctx context.Context, client ENIClient, region string, groupID string) (vpcID string, found bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := regionalID{region: region, id: groupID}
+	if entry, ok := c.groups[key]; ok {
+		return entry.vpcID, entry.found, entry.err
+	}
+
+	vpcID, found, err = c.lookupVPC(ctx, client, groupID)
+	c.groups[key] = securityGroupVPCEntry{vpcID: vpcID, found: found, err: err}
+	return vpcID, found, err
+}
+
+// lookupSecurityGroupVPC finds the VPC of a security group
+func lookupSecurityGroupVPC(ctx context.Context, client ENIClient, groupID string) (string, bool, error) {
+	// Filtering by ID, unlike passing GroupIds, doesn't fail on unknown IDs
+	resp, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("group-id"),
+				Values: []string{groupID},
+			},
+		},
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to describe security group %s: %w", groupID, err)
+	}
+
+	for _, group := range resp.SecurityGroups {
+		if aws.ToString(group.GroupId) == groupID {
+			return aws.ToString(group.VpcId), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// restoreSecurityGroup returns the security group pinned by the ENI's restore
+// tag, if the group exists in the ENI's VPC
+func restoreSecurityGroup(ctx context.Context, client ENIClient, eni OrphanedENI, tagKey string, cache *sgVPCCache) (string, bool) {
+	groupID := eni.Tags[tagKey]
+	if groupID == "" {
+		return "", false
+	}
+
+	vpcID, found, err := cache.vpcOf(ctx, client, eni.Region, groupID)
+	if err != nil {
+		logging.V(5).Infof("Ignoring %s tag on ENI %s: %v", tagKey, eni.ID, err)
+		return "", false
+	}
+	if !found || (eni.VPCID != "" && vpcID != eni.VPCID) {
+		logging.V(5).Infof("Ignoring %s tag on ENI %s: security group %s not found in VPC %s", tagKey, eni.ID, groupID, eni.VPCID)
+		return "", false
+	}
+	return groupID, true
+}
+
+// resolveFallbackSecurityGroup returns the security group to assign to an ENI when
+// removing its groups would leave it with none. A group pinned by the ENI's
+// restore tag takes precedence, then an explicitly configured default in the
+// ENI's VPC; otherwise the VPC's default security group is discovered through
+// the run's cache, unless requireDefault forbids auto-discovery.
+func resolveFallbackSecurityGroup(ctx context.Context, client ENIClient, eni OrphanedENI, restoreTagKey string, defaultSG string, requireDefault bool, cache *sgVPCCache) (string, error) {
+	if groupID, ok := restoreSecurityGroup(ctx, client, eni, restoreTagKey, cache); ok {
+		return groupID, nil
+	}
+
+	if defaultSG != "" {
+		// A group from another VPC can't be assigned; one that can't be
+		// looked up is tried anyway
+		vpcID, found, err := cache.vpcOf(ctx, client, eni.Region, defaultSG)
+		if err != nil {
+			logging.V(5).Infof("Could not check the VPC of default security group %s: %v", defaultSG, err)
+		}
+		if err != nil || !found || eni.VPCID == "" || vpcID == eni.VPCID {
+			return defaultSG, nil
+		}
+		if requireDefault {
+			return "", fmt.Errorf("default security group %s is in VPC %s, not VPC %s of ENI %s", defaultSG, vpcID, eni.VPCID, eni.ID)
+		}
+		logging.V(5).Infof("Default security group %s is in VPC %s, not %s; discovering the default security group of ENI %s's VPC", defaultSG, vpcID, eni.VPCID, eni.ID)
+	}
+
+	if requireDefault {
+		return "", fmt.Errorf("ENI %s would be left without security groups and no default security group was provided", eni.ID)
+	}
+
+	if eni.VPCID == "" {
+		return "", fmt.Errorf("cannot discover default security group for ENI %s: VPC ID is unknown", eni.ID)
+	}
+
+	return cache.defaultGroup(ctx, client, eni.Region, eni.VPCID)
+}
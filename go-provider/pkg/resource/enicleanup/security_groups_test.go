@@ -0,0 +1,137 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestDefaultSecurityGroupCache(t *testing.T) {
+	lookups := make(map[string]int)
+	cache := newSGVPCCache()
+	cache.lookupDefault = func(ctx context.Context, client ENIClient, vpcID string) (string, error) {
+		lookups[vpcID]++
+		if vpcID == "vpc-missing" {
+			return "", errors.New("no default security group")
+		}
+		return "sg-" + vpcID, nil
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if sg, err := cache.defaultGroup(ctx, nil, "us-east-1", "vpc-1"); err != nil || sg != "sg-vpc-1" {
+			t.Fatalf("unexpected lookup result %q, %v", sg, err)
+		}
+		if _, err := cache.defaultGroup(ctx, nil, "us-east-1", "vpc-missing"); err == nil {
+			t.Fatal("expected cached lookup error")
+		}
+	}
+	if lookups["vpc-1"] != 1 || lookups["vpc-missing"] != 1 {
+		t.Errorf("expected one lookup per VPC, got %v", lookups)
+	}
+
+	// The same VPC ID in another region is looked up separately
+	if _, err := cache.defaultGroup(ctx, nil, "us-west-2", "vpc-1"); err != nil {
+		t.Fatal(err)
+	}
+	if lookups["vpc-1"] != 2 {
+		t.Errorf("expected a separate lookup per region, got %d", lookups["vpc-1"])
+	}
+}
+
+func TestRestoreSecurityGroupTag(t *testing.T) {
+	fake := newFakeEC2()
+	fake.securityGroups["sg-pinned"] = "vpc-1"
+	fake.securityGroups["sg-other-vpc"] = "vpc-2"
+
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "pinned group is restored", tag: "sg-pinned", want: "sg-pinned"},
+		{name: "group in another VPC falls back", tag: "sg-other-vpc", want: "sg-default"},
+		{name: "missing group falls back", tag: "sg-missing", want: "sg-default"},
+		{name: "no tag falls back", want: "sg-default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eni := OrphanedENI{ID: "eni-1", VPCID: "vpc-1", Tags: map[string]string{}}
+			if tt.tag != "" {
+				eni.Tags[DefaultRestoreSecurityGroupTagKey] = tt.tag
+			}
+			got, err := resolveFallbackSecurityGroup(context.Background(), fake, eni, DefaultRestoreSecurityGroupTagKey, "sg-default", false, newSGVPCCache())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSGVPCCacheSharesLookups(t *testing.T) {
+	var mu sync.Mutex
+	lookups := make(map[string]int)
+	cache := newSGVPCCache()
+	cache.lookupVPC = func(ctx context.Context, client ENIClient, groupID string) (string, bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups[groupID]++
+		return "vpc-1", groupID != "sg-missing", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if vpcID, found, err := cache.vpcOf(context.Background(), nil, "us-east-1", "sg-app"); err != nil || !found || vpcID != "vpc-1" {
+				t.Errorf("unexpected lookup result %q, %v, %v", vpcID, found, err)
+			}
+			if _, found, _ := cache.vpcOf(context.Background(), nil, "us-east-1", "sg-missing"); found {
+				t.Error("expected sg-missing not to be found")
+			}
+		}()
+	}
+	wg.Wait()
+	if lookups["sg-app"] != 1 || lookups["sg-missing"] != 1 {
+		t.Errorf("expected one lookup per security group, got %v", lookups)
+	}
+
+	// Discovered default security groups are known to be in their VPC
+	cache.lookupDefault = func(ctx context.Context, client ENIClient, vpcID string) (string, error) {
+		return "sg-default-2", nil
+	}
+	if _, err := cache.defaultGroup(context.Background(), nil, "us-east-1", "vpc-2"); err != nil {
+		t.Fatal(err)
+	}
+	if vpcID, found, _ := cache.vpcOf(context.Background(), nil, "us-east-1", "sg-default-2"); !found || vpcID != "vpc-2" || lookups["sg-default-2"] != 0 {
+		t.Errorf("expected the discovered default to be cached in vpc-2, got %q, %v", vpcID, found)
+	}
+}
+
+func TestDefaultSecurityGroupFromAnotherVPC(t *testing.T) {
+	fake := newFakeEC2()
+	fake.securityGroups["sg-configured"] = "vpc-2"
+	eni := OrphanedENI{ID: "eni-1", Region: "us-east-1", VPCID: "vpc-1", Tags: map[string]string{}}
+
+	// The VPC's own default is discovered instead
+	cache := newSGVPCCache()
+	cache.lookupDefault = func(ctx context.Context, client ENIClient, vpcID string) (string, error) {
+		return "sg-default-" + vpcID, nil
+	}
+	got, err := resolveFallbackSecurityGroup(context.Background(), fake, eni, DefaultRestoreSecurityGroupTagKey, "sg-configured", false, cache)
+	if err != nil || got != "sg-default-vpc-1" {
+		t.Errorf("expected the VPC default security group, got %q, %v", got, err)
+	}
+
+	// Unless discovery is forbidden
+	_, err = resolveFallbackSecurityGroup(context.Background(), fake, eni, DefaultRestoreSecurityGroupTagKey, "sg-configured", true, newSGVPCCache())
+	if err == nil {
+		t.Error("expected a default security group from another VPC to be refused")
+	}
+}
@@ -0,0 +1,40 @@
+package enicleanup
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// StrikesTagKey counts the cleanup runs that found an ENI orphaned without acting on it
+const StrikesTagKey = "eni-cleanup:strikes"
+
+// currentStrikes reads the strike count from an ENI's tags, treating a missing
+// or malformed tag as no strikes
+func currentStrikes(tags map[string]string) int {
+	strikes, err := strconv.Atoi(tags[StrikesTagKey])
+	if err != nil || strikes < 0 {
+		return 0
+	}
+	return strikes
+}
+
+// recordStrike tags an ENI with its updated strike count
+func recordStrike(ctx context.Context, client ENIClient, eniID string, strikes int) {
+	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{eniID},
+		Tags: []types.Tag{
+			{
+				Key:   aws.String(StrikesTagKey),
+				Value: aws.String(strconv.Itoa(strikes)),
+			},
+		},
+	})
+	if err != nil {
+		logging.V(5).Infof("Failed to record strike %d for ENI %s: %v", strikes, eniID, err)
+	}
+}
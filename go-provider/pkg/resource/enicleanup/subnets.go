@@ -0,0 +1,65 @@
+package enicleanup
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// eniSubnetIDs returns the distinct subnets of the ENIs, in batches small
+// enough for a filter
+func eniSubnetIDs(enis []types.NetworkInterface) [][]string {
+	var subnetIDs []string
+	seen := make(map[string]bool)
+	for _, eni := range enis {
+		if subnetID := aws.ToString(eni.SubnetId); subnetID != "" && !seen[subnetID] {
+			seen[subnetID] = true
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+
+	var batches [][]string
+	for start := 0; start < len(subnetIDs); start += maxFilterValues {
+		batches = append(batches, subnetIDs[start:min(start+maxFilterValues, len(subnetIDs))])
+	}
+	return batches
+}
+
+// subnetOwners returns the account owning each of the given subnets
+func subnetOwners(ctx context.Context, client ENIClient, subnetIDs []string) (map[string]string, error) {
+	// Filtering by ID, unlike passing SubnetIds, doesn't fail on unknown IDs
+	owners := make(map[string]string)
+	paginator := ec2.NewDescribeSubnetsPaginator(client, &ec2.DescribeSubnetsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("subnet-id"),
+				Values: subnetIDs,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, subnet := range page.Subnets {
+			owners[aws.ToString(subnet.SubnetId)] = aws.ToString(subnet.OwnerId)
+		}
+	}
+	return owners, nil
+}
+
+// sharedSubnetOwner returns the owner of the ENI's subnet if another account
+// owns it. In a RAM-shared subnet the subnet owner and the ENI owner differ,
+// whichever side of the share is scanning, and deleting the ENI can disrupt
+// the other account.
+func sharedSubnetOwner(eni types.NetworkInterface, owners map[string]string) (string, bool) {
+	subnetOwner := owners[aws.ToString(eni.SubnetId)]
+	eniOwner := aws.ToString(eni.OwnerId)
+	if subnetOwner == "" || eniOwner == "" || subnetOwner == eniOwner {
+		return "", false
+	}
+	return subnetOwner, true
+}
@@ -0,0 +1,45 @@
+package enicleanup
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans emitted by the detection and cleanup engine
+const tracerName = "github.com/organization/aws-eni-cleanup-provider/pkg/resource/enicleanup"
+
+// Span attribute keys
+const (
+	attrRegion     = "cloud.region"
+	attrRegions    = "eni_cleanup.regions"
+	attrENIID      = "eni_cleanup.eni_id"
+	attrAction     = "eni_cleanup.action"
+	attrOutcome    = "eni_cleanup.outcome"
+	attrCandidates = "eni_cleanup.candidates"
+)
+
+// startSpan starts a span using the tracer provider of the span already in the
+// context, falling back to the global provider. Both are no-ops unless a
+// tracer provider has been registered.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	var tracer trace.Tracer
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		tracer = parent.TracerProvider().Tracer(tracerName)
+	} else {
+		tracer = otel.Tracer(tracerName)
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records an error, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
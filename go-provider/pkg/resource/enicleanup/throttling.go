@@ -0,0 +1,209 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// throttleAttempts is how many times a throttled EC2 call is attempted
+// before its error is returned
+const throttleAttempts = 5
+
+// maxThrottleDelay caps the wait between throttled attempts
+const maxThrottleDelay = 20 * time.Second
+
+// throttleBaseDelay is the backoff before the first retry of a throttled
+// call, doubling with each further retry. Tests shorten it.
+var throttleBaseDelay = 500 * time.Millisecond
+
+// throttlingErrorCodes are the API error codes AWS uses to reject calls over
+// the account's request rate
+var throttlingErrorCodes = []string{
+	"RequestLimitExceeded",
+	"Throttling",
+	"ThrottlingException",
+	"ThrottledException",
+	"RequestThrottled",
+	"RequestThrottledException",
+	"TooManyRequestsException",
+}
+
+// isThrottlingError reports whether an error is AWS rejecting a call over the
+// request rate, rather than the call itself failing
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && slices.Contains(throttlingErrorCodes, apiErr.ErrorCode())
+}
+
+// waitContext waits for delay, returning early with the context's error if
+// it is done first
+func waitContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter spaces calls at least interval apart. Waiting callers reserve
+// consecutive slots, so concurrent callers are spaced too.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter creates a limiter allowing qps calls per second, or nil for
+// no limit when qps isn't positive
+func newRateLimiter(qps float64) *rateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / qps)}
+}
+
+// wait blocks until the caller's slot, or returns ctx's error if it is done first
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay := slot.Sub(now); delay > 0 {
+		return waitContext(ctx, delay)
+	}
+	return nil
+}
+
+// throttledClient wraps an ENIClient to space its calls with an optional
+// rate limiter and retry calls AWS throttled, with jittered exponential
+// backoff. Other errors are returned as they are.
+type throttledClient struct {
+	client  ENIClient
+	limiter *rateLimiter
+}
+
+// newThrottledClient wraps client, limiting it to qps calls per second when
+// qps is positive
+func newThrottledClient(client ENIClient, qps float64) ENIClient {
+	return &throttledClient{client: client, limiter: newRateLimiter(qps)}
+}
+
+// throttled makes a call through c's rate limiter, retrying it while throttled
+func throttled[T any](ctx context.Context, c *throttledClient, operation string, call func() (T, error)) (T, error) {
+	delay := throttleBaseDelay
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		out, err := call()
+		if err == nil || attempt == throttleAttempts || !isThrottlingError(err) {
+			return out, err
+		}
+
+		// Full jitter spreads out the retries of concurrent callers
+		jittered := time.Duration(rand.Int64N(int64(delay) + 1))
+		logging.V(5).Infof("%s was throttled on attempt %d of %d, retrying in %s: %v", operation, attempt, throttleAttempts, jittered, err)
+		if waitErr := waitContext(ctx, jittered); waitErr != nil {
+			return out, err
+		}
+		delay = min(delay*2, maxThrottleDelay)
+	}
+}
+
+func (c *throttledClient) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	return throttled(ctx, c, "DescribeNetworkInterfaces", func() (*ec2.DescribeNetworkInterfacesOutput, error) {
+		return c.client.DescribeNetworkInterfaces(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	return throttled(ctx, c, "DescribeAvailabilityZones", func() (*ec2.DescribeAvailabilityZonesOutput, error) {
+		return c.client.DescribeAvailabilityZones(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return throttled(ctx, c, "DescribeSecurityGroups", func() (*ec2.DescribeSecurityGroupsOutput, error) {
+		return c.client.DescribeSecurityGroups(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) ModifyNetworkInterfaceAttribute(ctx context.Context, params *ec2.ModifyNetworkInterfaceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.ModifyNetworkInterfaceAttributeOutput, error) {
+	return throttled(ctx, c, "ModifyNetworkInterfaceAttribute", func() (*ec2.ModifyNetworkInterfaceAttributeOutput, error) {
+		return c.client.ModifyNetworkInterfaceAttribute(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DetachNetworkInterface(ctx context.Context, params *ec2.DetachNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DetachNetworkInterfaceOutput, error) {
+	return throttled(ctx, c, "DetachNetworkInterface", func() (*ec2.DetachNetworkInterfaceOutput, error) {
+		return c.client.DetachNetworkInterface(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	return throttled(ctx, c, "DeleteNetworkInterface", func() (*ec2.DeleteNetworkInterfaceOutput, error) {
+		return c.client.DeleteNetworkInterface(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return throttled(ctx, c, "DescribeInstances", func() (*ec2.DescribeInstancesOutput, error) {
+		return c.client.DescribeInstances(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return throttled(ctx, c, "DescribeVpcs", func() (*ec2.DescribeVpcsOutput, error) {
+		return c.client.DescribeVpcs(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return throttled(ctx, c, "DescribeSubnets", func() (*ec2.DescribeSubnetsOutput, error) {
+		return c.client.DescribeSubnets(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	return throttled(ctx, c, "DescribeNatGateways", func() (*ec2.DescribeNatGatewaysOutput, error) {
+		return c.client.DescribeNatGateways(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	return throttled(ctx, c, "CreateTags", func() (*ec2.CreateTagsOutput, error) {
+		return c.client.CreateTags(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	return throttled(ctx, c, "DeleteTags", func() (*ec2.DeleteTagsOutput, error) {
+		return c.client.DeleteTags(ctx, params, optFns...)
+	})
+}
+
+func (c *throttledClient) DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
+	return throttled(ctx, c, "DisassociateAddress", func() (*ec2.DisassociateAddressOutput, error) {
+		return c.client.DisassociateAddress(ctx, params, optFns...)
+	})
+}
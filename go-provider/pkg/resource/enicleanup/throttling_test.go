@@ -0,0 +1,99 @@
+package enicleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+)
+
+// throttlingEC2 fails the first throttles describes with throttleErr
+type throttlingEC2 struct {
+	*fakeEC2
+	throttles   int
+	throttleErr error
+
+	mu        sync.Mutex
+	describes int
+}
+
+func (f *throttlingEC2) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	f.mu.Lock()
+	f.describes++
+	throttled := f.describes <= f.throttles
+	f.mu.Unlock()
+	if throttled {
+		return nil, f.throttleErr
+	}
+	return f.fakeEC2.DescribeNetworkInterfaces(ctx, params, optFns...)
+}
+
+// skipThrottleBackoff removes the waits between throttled attempts for the
+// rest of the test
+func skipThrottleBackoff(t *testing.T) {
+	t.Helper()
+	original := throttleBaseDelay
+	throttleBaseDelay = 0
+	t.Cleanup(func() { throttleBaseDelay = original })
+}
+
+func TestThrottledCallsAreRetried(t *testing.T) {
+	requestLimit := &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "Request limit exceeded."}
+
+	tests := []struct {
+		name          string
+		throttles     int
+		throttleErr   error
+		wantDescribes int
+		wantErr       bool
+	}{
+		{name: "succeeds once no longer throttled", throttles: 2, throttleErr: requestLimit, wantDescribes: 3},
+		{name: "wrapped throttling errors are recognized", throttles: 1, throttleErr: fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: "Throttling"}), wantDescribes: 2},
+		{name: "gives up after every attempt is throttled", throttles: throttleAttempts, throttleErr: requestLimit, wantDescribes: throttleAttempts, wantErr: true},
+		{name: "other errors are not retried", throttles: 1, throttleErr: errors.New("UnauthorizedOperation"), wantDescribes: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skipThrottleBackoff(t)
+			fake := &throttlingEC2{fakeEC2: newFakeEC2(availableENI("eni-1")), throttles: tt.throttles, throttleErr: tt.throttleErr}
+
+			result, err := DetectOrphanedENIsWithDetails(context.Background(), []string{"us-east-1"}, DetectOptions{
+				ClientFactory: stubFactory(fake),
+			})
+
+			if fake.describes != tt.wantDescribes {
+				t.Errorf("expected %d describes, got %d", tt.wantDescribes, fake.describes)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && len(result.ENIs) != 1 {
+				t.Errorf("expected eni-1 to be detected, got %+v", result.ENIs)
+			}
+		})
+	}
+}
+
+func TestRateLimiterSpacesCalls(t *testing.T) {
+	limiter := newRateLimiter(50)
+	start := time.Now()
+	for range 5 {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first call goes straight through and the rest are 20ms apart
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected 5 calls at 50 qps to take at least 80ms, took %s", elapsed)
+	}
+
+	if newRateLimiter(0) != nil {
+		t.Error("expected no limiter when qps is zero")
+	}
+}
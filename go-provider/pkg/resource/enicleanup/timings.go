@@ -0,0 +1,66 @@
+package enicleanup
+
+import "time"
+
+// RegionTiming is how long a region took to scan and clean up and how many
+// ENIs it had, for finding the regions that dominate a run's runtime
+type RegionTiming struct {
+	ScanMs    int `pulumi:"scanMs" json:"scanMs"`
+	CleanupMs int `pulumi:"cleanupMs" json:"cleanupMs"`
+	// Detected is the number of candidates found in the region
+	Detected int `pulumi:"detected" json:"detected"`
+	// Cleaned is the number of ENIs cleaned up in the region
+	Cleaned int `pulumi:"cleaned" json:"cleaned"`
+}
+
+// RegionTimings breaks a run down by region, keyed like the results'
+// RegionTimings: by region, or "<account>/<region>" for accountRoles runs
+func RegionTimings(detected DetectResult, result CleanupResult) map[string]RegionTiming {
+	timings := make(map[string]RegionTiming)
+	for key, duration := range detected.RegionTimings {
+		timing := timings[key]
+		timing.ScanMs = int(duration.Milliseconds())
+		timings[key] = timing
+	}
+	for key, duration := range result.RegionTimings {
+		timing := timings[key]
+		timing.CleanupMs = int(duration.Milliseconds())
+		timings[key] = timing
+	}
+	for _, eni := range detected.ENIs {
+		key := timingKey(eni.AccountID, eni.Region)
+		timing := timings[key]
+		timing.Detected++
+		timings[key] = timing
+	}
+	for _, eni := range result.CleanedENIs {
+		key := timingKey(eni.AccountID, eni.Region)
+		timing := timings[key]
+		timing.Cleaned++
+		timings[key] = timing
+	}
+	return timings
+}
+
+// timingKey is the RegionTimings key of a region, scoped to its account if
+// it was scanned via accountRoles
+func timingKey(accountID string, region string) string {
+	if accountID == "" {
+		return region
+	}
+	return accountID + "/" + region
+}
+
+// addAccountTimings adds an account's region timings to timings, keyed by timingKey
+func addAccountTimings(timings map[string]time.Duration, accountID string, accountTimings map[string]time.Duration) map[string]time.Duration {
+	if len(accountTimings) == 0 {
+		return timings
+	}
+	if timings == nil {
+		timings = make(map[string]time.Duration)
+	}
+	for region, duration := range accountTimings {
+		timings[timingKey(accountID, region)] = duration
+	}
+	return timings
+}
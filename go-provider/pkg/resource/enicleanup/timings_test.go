@@ -0,0 +1,55 @@
+package enicleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRegionTimings(t *testing.T) {
+	useFakeEC2(t, newFakeEC2(availableENI("eni-1")))
+
+	regions := []string{"us-east-1", "us-west-2"}
+	detected, err := DetectOrphanedENIsWithDetails(context.Background(), regions, DetectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), detected.ENIs, CleanupOptions{
+		DefaultSecurityGroupId: aws.String("sg-default"),
+	})
+
+	for _, region := range regions {
+		if _, ok := detected.RegionTimings[region]; !ok {
+			t.Errorf("expected a scan timing for %s, got %v", region, detected.RegionTimings)
+		}
+	}
+	if _, ok := result.RegionTimings["us-east-1"]; !ok || len(result.RegionTimings) != 1 {
+		t.Errorf("expected a cleanup timing for the region with candidates only, got %v", result.RegionTimings)
+	}
+
+	// Every region is reported, including those without candidates
+	timings := RegionTimings(detected, result)
+	if len(timings) != 2 {
+		t.Fatalf("expected a timing per region, got %+v", timings)
+	}
+	if got := timings["us-east-1"]; got.Detected != 1 || got.Cleaned != 1 {
+		t.Errorf("expected one detected and cleaned ENI in us-east-1, got %+v", got)
+	}
+	if got := timings["us-west-2"]; got.Detected != 0 || got.Cleaned != 0 {
+		t.Errorf("expected no ENIs counted in us-west-2, got %+v", got)
+	}
+}
+
+func TestCombineAccountResultsScopesTimings(t *testing.T) {
+	detected, cleanup := CombineAccountResults([]AccountResult{
+		{AccountID: "111111111111", Detected: DetectResult{RegionTimings: map[string]time.Duration{"us-east-1": time.Second}}},
+		{AccountID: "222222222222", Detected: DetectResult{RegionTimings: map[string]time.Duration{"us-east-1": 2 * time.Second}}},
+	})
+
+	timings := RegionTimings(detected, cleanup)
+	if timings["111111111111/us-east-1"].ScanMs != 1000 || timings["222222222222/us-east-1"].ScanMs != 2000 {
+		t.Errorf("expected timings keyed by account and region, got %+v", timings)
+	}
+}
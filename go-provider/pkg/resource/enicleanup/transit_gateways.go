@@ -0,0 +1,32 @@
+package enicleanup
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// transitGatewayInterfaceType is the ENI interface type created for transit gateway attachments
+const transitGatewayInterfaceType = "transit_gateway"
+
+// vpnAttachmentPattern matches the transit gateway attachment or VPN resource
+// an ENI was created for, as it appears in the ENI's description
+var vpnAttachmentPattern = regexp.MustCompile(`\b(tgw-attach-[0-9a-f]+|vpn-[0-9a-f]+|cvpn-endpoint-[0-9a-f]+)\b`)
+
+// isTransitGatewayOrVPNENI checks whether an ENI belongs to a transit gateway or
+// VPN attachment. These ENIs can briefly appear available while the attachment
+// is reconfigured, so they are always skipped, regardless of the configured
+// reserved descriptions.
+func isTransitGatewayOrVPNENI(eni types.NetworkInterface) bool {
+	if string(eni.InterfaceType) == transitGatewayInterfaceType {
+		return true
+	}
+	return strings.Contains(strings.ToLower(aws.ToString(eni.RequesterId)), "vpn")
+}
+
+// vpnAttachmentID extracts the attachment or VPN resource ID from an ENI's description, if present
+func vpnAttachmentID(eni types.NetworkInterface) string {
+	return vpnAttachmentPattern.FindString(aws.ToString(eni.Description))
+}
@@ -0,0 +1,42 @@
+package enicleanup
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Interface types used by the EKS security groups for pods feature. The VPC
+// CNI attaches a trunk ENI to the node and associates a branch ENI with the
+// trunk for each pod.
+const (
+	trunkInterfaceType  = "trunk"
+	branchInterfaceType = "branch"
+)
+
+// isTrunkOrBranchENI checks whether an ENI is a CNI-managed trunk or branch interface
+func isTrunkOrBranchENI(eni types.NetworkInterface) bool {
+	interfaceType := string(eni.InterfaceType)
+	return interfaceType == trunkInterfaceType || interfaceType == branchInterfaceType
+}
+
+// cleanupOrder returns the order ENIs of an interface type are cleaned up in.
+// Branch ENIs must be removed before the trunk they are associated with.
+func cleanupOrder(interfaceType string) int {
+	switch interfaceType {
+	case branchInterfaceType:
+		return 0
+	case trunkInterfaceType:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// sortForCleanup orders ENIs so every branch ENI precedes every trunk ENI,
+// keeping the detection order otherwise
+func sortForCleanup(enis []OrphanedENI) {
+	sort.SliceStable(enis, func(i, j int) bool {
+		return cleanupOrder(enis[i].InterfaceType) < cleanupOrder(enis[j].InterfaceType)
+	})
+}
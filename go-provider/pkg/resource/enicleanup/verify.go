@@ -0,0 +1,71 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// verifyDeletionAttempts is how many times deleted ENIs are re-described
+// before any that remain are reported
+const verifyDeletionAttempts = 3
+
+// maxFilterValues is the most values EC2 accepts in a single filter
+const maxFilterValues = 200
+
+// verifyDeletionDelay is the wait between verification attempts, allowing for
+// eventual consistency. Tests shorten it.
+var verifyDeletionDelay = 2 * time.Second
+
+// verifyDeletions re-describes deleted ENIs and returns the IDs that still
+// exist after verifyDeletionAttempts tries
+func verifyDeletions(ctx context.Context, client ENIClient, ids []string) ([]string, error) {
+	remaining := ids
+	for attempt := 1; attempt <= verifyDeletionAttempts; attempt++ {
+		found, err := existingENIs(ctx, client, remaining)
+		if err != nil {
+			return nil, err
+		}
+		remaining = found
+		if len(remaining) == 0 || attempt == verifyDeletionAttempts {
+			break
+		}
+
+		logging.V(9).Infof("%d deleted ENIs still visible, rechecking in %s", len(remaining), verifyDeletionDelay)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(verifyDeletionDelay):
+		}
+	}
+
+	return remaining, nil
+}
+
+// existingENIs returns the IDs among ids that DescribeNetworkInterfaces still
+// returns. It filters by ID rather than passing NetworkInterfaceIds, which
+// fails the whole call when any ID is gone.
+func existingENIs(ctx context.Context, client ENIClient, ids []string) ([]string, error) {
+	var found []string
+	for start := 0; start < len(ids); start += maxFilterValues {
+		end := min(start+maxFilterValues, len(ids))
+		enis, err := findNetworkInterfaces(ctx, client, []types.Filter{
+			{
+				Name:   aws.String("network-interface-id"),
+				Values: ids[start:end],
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing deleted ENIs: %w", err)
+		}
+		for _, eni := range enis {
+			found = append(found, aws.ToString(eni.NetworkInterfaceId))
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,46 @@
+package enicleanup
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestVerifyDeletionReportsENIsThatStillExist(t *testing.T) {
+	original := verifyDeletionDelay
+	verifyDeletionDelay = 0
+	t.Cleanup(func() { verifyDeletionDelay = original })
+
+	// The fake's delete succeeds but leaves the ENI visible
+	fake := newFakeEC2(types.NetworkInterface{NetworkInterfaceId: aws.String("eni-stuck")})
+	useFakeEC2(t, fake)
+
+	enis := []OrphanedENI{
+		{ID: "eni-gone", Region: "us-east-1", VPCID: "vpc-1"},
+		{ID: "eni-stuck", Region: "us-east-1", VPCID: "vpc-1"},
+	}
+	result := CleanupOrphanedENIsWithOptions(context.Background(), enis, CleanupOptions{VerifyDeletion: true})
+
+	if result.SuccessCount != 2 {
+		t.Errorf("expected 2 deleted ENIs, got %d", result.SuccessCount)
+	}
+	if !slices.Equal(result.VerificationFailed, []string{"eni-stuck"}) {
+		t.Errorf("expected eni-stuck to fail verification, got %v", result.VerificationFailed)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("expected one verification error, got %v", result.Errors)
+	}
+}
+
+func TestVerifyDeletionIsOffByDefault(t *testing.T) {
+	fake := newFakeEC2(types.NetworkInterface{NetworkInterfaceId: aws.String("eni-stuck")})
+	useFakeEC2(t, fake)
+
+	result := CleanupOrphanedENIsWithOptions(context.Background(), []OrphanedENI{{ID: "eni-stuck", Region: "us-east-1", VPCID: "vpc-1"}}, CleanupOptions{})
+	if len(result.VerificationFailed) != 0 {
+		t.Errorf("expected no verification without VerifyDeletion, got %v", result.VerificationFailed)
+	}
+}
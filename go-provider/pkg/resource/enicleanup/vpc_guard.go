@@ -0,0 +1,96 @@
+package enicleanup
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// guardVPCDeletionRatio withholds the candidates of any VPC where they make
+// up more than MaxVPCDeletionRatio of the VPC's ENIs, sparing them with
+// SkipReasonVPCDeletionRatio and recording the VPC as aborted. scanned are
+// the ENIs described in the region; when filters narrowed the scan, the VPCs
+// are counted again without them.
+func guardVPCDeletionRatio(ctx context.Context, client ENIClient, region string, scanned []types.NetworkInterface, filtered bool, result RegionResult, options DetectOptions) (RegionResult, error) {
+	if options.MaxVPCDeletionRatio <= 0 || len(result.ENIs) == 0 {
+		return result, nil
+	}
+
+	candidates := make(map[string]int)
+	for _, eni := range result.ENIs {
+		if eni.VPCID != "" && !slices.Contains(options.VPCDeletionRatioOverrides, eni.VPCID) {
+			candidates[eni.VPCID]++
+		}
+	}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	if filtered {
+		vpcIDs := slices.Sorted(maps.Keys(candidates))
+		scanned = nil
+		for start := 0; start < len(vpcIDs); start += maxFilterValues {
+			end := min(start+maxFilterValues, len(vpcIDs))
+			enis, err := findNetworkInterfaces(ctx, client, []types.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: vpcIDs[start:end],
+				},
+			})
+			if err != nil {
+				return RegionResult{}, fmt.Errorf("error counting ENIs per VPC: %w", err)
+			}
+			scanned = append(scanned, enis...)
+		}
+	}
+
+	totals := make(map[string]int)
+	for _, eni := range scanned {
+		totals[aws.ToString(eni.VpcId)]++
+	}
+
+	aborted := make(map[string]AbortedVPC)
+	for vpcID, count := range candidates {
+		total := max(totals[vpcID], count)
+		if float64(count)/float64(total) > options.MaxVPCDeletionRatio {
+			aborted[vpcID] = AbortedVPC{
+				VpcID:      vpcID,
+				Region:     region,
+				Candidates: count,
+				Total:      total,
+			}
+		}
+	}
+	if len(aborted) == 0 {
+		return result, nil
+	}
+
+	var kept []OrphanedENI
+	for _, eni := range result.ENIs {
+		vpc, ok := aborted[eni.VPCID]
+		if !ok {
+			kept = append(kept, eni)
+			continue
+		}
+		result.Skipped = append(result.Skipped, SkippedENI{
+			ID:     eni.ID,
+			Region: eni.Region,
+			Reason: SkipReasonVPCDeletionRatio,
+			Detail: fmt.Sprintf("%d of %d ENIs in %s", vpc.Candidates, vpc.Total, vpc.VpcID),
+		})
+	}
+	result.ENIs = kept
+
+	for _, vpcID := range slices.Sorted(maps.Keys(aborted)) {
+		vpc := aborted[vpcID]
+		logging.V(5).Infof("Aborting cleanup in VPC %s: %d of its %d ENIs are candidates, more than the allowed ratio %v", vpcID, vpc.Candidates, vpc.Total, options.MaxVPCDeletionRatio)
+		result.AbortedVPCs = append(result.AbortedVPCs, vpc)
+	}
+
+	return result, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/organization/eni-cleanup-go/pkg/enicleanup"
+	"github.com/organization/eni-cleanup-go/pkg/multiregion"
 )
 
 // ENICleanupOptions contains options for the ENI cleanup handler
@@ -15,6 +16,32 @@ type ENICleanupOptions struct {
 	Regions        []string
 	DisableCleanup bool
 	LogOutput      *bool
+	// JSONSummary makes the cleanup script finish with a JSON summary of
+	// every ENI it acted on, see enicleanup.ParseCleanupSummary
+	JSONSummary bool
+	// SummaryFile is where the JSON summary is written instead of stdout
+	SummaryFile string
+	// UseNativeEngine cleans up with the go-provider eni-cleanup binary
+	// instead of the bash script, see enicleanup.ScriptOptions
+	UseNativeEngine bool
+	// NativeEnginePath is the eni-cleanup binary for UseNativeEngine
+	NativeEnginePath string
+	// SharedScan routes the cleanup through one command shared by every
+	// resource attached with the same options, so each region is scanned once
+	// per destroy; call enicleanup.RegisterSharedENICleanupHandlers after the
+	// last resource is attached
+	SharedScan bool
+	// Interpreter runs the cleanup script, e.g. ["/bin/sh", "-c"] in a
+	// container without bash, see enicleanup.ScriptOptions
+	Interpreter []string
+	// Shell selects the bash, python or powershell cleanup script, e.g.
+	// enicleanup.ShellPowerShell on Windows; defaults to bash
+	Shell string
+	// SkipReservedDescriptions, IncludeTagKeys and ExcludeTagKeys narrow the
+	// ENIs the cleanup script considers, see enicleanup.ScriptOptions
+	SkipReservedDescriptions []string
+	IncludeTagKeys           []string
+	ExcludeTagKeys           []string
 }
 
 // ENICleanupComponent is a component resource that registers a destroy-time ENI cleanup handler
@@ -35,10 +62,15 @@ func NewENICleanupComponent(ctx *pulumi.Context, name string, args *ENICleanupOp
 		args = &ENICleanupOptions{}
 	}
 
-	// Default to us-east-1 if not specified
-	if len(args.Regions) == 0 {
-		args.Regions = []string{"us-east-1"}
+	// Resolve the regions from config or the AWS environment if not provided
+	args.Regions = multiregion.DefaultRegions(ctx, args.Regions)
+
+	// Expand region sentinels such as "all-enabled"
+	regions, err := multiregion.ExpandRegions(ctx, args.Regions)
+	if err != nil {
+		return nil, err
 	}
+	args.Regions = regions
 
 	// Setup log output
 	logOutput := true
@@ -47,8 +79,22 @@ func NewENICleanupComponent(ctx *pulumi.Context, name string, args *ENICleanupOp
 	}
 
 	// Register the cleanup handler
-	if !args.DisableCleanup {
-		_, err := enicleanup.RegisterENICleanupHandler(ctx, comp, args.Regions, logOutput, false)
+	scriptOptions := enicleanup.ScriptOptions{
+		JSONSummary:      args.JSONSummary,
+		SummaryFile:      args.SummaryFile,
+		UseNativeEngine:  args.UseNativeEngine,
+		NativeEnginePath: args.NativeEnginePath,
+		Interpreter:      args.Interpreter,
+		Shell:            args.Shell,
+
+		SkipReservedDescriptions: args.SkipReservedDescriptions,
+		IncludeTagKeys:           args.IncludeTagKeys,
+		ExcludeTagKeys:           args.ExcludeTagKeys,
+	}
+	if !args.DisableCleanup && args.SharedScan {
+		enicleanup.AttachSharedENICleanupHandler(ctx, comp, args.Regions, logOutput, scriptOptions)
+	} else if !args.DisableCleanup {
+		_, err := enicleanup.RegisterENICleanupHandlerWithOptions(ctx, comp, args.Regions, logOutput, scriptOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -65,10 +111,15 @@ func AttachENICleanupHandler(ctx *pulumi.Context, resource pulumi.Resource, opti
 		options = &ENICleanupOptions{}
 	}
 
-	// Default to us-east-1 if not specified
-	if len(options.Regions) == 0 {
-		options.Regions = []string{"us-east-1"}
+	// Resolve the regions from config or the AWS environment if not provided
+	options.Regions = multiregion.DefaultRegions(ctx, options.Regions)
+
+	// Expand region sentinels such as "all-enabled"
+	regions, err := multiregion.ExpandRegions(ctx, options.Regions)
+	if err != nil {
+		return err
 	}
+	options.Regions = regions
 
 	// Setup log output
 	logOutput := true
@@ -77,8 +128,22 @@ func AttachENICleanupHandler(ctx *pulumi.Context, resource pulumi.Resource, opti
 	}
 
 	// Register the cleanup handler
-	if !options.DisableCleanup {
-		_, err := enicleanup.RegisterENICleanupHandler(ctx, resource, options.Regions, logOutput, false)
+	scriptOptions := enicleanup.ScriptOptions{
+		JSONSummary:      options.JSONSummary,
+		SummaryFile:      options.SummaryFile,
+		UseNativeEngine:  options.UseNativeEngine,
+		NativeEnginePath: options.NativeEnginePath,
+		Interpreter:      options.Interpreter,
+		Shell:            options.Shell,
+
+		SkipReservedDescriptions: options.SkipReservedDescriptions,
+		IncludeTagKeys:           options.IncludeTagKeys,
+		ExcludeTagKeys:           options.ExcludeTagKeys,
+	}
+	if !options.DisableCleanup && options.SharedScan {
+		enicleanup.AttachSharedENICleanupHandler(ctx, resource, options.Regions, logOutput, scriptOptions)
+	} else if !options.DisableCleanup {
+		_, err := enicleanup.RegisterENICleanupHandlerWithOptions(ctx, resource, options.Regions, logOutput, scriptOptions)
 		if err != nil {
 			return err
 		}
@@ -226,4 +291,37 @@ func EksClusterCleanupExample(ctx *pulumi.Context) (*eks.Cluster, error) {
 	}
 
 	return eksCluster, nil
+}
+
+// SharedScanExample demonstrates routing the cleanup of several resources
+// through one command, so the regions are scanned once per destroy
+func SharedScanExample(ctx *pulumi.Context) ([]*ec2.Vpc, error) {
+	var vpcs []*ec2.Vpc
+	for _, name := range []string{"shared-vpc-a", "shared-vpc-b"} {
+		vpc, err := ec2.NewVpc(ctx, name, &ec2.VpcArgs{
+			CidrBlock: pulumi.String("10.0.0.0/16"),
+			Tags: pulumi.StringMap{
+				"Name": pulumi.String(name),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = AttachENICleanupHandler(ctx, vpc, &ENICleanupOptions{
+			Regions:    []string{"us-east-1"},
+			SharedScan: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		vpcs = append(vpcs, vpc)
+	}
+
+	// Create the one command covering both VPCs
+	if _, err := enicleanup.RegisterSharedENICleanupHandlers(ctx); err != nil {
+		return nil, err
+	}
+
+	return vpcs, nil
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ module github.com/organization/eni-cleanup-go
 go 1.20
 
 require (
+	github.com/aws/aws-sdk-go-v2/config v1.27.0
 	github.com/pulumi/pulumi-aws/sdk/v5 v5.0.0
 	github.com/pulumi/pulumi-command/sdk v0.7.0
 	github.com/pulumi/pulumi/sdk/v3 v3.0.0
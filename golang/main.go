@@ -1,11 +1,17 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 
 	"github.com/organization/eni-cleanup-go/pkg/enicleanup"
+	"github.com/organization/eni-cleanup-go/pkg/enidetection"
+	"github.com/organization/eni-cleanup-go/pkg/multiregion"
 )
 
 // ENICleanupOptions contains options for the ENI cleanup handler
@@ -13,11 +19,25 @@ type ENICleanupOptions struct {
 	Regions        []string
 	DisableCleanup bool
 	LogOutput      *bool
+	// PreviewDryRun detects orphaned ENIs in every region when the program
+	// runs, read-only, and reports how many were found as CandidateCount
+	PreviewDryRun bool
+	// SharedScan routes the cleanup through one command shared by every
+	// resource attached with the same options, so each region is scanned once
+	// per destroy; call enicleanup.RegisterSharedENICleanupHandlers after the
+	// last resource is attached
+	SharedScan bool
+	// Interpreter runs the cleanup script, e.g. ["/usr/bin/env", "bash", "-c"];
+	// see enicleanup.ScriptOptions
+	Interpreter []string
 }
 
 // ENICleanupComponent is a component resource that registers a destroy-time ENI cleanup handler
 type ENICleanupComponent struct {
 	pulumi.ComponentResource
+
+	// CandidateCount is the number of orphaned ENIs found by PreviewDryRun
+	CandidateCount pulumi.IntOutput `pulumi:"candidateCount"`
 }
 
 // NewENICleanupComponent creates a new ENI cleanup component
@@ -33,17 +53,15 @@ func NewENICleanupComponent(ctx *pulumi.Context, name string, args *ENICleanupOp
 		args = &ENICleanupOptions{}
 	}
 
-	// Get default regions from config if not provided
-	if len(args.Regions) == 0 {
-		conf := config.New(ctx, "")
-		var regions []string
-		if err := conf.TryObject("regions", &regions); err == nil && len(regions) > 0 {
-			args.Regions = regions
-		} else {
-			// Default to us-east-1 if not specified
-			args.Regions = []string{"us-east-1"}
-		}
+	// Resolve the regions from config or the AWS environment if not provided
+	args.Regions = multiregion.DefaultRegions(ctx, args.Regions)
+
+	// Expand region sentinels such as "all-enabled"
+	regions, err := multiregion.ExpandRegions(ctx, args.Regions)
+	if err != nil {
+		return nil, err
 	}
+	args.Regions = regions
 
 	// Setup log output
 	logOutput := true
@@ -52,17 +70,75 @@ func NewENICleanupComponent(ctx *pulumi.Context, name string, args *ENICleanupOp
 	}
 
 	// Register the cleanup handler
-	if !args.DisableCleanup {
-		_, err := enicleanup.RegisterENICleanupHandler(ctx, comp, args.Regions, logOutput, false)
+	scriptOptions := enicleanup.ScriptOptions{Interpreter: args.Interpreter}
+	if !args.DisableCleanup && args.SharedScan {
+		enicleanup.AttachSharedENICleanupHandler(ctx, comp, args.Regions, logOutput, scriptOptions)
+	} else if !args.DisableCleanup {
+		_, err := enicleanup.RegisterENICleanupHandlerWithOptions(ctx, comp, args.Regions, logOutput, scriptOptions)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	ctx.RegisterResourceOutputs(comp, pulumi.Map{})
+	outputs := pulumi.Map{}
+	if args.PreviewDryRun {
+		count, err := previewOrphanedENIs(ctx, name, comp, args.Regions)
+		if err != nil {
+			return nil, err
+		}
+		comp.CandidateCount = pulumi.Int(count).ToIntOutput()
+		outputs["candidateCount"] = comp.CandidateCount
+	}
+
+	ctx.RegisterResourceOutputs(comp, outputs)
 	return comp, nil
 }
 
+// previewOrphanedENIs counts orphaned ENIs across the regions concurrently,
+// using a provider per region parented to the component
+func previewOrphanedENIs(ctx *pulumi.Context, name string, parent pulumi.Resource, regions []string) (int, error) {
+	providers := make(map[string]*aws.Provider, len(regions))
+	for _, region := range regions {
+		provider, err := aws.NewProvider(ctx, fmt.Sprintf("%s-preview-%s", name, region), &aws.ProviderArgs{
+			Region: pulumi.String(region),
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return 0, err
+		}
+		providers[region] = provider
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		total int
+		errs  []error
+	)
+	for region, provider := range providers {
+		region, provider := region, provider
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			enis, err := enidetection.DetectOrphanedENIs(ctx, []string{region}, provider)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to detect orphaned ENIs in %s: %w", region, err))
+				return
+			}
+			ctx.Log.Info(fmt.Sprintf("Found %d orphaned ENIs in %s", len(enis), region), nil)
+			total += len(enis)
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return total, nil
+}
+
 // AttachENICleanupHandler attaches an ENI cleanup handler to a resource
 func AttachENICleanupHandler(ctx *pulumi.Context, resource pulumi.Resource, options *ENICleanupOptions) error {
 	// Default options
@@ -70,17 +146,15 @@ func AttachENICleanupHandler(ctx *pulumi.Context, resource pulumi.Resource, opti
 		options = &ENICleanupOptions{}
 	}
 
-	// Get default regions from config if not provided
-	if len(options.Regions) == 0 {
-		conf := config.New(ctx, "")
-		var regions []string
-		if err := conf.TryObject("regions", &regions); err == nil && len(regions) > 0 {
-			options.Regions = regions
-		} else {
-			// Default to us-east-1 if not specified
-			options.Regions = []string{"us-east-1"}
-		}
+	// Resolve the regions from config or the AWS environment if not provided
+	options.Regions = multiregion.DefaultRegions(ctx, options.Regions)
+
+	// Expand region sentinels such as "all-enabled"
+	regions, err := multiregion.ExpandRegions(ctx, options.Regions)
+	if err != nil {
+		return err
 	}
+	options.Regions = regions
 
 	// Setup log output
 	logOutput := true
@@ -89,8 +163,11 @@ func AttachENICleanupHandler(ctx *pulumi.Context, resource pulumi.Resource, opti
 	}
 
 	// Register the cleanup handler
-	if !options.DisableCleanup {
-		_, err := enicleanup.RegisterENICleanupHandler(ctx, resource, options.Regions, logOutput, false)
+	scriptOptions := enicleanup.ScriptOptions{Interpreter: options.Interpreter}
+	if !options.DisableCleanup && options.SharedScan {
+		enicleanup.AttachSharedENICleanupHandler(ctx, resource, options.Regions, logOutput, scriptOptions)
+	} else if !options.DisableCleanup {
+		_, err := enicleanup.RegisterENICleanupHandlerWithOptions(ctx, resource, options.Regions, logOutput, scriptOptions)
 		if err != nil {
 			return err
 		}
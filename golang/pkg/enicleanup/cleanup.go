@@ -3,6 +3,9 @@ package enicleanup
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws"
@@ -10,6 +13,131 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// CleanupSummaryMarker prefixes the JSON summary line the cleanup script
+// prints to stdout when JSONSummary is set without a SummaryFile
+const CleanupSummaryMarker = "ENI_CLEANUP_SUMMARY: "
+
+// ScriptOptions configures the generated cleanup script
+type ScriptOptions struct {
+	// DryRun logs what would be done without changing anything
+	DryRun bool
+	// JSONSummary makes the script finish with a CleanupSummary of every ENI
+	// it processed, written to SummaryFile or printed after CleanupSummaryMarker
+	JSONSummary bool
+	// SummaryFile is where the JSON summary is written; stdout when empty
+	SummaryFile string
+	// UseNativeEngine runs the go-provider eni-cleanup binary, which uses
+	// the AWS SDK directly, instead of the bash script, so neither bash nor
+	// jq is needed. The binary isn't bundled: it must be installed where the
+	// destroy runs, at NativeEnginePath or on the PATH. JSONSummary and
+	// SummaryFile don't apply.
+	UseNativeEngine bool
+	// NativeEnginePath is the eni-cleanup binary for UseNativeEngine;
+	// defaults to DefaultNativeEnginePath, looked up on the PATH
+	NativeEnginePath string
+	// Interpreter runs the cleanup command, which is passed as its last
+	// argument, e.g. ["/usr/bin/env", "bash", "-c"] or ["/bin/sh", "-c"].
+	// Defaults to the Shell's default interpreter. For the bash script it
+	// must be a Bourne-style shell; see validateInterpreter.
+	Interpreter []string
+	// Shell selects the cleanup script: ShellBash, the default, ShellPython
+	// or ShellPowerShell. JSONSummary and SummaryFile only apply to bash.
+	Shell string
+	// SkipReservedDescriptions are description substrings whose ENIs are
+	// skipped, in addition to DefaultReservedDescriptions
+	SkipReservedDescriptions []string
+	// IncludeTagKeys only considers ENIs with at least one of these tag keys
+	IncludeTagKeys []string
+	// ExcludeTagKeys skips ENIs with any of these tag keys
+	ExcludeTagKeys []string
+}
+
+// DefaultReservedDescriptions are the description substrings of ENIs the
+// cleanup scripts always skip, since AWS services manage them
+var DefaultReservedDescriptions = []string{"ELB", "Amazon EKS", "AWS-mgmt"}
+
+// reservedDescriptions returns the description substrings whose ENIs are skipped
+func (o ScriptOptions) reservedDescriptions() []string {
+	return append(slices.Clone(DefaultReservedDescriptions), o.SkipReservedDescriptions...)
+}
+
+// DefaultNativeEnginePath is the eni-cleanup binary UseNativeEngine runs by default
+const DefaultNativeEnginePath = "eni-cleanup"
+
+// Shells the cleanup script can be generated for, see ScriptOptions.Shell
+const (
+	ShellBash       = "bash"
+	ShellPython     = "python"
+	ShellPowerShell = "powershell"
+)
+
+// DefaultInterpreter runs the cleanup command when ScriptOptions.Interpreter is empty
+var DefaultInterpreter = []string{"/bin/bash", "-c"}
+
+// DefaultPythonInterpreter runs the ShellPython script when ScriptOptions.Interpreter is empty
+var DefaultPythonInterpreter = []string{"python3", "-c"}
+
+// DefaultPowerShellInterpreter runs the ShellPowerShell script when
+// ScriptOptions.Interpreter is empty. pwsh runs on Windows, Linux and macOS.
+var DefaultPowerShellInterpreter = []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command"}
+
+// posixShells are the shells, besides bash, that the cleanup script is kept
+// compatible with. They lack bash extensions, so the script sticks to POSIX sh.
+var posixShells = map[string]bool{
+	"sh":   true,
+	"ash":  true,
+	"dash": true,
+	"ksh":  true,
+	"mksh": true,
+	"zsh":  true,
+}
+
+// bashisms match the bash-only constructs POSIX shells reject or misread
+var bashisms = []struct {
+	pattern     *regexp.Regexp
+	description string
+}{
+	{regexp.MustCompile(`\[\[`), "[[ ]] tests"},
+	{regexp.MustCompile(`\[ [^]]*==`), "== in [ ] tests"},
+	{regexp.MustCompile(`(?m)^\s*function\s`), "the function keyword"},
+	{regexp.MustCompile(`<<<`), "here-strings"},
+	{regexp.MustCompile(`&>`), "&> redirection"},
+	{regexp.MustCompile(`\$\{[A-Za-z_][A-Za-z0-9_]*(/|:[0-9-])`), "${var/...} and ${var:offset} expansions"},
+	{regexp.MustCompile(`(?m)^\s*source\s`), "source"},
+}
+
+// CleanupSummary is the JSON summary emitted by the cleanup script
+type CleanupSummary struct {
+	ENIs []ScriptENIResult `json:"enis"`
+}
+
+// ScriptENIResult records what the cleanup script did with one ENI
+type ScriptENIResult struct {
+	ID               string `json:"id"`
+	Region           string `json:"region"`
+	VpcID            string `json:"vpcId"`
+	SubnetID         string `json:"subnetId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	// Action is "deleted", "skipped", "tagged-for-manual-cleanup" or, in a dry run, "would-delete"
+	Action string `json:"action"`
+}
+
+// ParseCleanupSummary extracts the JSON summary from the cleanup script's stdout
+func ParseCleanupSummary(stdout string) (*CleanupSummary, error) {
+	for _, line := range strings.Split(stdout, "\n") {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), CleanupSummaryMarker)
+		if !ok {
+			continue
+		}
+		var summary CleanupSummary
+		if err := json.Unmarshal([]byte(data), &summary); err != nil {
+			return nil, fmt.Errorf("failed to parse ENI cleanup summary: %w", err)
+		}
+		return &summary, nil
+	}
+	return nil, fmt.Errorf("no ENI cleanup summary in output")
+}
+
 // RegisterENICleanupHandler registers an ENI cleanup handler that runs during resource destruction
 // Uses the pulumi-command provider to execute AWS CLI commands that identify and clean up orphaned ENIs
 func RegisterENICleanupHandler(
@@ -19,20 +147,22 @@ func RegisterENICleanupHandler(
 	logOutput bool,
 	dryRun bool,
 ) (*local.Command, error) {
-	// Create a script that will run as part of resource destruction
-	cleanupScript := generateCleanupScript(regions, dryRun)
+	return RegisterENICleanupHandlerWithOptions(ctx, resource, regions, logOutput, ScriptOptions{DryRun: dryRun})
+}
 
+// RegisterENICleanupHandlerWithOptions registers an ENI cleanup handler like
+// RegisterENICleanupHandler, with the script configured by options
+func RegisterENICleanupHandlerWithOptions(
+	ctx *pulumi.Context,
+	resource pulumi.Resource,
+	regions []string,
+	logOutput bool,
+	options ScriptOptions,
+) (*local.Command, error) {
 	// Generate a unique name for this cleanup handler
 	resourceName := resource.URN().Name()
 	cleanupName := fmt.Sprintf("%s-eni-cleanup", resourceName)
 
-	// Create command arguments
-	commandArgs := &local.CommandArgs{
-		Create:      pulumi.String("echo 'ENI cleanup handler attached'"),
-		Delete:      pulumi.String(cleanupScript),
-		Interpreter: pulumi.ToStringArray([]string{"/bin/bash", "-c"}),
-	}
-
 	// Create command options
 	commandOpts := []pulumi.ResourceOption{
 		pulumi.Parent(resource),
@@ -43,6 +173,11 @@ func RegisterENICleanupHandler(
 		pulumi.Trigger("triggers", resource.URN()),
 	}
 
+	commandArgs, err := cleanupCommandArgs(regions, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a command resource that runs during destruction
 	cleanupCommand, err := local.NewCommand(ctx, cleanupName, commandArgs, commandOpts...)
 	if err != nil {
@@ -51,23 +186,179 @@ func RegisterENICleanupHandler(
 
 	// If we want to see the output, we can export it
 	if logOutput {
-		cleanupCommand.Stdout.ApplyT(func(stdout string) string {
-			if stdout == "" {
-				return "No output from ENI cleanup"
-			}
-			return stdout
-		}).(pulumi.StringOutput).ApplyT(func(output string) error {
-			outputName := fmt.Sprintf("%s_eni_cleanup", strings.ReplaceAll(strings.ReplaceAll(resource.URN().String(), "::", "_"), "$", "_"))
-			ctx.Export(outputName, pulumi.String(output))
-			return nil
-		})
+		exportCleanupOutput(ctx, cleanupCommand, resource.URN().String())
 	}
 
 	return cleanupCommand, nil
 }
 
-// generateCleanupScript generates a bash script to cleanup orphaned ENIs
-func generateCleanupScript(regions []string, dryRun bool) string {
+// cleanupCommandArgs creates the command arguments, running a script or the
+// native engine during resource destruction
+func cleanupCommandArgs(regions []string, options ScriptOptions) (*local.CommandArgs, error) {
+	if options.UseNativeEngine {
+		args := &local.CommandArgs{
+			Create: pulumi.String("echo 'ENI cleanup handler attached'"),
+			Delete: pulumi.String(generateNativeCommand(regions, options)),
+		}
+		// The native command is only quoted, so any shell runs it
+		if len(options.Interpreter) > 0 {
+			args.Interpreter = pulumi.ToStringArray(options.Interpreter)
+		}
+		return args, nil
+	}
+
+	script, interpreter, err := cleanupScript(regions, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &local.CommandArgs{
+		Create:      pulumi.String("echo 'ENI cleanup handler attached'"),
+		Delete:      pulumi.String(script),
+		Interpreter: pulumi.ToStringArray(interpreter),
+	}, nil
+}
+
+// cleanupScript generates the cleanup script for the selected shell and
+// returns it with the interpreter that runs it
+func cleanupScript(regions []string, options ScriptOptions) (string, []string, error) {
+	interpreter := options.Interpreter
+	switch options.Shell {
+	case "", ShellBash:
+		if len(interpreter) == 0 {
+			interpreter = DefaultInterpreter
+		}
+		script := generateCleanupScript(regions, options)
+		if err := validateInterpreter(interpreter, script); err != nil {
+			return "", nil, err
+		}
+		return script, interpreter, nil
+	case ShellPython:
+		if len(interpreter) == 0 {
+			interpreter = DefaultPythonInterpreter
+		}
+		return generatePythonCleanupScript(regions, options), interpreter, nil
+	case ShellPowerShell:
+		if len(interpreter) == 0 {
+			interpreter = DefaultPowerShellInterpreter
+		}
+		return generatePowerShellCleanupScript(regions, options), interpreter, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported shell %q: must be %s, %s or %s", options.Shell, ShellBash, ShellPython, ShellPowerShell)
+	}
+}
+
+// interpreterShell returns the name of the shell an interpreter runs, looking
+// through /usr/bin/env and busybox, e.g. "bash" for ["/usr/bin/env", "bash", "-c"]
+func interpreterShell(interpreter []string) string {
+	for i, arg := range interpreter {
+		name := filepath.Base(arg)
+		if i > 0 && strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if name == "env" || name == "busybox" {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// validateInterpreter checks that the cleanup script runs under the
+// interpreter's shell. bash runs it as is; the POSIX shells in posixShells
+// only if it contains no bashisms. Other interpreters, such as python or
+// PowerShell, can't run it at all; they need ScriptOptions.Shell instead.
+func validateInterpreter(interpreter []string, script string) error {
+	shell := interpreterShell(interpreter)
+	switch {
+	case shell == "bash":
+		return nil
+	case posixShells[shell]:
+		for _, bashism := range bashisms {
+			if bashism.pattern.MatchString(script) {
+				return fmt.Errorf("the cleanup script uses %s, which %s doesn't support; use bash as the interpreter", bashism.description, shell)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported interpreter %q: the cleanup script needs bash or a POSIX shell such as sh, ash, dash, ksh or zsh; set Shell to %s or %s for the other scripts", strings.Join(interpreter, " "), ShellPython, ShellPowerShell)
+	}
+}
+
+// exportCleanupOutput exports the cleanup command's output under a name derived from key
+func exportCleanupOutput(ctx *pulumi.Context, cleanupCommand *local.Command, key string) {
+	cleanupCommand.Stdout.ApplyT(func(stdout string) string {
+		if stdout == "" {
+			return "No output from ENI cleanup"
+		}
+		return stdout
+	}).(pulumi.StringOutput).ApplyT(func(output string) error {
+		outputName := fmt.Sprintf("%s_eni_cleanup", strings.ReplaceAll(strings.ReplaceAll(key, "::", "_"), "$", "_"))
+		ctx.Export(outputName, pulumi.String(output))
+		return nil
+	})
+}
+
+// generateNativeCommand generates the command line running the native
+// eni-cleanup engine, quoting each argument for the default shell
+func generateNativeCommand(regions []string, options ScriptOptions) string {
+	path := options.NativeEnginePath
+	if path == "" {
+		path = DefaultNativeEnginePath
+	}
+
+	args := []string{path, "-regions", strings.Join(regions, ",")}
+	if options.DryRun {
+		args = append(args, "-dry-run")
+	}
+	if len(options.SkipReservedDescriptions) > 0 {
+		args = append(args, "-skip-reserved-descriptions", strings.Join(options.SkipReservedDescriptions, ","))
+	}
+	if len(options.IncludeTagKeys) > 0 {
+		args = append(args, "-include-tag-keys", strings.Join(options.IncludeTagKeys, ","))
+	}
+	if len(options.ExcludeTagKeys) > 0 {
+		args = append(args, "-exclude-tag-keys", strings.Join(options.ExcludeTagKeys, ","))
+	}
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes a value as a single shell word
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// jsonList encodes values as a JSON array, which is also a Python list
+// literal, encoding nil as an empty array
+func jsonList(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// descriptionCasePattern builds the case pattern matching descriptions that
+// contain any of the substrings. Each is double-quoted so glob characters in
+// it match literally.
+func descriptionCasePattern(substrings []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	patterns := make([]string, len(substrings))
+	for i, substring := range substrings {
+		patterns[i] = `*"` + escaper.Replace(substring) + `"*`
+	}
+	return strings.Join(patterns, "|")
+}
+
+// generateCleanupScript generates a shell script to cleanup orphaned ENIs. The
+// script must stay POSIX sh, as checked by validateInterpreter, so it runs
+// under every shell in posixShells as well as bash.
+func generateCleanupScript(regions []string, options ScriptOptions) string {
 	regionsStr := ""
 	for i, region := range regions {
 		if i > 0 {
@@ -77,17 +368,37 @@ func generateCleanupScript(regions []string, dryRun bool) string {
 	}
 
 	dryRunFlag := ""
-	if dryRun {
+	if options.DryRun {
 		dryRunFlag = "--dry-run"
 	}
 
+	jsonSummary := ""
+	if options.JSONSummary {
+		jsonSummary = "true"
+	}
+
 	return fmt.Sprintf(`
-#!/bin/bash
+#!/bin/sh
 set -e
 
-echo "Starting ENI cleanup for regions: %s"
+JSON_SUMMARY="%[5]s"
+SUMMARY_FILE=%[6]s
+INCLUDE_TAG_KEYS=%[9]s
+EXCLUDE_TAG_KEYS=%[10]s
+SUMMARY_RECORDS=$(mktemp)
+trap 'rm -f "$SUMMARY_RECORDS"' EXIT
 
-for region in %s; do
+# Record what was done with the current ENI for the JSON summary
+record_action() {
+    [ "$JSON_SUMMARY" = "true" ] || return 0
+    jq -cn --arg id "$ENI_ID" --arg region "$region" --arg vpc "$VPC_ID" \
+        --arg subnet "$SUBNET_ID" --arg az "$AZ" --arg action "$1" \
+        '{id: $id, region: $region, vpcId: $vpc, subnetId: $subnet, availabilityZone: $az, action: $action}' >> "$SUMMARY_RECORDS"
+}
+
+echo "Starting ENI cleanup for regions: %[1]s"
+
+for region in %[2]s; do
     echo "Scanning region: $region for orphaned ENIs"
     
     # Find all ENIs in 'available' state
@@ -95,7 +406,7 @@ for region in %s; do
     AVAILABLE_ENIS=$(aws ec2 describe-network-interfaces \
         --region $region \
         --filters "Name=status,Values=available" \
-        --query 'NetworkInterfaces[*].{ID:NetworkInterfaceId, VPC:VpcId, Description:Description}' \
+        --query 'NetworkInterfaces[*].{ID:NetworkInterfaceId, VPC:VpcId, Subnet:SubnetId, AZ:AvailabilityZone, Description:Description, TagKeys:TagSet[].Key}' \
         --output json)
     
     # Count them
@@ -112,13 +423,34 @@ for region in %s; do
     echo $AVAILABLE_ENIS | jq -c '.[]' | while read -r eni; do
         ENI_ID=$(echo $eni | jq -r '.ID')
         VPC_ID=$(echo $eni | jq -r '.VPC')
+        SUBNET_ID=$(echo $eni | jq -r '.Subnet')
+        AZ=$(echo $eni | jq -r '.AZ')
         DESCRIPTION=$(echo $eni | jq -r '.Description')
         
         echo "Processing ENI: $ENI_ID in VPC: $VPC_ID"
         
         # Skip ENIs with reserved descriptions that should not be deleted
-        if [[ "$DESCRIPTION" == *"ELB"* || "$DESCRIPTION" == *"Amazon EKS"* || "$DESCRIPTION" == *"AWS-mgmt"* ]]; then
-            echo "Skipping ENI $ENI_ID with reserved description: $DESCRIPTION"
+        case "$DESCRIPTION" in
+            %[8]s)
+                echo "Skipping ENI $ENI_ID with reserved description: $DESCRIPTION"
+                record_action skipped
+                continue
+                ;;
+        esac
+        
+        # Only consider ENIs with one of the include tag keys, if any are set
+        if [ "$INCLUDE_TAG_KEYS" != "[]" ] && ! echo "$eni" | jq -e --argjson keys "$INCLUDE_TAG_KEYS" \
+            'any((.TagKeys // [])[]; . as $key | $keys | index($key))' > /dev/null; then
+            echo "Skipping ENI $ENI_ID without any of the include tag keys"
+            record_action skipped
+            continue
+        fi
+        
+        # Skip ENIs with any of the exclude tag keys
+        if echo "$eni" | jq -e --argjson keys "$EXCLUDE_TAG_KEYS" \
+            'any((.TagKeys // [])[]; . as $key | $keys | index($key))' > /dev/null; then
+            echo "Skipping ENI $ENI_ID with an exclude tag key"
+            record_action skipped
             continue
         fi
         
@@ -136,7 +468,7 @@ for region in %s; do
             ATTACH_ID=$(echo $ENI_DETAILS | jq -r '.Attachment.AttachmentId // "none"')
             if [ "$ATTACH_ID" != "none" ]; then
                 echo "Detaching ENI $ENI_ID (attachment: $ATTACH_ID)"
-                if [ "%s" == "" ]; then
+                if [ "%[3]s" = "" ]; then
                     aws ec2 detach-network-interface \
                         --region $region \
                         --attachment-id $ATTACH_ID \
@@ -153,7 +485,7 @@ for region in %s; do
         
         # Delete the ENI
         echo "Deleting ENI $ENI_ID"
-        if [ "%s" == "" ]; then
+        if [ "%[4]s" = "" ]; then
             # Try to delete the ENI
             if ! aws ec2 delete-network-interface \
                 --region $region \
@@ -176,49 +508,64 @@ for region in %s; do
                         --region $region \
                         --network-interface-id $ENI_ID 2>/dev/null; then
                         echo "Successfully deleted ENI $ENI_ID after security group disassociation"
+                        record_action deleted
                     else
                         echo "Deletion still failed after removing security groups"
                         
                         # Fallback 2: Tag for manual cleanup
                         echo "Fallback 2: Tagging ENI $ENI_ID for manual cleanup"
-                        TIMESTAMP=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
+                        TIMESTAMP=$(date -u +"%%Y-%%m-%%dT%%H:%%M:%%SZ")
                         aws ec2 create-tags \
                             --region $region \
                             --resources $ENI_ID \
                             --tags "Key=NeedsManualCleanup,Value=true" "Key=AttemptedCleanupTime,Value=$TIMESTAMP"
                         echo "Tagged ENI $ENI_ID for manual cleanup"
+                        record_action tagged-for-manual-cleanup
                     fi
                 else
                     echo "Failed to modify security groups for ENI $ENI_ID"
                     
                     # Fallback 2: Tag for manual cleanup
                     echo "Fallback 2: Tagging ENI $ENI_ID for manual cleanup"
-                    TIMESTAMP=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
+                    TIMESTAMP=$(date -u +"%%Y-%%m-%%dT%%H:%%M:%%SZ")
                     aws ec2 create-tags \
                         --region $region \
                         --resources $ENI_ID \
                         --tags "Key=NeedsManualCleanup,Value=true" "Key=AttemptedCleanupTime,Value=$TIMESTAMP"
                     echo "Tagged ENI $ENI_ID for manual cleanup"
+                    record_action tagged-for-manual-cleanup
                 fi
             else
                 echo "Successfully deleted ENI $ENI_ID in $region"
+                record_action deleted
             fi
         else
             echo "[DRY RUN] Would delete ENI $ENI_ID in $region"
+            record_action would-delete
         fi
     done
 done
 
 echo "ENI cleanup completed"
-`, strings.Join(regions, ", "), regionsStr, dryRunFlag, dryRunFlag)
+
+if [ "$JSON_SUMMARY" = "true" ]; then
+    if [ -n "$SUMMARY_FILE" ]; then
+        jq -s '{enis: .}' "$SUMMARY_RECORDS" > "$SUMMARY_FILE"
+        echo "Wrote ENI cleanup summary to $SUMMARY_FILE"
+    else
+        echo "%[7]s$(jq -cs '{enis: .}' "$SUMMARY_RECORDS")"
+    fi
+fi
+`, strings.Join(regions, ", "), regionsStr, dryRunFlag, dryRunFlag, jsonSummary, shellQuote(options.SummaryFile), CleanupSummaryMarker,
+		descriptionCasePattern(options.reservedDescriptions()), shellQuote(jsonList(options.IncludeTagKeys)), shellQuote(jsonList(options.ExcludeTagKeys)))
 }
 
 // generatePythonCleanupScript generates a Python script to cleanup orphaned ENIs
 // Used as an alternative when bash might not be available or cross-platform execution is needed
-func generatePythonCleanupScript(regions []string, dryRun bool) string {
+func generatePythonCleanupScript(regions []string, options ScriptOptions) string {
 	regionsJSON, _ := json.Marshal(regions)
 	dryRunStr := "False"
-	if dryRun {
+	if options.DryRun {
 		dryRunStr = "True"
 	}
 
@@ -229,6 +576,9 @@ import time
 
 regions = %s
 dry_run = %s
+reserved_descriptions = %s
+include_tag_keys = %s
+exclude_tag_keys = %s
 
 print(f"Starting ENI cleanup for regions: {', '.join(regions)}")
 
@@ -256,14 +606,25 @@ for region in regions:
         eni_id = eni['NetworkInterfaceId']
         vpc_id = eni.get('VpcId', 'unknown')
         description = eni.get('Description', '')
+        tag_keys = {tag['Key'] for tag in eni.get('TagSet', [])}
         
         print(f"Processing ENI: {eni_id} in VPC: {vpc_id}")
         
         # Skip ENIs with reserved descriptions that should not be deleted
-        if any(reserved in description for reserved in ['ELB', 'Amazon EKS', 'AWS-mgmt']):
+        if any(reserved in description for reserved in reserved_descriptions):
             print(f"Skipping ENI {eni_id} with reserved description: {description}")
             continue
         
+        # Only consider ENIs with one of the include tag keys, if any are set
+        if include_tag_keys and not tag_keys.intersection(include_tag_keys):
+            print(f"Skipping ENI {eni_id} without any of the include tag keys")
+            continue
+        
+        # Skip ENIs with any of the exclude tag keys
+        if tag_keys.intersection(exclude_tag_keys):
+            print(f"Skipping ENI {eni_id} with an exclude tag key")
+            continue
+        
         # Check if it has any attachments
         if 'Attachment' in eni and eni['Attachment']:
             attachment_id = eni['Attachment'].get('AttachmentId')
@@ -320,7 +681,7 @@ for region in regions:
                         
                         # Fallback 2: Tag for manual cleanup
                         print(f"Fallback 2: Tagging ENI {eni_id} for manual cleanup")
-                        timestamp = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+                        timestamp = time.strftime("%%Y-%%m-%%dT%%H:%%M:%%SZ", time.gmtime())
                         ec2_client.create_tags(
                             Resources=[eni_id],
                             Tags=[
@@ -336,7 +697,7 @@ for region in regions:
                     # Still try to tag for manual cleanup as last resort
                     try:
                         print(f"Tagging ENI {eni_id} for manual cleanup as last resort")
-                        timestamp = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+                        timestamp = time.strftime("%%Y-%%m-%%dT%%H:%%M:%%SZ", time.gmtime())
                         ec2_client.create_tags(
                             Resources=[eni_id],
                             Tags=[
@@ -352,5 +713,5 @@ for region in regions:
             print(f"[DRY RUN] Would delete ENI {eni_id} in {region}")
 
 print("ENI cleanup completed")
-`, regionsJSON, dryRunStr)
+`, regionsJSON, dryRunStr, jsonList(options.reservedDescriptions()), jsonList(options.IncludeTagKeys), jsonList(options.ExcludeTagKeys))
 }
\ No newline at end of file
@@ -0,0 +1,160 @@
+package enicleanup
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestInterpreterShell tests finding the shell an interpreter runs
+func TestInterpreterShell(t *testing.T) {
+	tests := []struct {
+		interpreter []string
+		want        string
+	}{
+		{[]string{"/bin/bash", "-c"}, "bash"},
+		{[]string{"/usr/bin/env", "bash", "-c"}, "bash"},
+		{[]string{"/usr/bin/env", "-S", "zsh", "-c"}, "zsh"},
+		{[]string{"/bin/busybox", "ash", "-c"}, "ash"},
+		{[]string{"sh", "-c"}, "sh"},
+	}
+	for _, tt := range tests {
+		if got := interpreterShell(tt.interpreter); got != tt.want {
+			t.Errorf("interpreterShell(%q) = %q, want %q", tt.interpreter, got, tt.want)
+		}
+	}
+}
+
+// TestCleanupScriptRunsUnderPOSIXShells tests that the generated script has no bashisms
+func TestCleanupScriptRunsUnderPOSIXShells(t *testing.T) {
+	script := generateCleanupScript([]string{"us-east-1", "eu-west-1"}, ScriptOptions{DryRun: true, JSONSummary: true})
+	for _, interpreter := range [][]string{DefaultInterpreter, {"/bin/sh", "-c"}, {"/bin/busybox", "ash", "-c"}, {"/usr/bin/env", "zsh", "-c"}} {
+		if err := validateInterpreter(interpreter, script); err != nil {
+			t.Errorf("expected the cleanup script to run under %q: %v", interpreter, err)
+		}
+	}
+}
+
+// TestValidateInterpreterRejectsBashisms tests that POSIX shells reject bash-only constructs
+func TestValidateInterpreterRejectsBashisms(t *testing.T) {
+	script := `if [[ "$DESCRIPTION" == *"ELB"* ]]; then echo skip; fi`
+	if err := validateInterpreter([]string{"/bin/bash", "-c"}, script); err != nil {
+		t.Errorf("expected bash to accept bashisms: %v", err)
+	}
+	if err := validateInterpreter([]string{"/bin/sh", "-c"}, script); err == nil || !strings.Contains(err.Error(), "[[ ]]") {
+		t.Errorf("expected sh to reject [[ ]], got %v", err)
+	}
+	if err := validateInterpreter([]string{"pwsh", "-Command"}, "echo hi"); err == nil {
+		t.Errorf("expected an unsupported interpreter to be rejected")
+	}
+}
+
+// TestCleanupScriptsHonorSkipOptions tests that both scripts filter on the
+// configured reserved descriptions and tag keys
+func TestCleanupScriptsHonorSkipOptions(t *testing.T) {
+	options := ScriptOptions{
+		SkipReservedDescriptions: []string{"Custom Appliance"},
+		IncludeTagKeys:           []string{"cleanup-owner"},
+		ExcludeTagKeys:           []string{"do-not-delete"},
+	}
+
+	script := generateCleanupScript([]string{"us-east-1"}, options)
+	for _, want := range []string{`*"ELB"*|*"Amazon EKS"*|*"AWS-mgmt"*|*"Custom Appliance"*)`, `INCLUDE_TAG_KEYS='["cleanup-owner"]'`, `EXCLUDE_TAG_KEYS='["do-not-delete"]'`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected the cleanup script to contain %s", want)
+		}
+	}
+	if err := validateInterpreter([]string{"/bin/sh", "-c"}, script); err != nil {
+		t.Errorf("expected the cleanup script to stay POSIX sh: %v", err)
+	}
+
+	python := generatePythonCleanupScript([]string{"us-east-1"}, options)
+	for _, want := range []string{`reserved_descriptions = ["ELB","Amazon EKS","AWS-mgmt","Custom Appliance"]`, `include_tag_keys = ["cleanup-owner"]`, `exclude_tag_keys = ["do-not-delete"]`} {
+		if !strings.Contains(python, want) {
+			t.Errorf("expected the Python cleanup script to contain %s", want)
+		}
+	}
+}
+
+// TestDescriptionCasePatternQuotesSubstrings tests that shell metacharacters
+// in reserved descriptions match literally
+func TestDescriptionCasePatternQuotesSubstrings(t *testing.T) {
+	got := descriptionCasePattern([]string{`a*b`, `say "hi" $HOME`})
+	want := `*"a*b"*|*"say \"hi\" \$HOME"*`
+	if got != want {
+		t.Errorf("descriptionCasePattern = %s, want %s", got, want)
+	}
+}
+
+// TestPowerShellCleanupScript tests that the PowerShell script loops over the
+// regions with the AWS Tools for PowerShell cmdlets
+func TestPowerShellCleanupScript(t *testing.T) {
+	script := generatePowerShellCleanupScript([]string{"us-east-1", "eu-west-1"}, ScriptOptions{DryRun: true, ExcludeTagKeys: []string{"owner's-key"}})
+	for _, want := range []string{
+		"$regions = @('us-east-1', 'eu-west-1')",
+		"$dryRun = $true",
+		"$excludeTagKeys = @('owner''s-key')",
+		"foreach ($region in $regions) {",
+		"Get-EC2NetworkInterface",
+		"Dismount-EC2NetworkInterface",
+		"Remove-EC2NetworkInterface",
+		"Edit-EC2NetworkInterfaceAttribute",
+		"New-EC2Tag",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected the PowerShell cleanup script to contain %s", want)
+		}
+	}
+}
+
+// TestCleanupScriptShellSelection tests that Shell picks the script and its
+// default interpreter, keeping bash by default
+func TestCleanupScriptShellSelection(t *testing.T) {
+	tests := []struct {
+		shell       string
+		wantScript  string
+		interpreter []string
+	}{
+		{"", "#!/bin/sh", DefaultInterpreter},
+		{ShellBash, "#!/bin/sh", DefaultInterpreter},
+		{ShellPython, "import boto3", DefaultPythonInterpreter},
+		{ShellPowerShell, "Import-Module AWS.Tools.EC2", DefaultPowerShellInterpreter},
+	}
+	for _, tt := range tests {
+		script, interpreter, err := cleanupScript([]string{"us-east-1"}, ScriptOptions{Shell: tt.shell})
+		if err != nil {
+			t.Fatalf("shell %q: %v", tt.shell, err)
+		}
+		if !strings.Contains(script, tt.wantScript) {
+			t.Errorf("expected the %q script to contain %s", tt.shell, tt.wantScript)
+		}
+		if strings.Join(interpreter, " ") != strings.Join(tt.interpreter, " ") {
+			t.Errorf("shell %q: interpreter = %q, want %q", tt.shell, interpreter, tt.interpreter)
+		}
+	}
+
+	if _, _, err := cleanupScript([]string{"us-east-1"}, ScriptOptions{Shell: "fish"}); err == nil {
+		t.Errorf("expected an unsupported shell to be rejected")
+	}
+}
+
+// TestCleanupScriptQuotesSummaryFile tests that the summary path is taken literally by the shell
+func TestCleanupScriptQuotesSummaryFile(t *testing.T) {
+	script := generateCleanupScript([]string{"us-east-1"}, ScriptOptions{JSONSummary: true, SummaryFile: "/tmp/$HOME/it's `id`.json"})
+	want := `SUMMARY_FILE='/tmp/$HOME/it'\''s ` + "`id`" + `.json'`
+	if !strings.Contains(script, want) {
+		t.Errorf("expected the cleanup script to contain %s", want)
+	}
+}
+
+// TestNativeCommandForwardsSkipOptions tests that the native engine gets the skip options as flags
+func TestNativeCommandForwardsSkipOptions(t *testing.T) {
+	command := generateNativeCommand([]string{"us-east-1"}, ScriptOptions{
+		SkipReservedDescriptions: []string{"Custom Appliance"},
+		IncludeTagKeys:           []string{"cleanup-owner"},
+		ExcludeTagKeys:           []string{"do-not-delete", "keep"},
+	})
+	want := `'eni-cleanup' '-regions' 'us-east-1' '-skip-reserved-descriptions' 'Custom Appliance' '-include-tag-keys' 'cleanup-owner' '-exclude-tag-keys' 'do-not-delete,keep'`
+	if command != want {
+		t.Errorf("generateNativeCommand = %s, want %s", command, want)
+	}
+}
\ No newline at end of file
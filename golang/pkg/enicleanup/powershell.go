@@ -0,0 +1,146 @@
+package enicleanup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// psQuote quotes a value as a PowerShell single-quoted string
+func psQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// psArray encodes values as a PowerShell array literal
+func psArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = psQuote(value)
+	}
+	return "@(" + strings.Join(quoted, ", ") + ")"
+}
+
+// generatePowerShellCleanupScript generates a PowerShell script to cleanup
+// orphaned ENIs with the AWS Tools for PowerShell EC2 cmdlets, for Windows
+// hosts without bash. It mirrors the bash script, without the JSON summary.
+func generatePowerShellCleanupScript(regions []string, options ScriptOptions) string {
+	dryRun := "$false"
+	if options.DryRun {
+		dryRun = "$true"
+	}
+
+	return fmt.Sprintf(`
+$ErrorActionPreference = 'Stop'
+Import-Module AWS.Tools.EC2
+
+$regions = %s
+$dryRun = %s
+$reservedDescriptions = %s
+$includeTagKeys = %s
+$excludeTagKeys = %s
+
+Write-Output "Starting ENI cleanup for regions: $($regions -join ', ')"
+
+foreach ($region in $regions) {
+    Write-Output "Scanning region: $region for orphaned ENIs"
+
+    # Find all ENIs in 'available' state
+    Write-Output "Finding available ENIs in $region"
+    $availableEnis = @(Get-EC2NetworkInterface -Region $region -Filter @{ Name = 'status'; Values = 'available' })
+
+    if ($availableEnis.Count -eq 0) {
+        Write-Output "No available ENIs found in $region"
+        continue
+    }
+
+    Write-Output "Found $($availableEnis.Count) available ENIs in $region"
+
+    # Process each ENI
+    foreach ($eni in $availableEnis) {
+        $eniId = $eni.NetworkInterfaceId
+        $description = [string]$eni.Description
+        $tagKeys = @($eni.TagSet | ForEach-Object { $_.Key })
+
+        Write-Output "Processing ENI: $eniId in VPC: $($eni.VpcId)"
+
+        # Skip ENIs with reserved descriptions that should not be deleted
+        if ($reservedDescriptions | Where-Object { $description.Contains($_) }) {
+            Write-Output "Skipping ENI $eniId with reserved description: $description"
+            continue
+        }
+
+        # Only consider ENIs with one of the include tag keys, if any are set
+        if ($includeTagKeys.Count -gt 0 -and -not ($tagKeys | Where-Object { $includeTagKeys -contains $_ })) {
+            Write-Output "Skipping ENI $eniId without any of the include tag keys"
+            continue
+        }
+
+        # Skip ENIs with any of the exclude tag keys
+        if ($tagKeys | Where-Object { $excludeTagKeys -contains $_ }) {
+            Write-Output "Skipping ENI $eniId with an exclude tag key"
+            continue
+        }
+
+        # Check if it has any attachments
+        if ($eni.Attachment -and $eni.Attachment.AttachmentId) {
+            $attachmentId = $eni.Attachment.AttachmentId
+            Write-Output "Detaching ENI $eniId (attachment: $attachmentId)"
+            if (-not $dryRun) {
+                Dismount-EC2NetworkInterface -Region $region -AttachmentId $attachmentId -ForceDismount $true -Force
+
+                # Wait for detachment to complete
+                Write-Output "Waiting for ENI $eniId to detach completely"
+                Start-Sleep -Seconds 5
+            } else {
+                Write-Output "[DRY RUN] Would detach ENI $eniId (attachment: $attachmentId)"
+            }
+        }
+
+        # Delete the ENI
+        Write-Output "Deleting ENI $eniId"
+        if ($dryRun) {
+            Write-Output "[DRY RUN] Would delete ENI $eniId in $region"
+            continue
+        }
+
+        try {
+            Remove-EC2NetworkInterface -Region $region -NetworkInterfaceId $eniId -Force
+            Write-Output "Successfully deleted ENI $eniId in $region"
+            continue
+        } catch {
+            Write-Output "Initial deletion failed for ENI ${eniId}: $_"
+            Write-Output "Trying fallback strategies..."
+        }
+
+        try {
+            # Fallback 1: Try removing all security group associations
+            Write-Output "Fallback 1: Removing security group associations for ENI $eniId"
+            Edit-EC2NetworkInterfaceAttribute -Region $region -NetworkInterfaceId $eniId -Group @() -Force
+
+            Write-Output "Security groups disassociated. Retrying deletion..."
+            Start-Sleep -Seconds 2
+
+            # Try deleting again
+            Remove-EC2NetworkInterface -Region $region -NetworkInterfaceId $eniId -Force
+            Write-Output "Successfully deleted ENI $eniId after security group disassociation"
+        } catch {
+            Write-Output "Deletion still failed after removing security groups: $_"
+
+            # Fallback 2: Tag for manual cleanup
+            Write-Output "Fallback 2: Tagging ENI $eniId for manual cleanup"
+            $timestamp = (Get-Date).ToUniversalTime().ToString("yyyy-MM-dd'T'HH:mm:ss'Z'")
+            try {
+                New-EC2Tag -Region $region -Resource $eniId -Tag @(
+                    @{ Key = 'NeedsManualCleanup'; Value = 'true' },
+                    @{ Key = 'AttemptedCleanupTime'; Value = $timestamp }
+                )
+                Write-Output "Tagged ENI $eniId for manual cleanup"
+            } catch {
+                Write-Output "Failed to tag ENI $eniId for manual cleanup: $_"
+            }
+        }
+    }
+}
+
+Write-Output "ENI cleanup completed"
+`, psArray(regions), dryRun, psArray(options.reservedDescriptions()), psArray(options.IncludeTagKeys), psArray(options.ExcludeTagKeys))
+}
@@ -0,0 +1,107 @@
+package enicleanup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// sharedScanKey groups the resources whose cleanup runs as one command.
+// options is the formatted ScriptOptions, which aren't comparable.
+type sharedScanKey struct {
+	ctx       *pulumi.Context
+	regions   string
+	logOutput bool
+	options   string
+}
+
+// sharedScan is one coordinating cleanup command and the resources it covers
+type sharedScan struct {
+	regions   []string
+	options   ScriptOptions
+	resources []pulumi.Resource
+}
+
+// sharedScans holds the resources attached with AttachSharedENICleanupHandler
+// until RegisterSharedENICleanupHandlers creates their commands
+var sharedScans = struct {
+	sync.Mutex
+	byKey map[sharedScanKey]*sharedScan
+	order []sharedScanKey
+}{byKey: make(map[sharedScanKey]*sharedScan)}
+
+// AttachSharedENICleanupHandler routes a resource's destroy-time cleanup
+// through a single command shared by every resource attached with the same
+// regions and options, instead of registering a command per resource. Many
+// per-resource commands would each scan the same regions concurrently during
+// a destroy; the shared command scans each region once, before any of its
+// resources is destroyed. RegisterSharedENICleanupHandlers must be called
+// once every resource has been attached.
+func AttachSharedENICleanupHandler(
+	ctx *pulumi.Context,
+	resource pulumi.Resource,
+	regions []string,
+	logOutput bool,
+	options ScriptOptions,
+) {
+	key := sharedScanKey{ctx: ctx, regions: strings.Join(regions, ","), logOutput: logOutput, options: fmt.Sprintf("%+v", options)}
+
+	sharedScans.Lock()
+	defer sharedScans.Unlock()
+	scan, ok := sharedScans.byKey[key]
+	if !ok {
+		scan = &sharedScan{regions: regions, options: options}
+		sharedScans.byKey[key] = scan
+		sharedScans.order = append(sharedScans.order, key)
+	}
+	scan.resources = append(scan.resources, resource)
+}
+
+// RegisterSharedENICleanupHandlers creates the coordinating cleanup command
+// for the resources attached with AttachSharedENICleanupHandler. Each command
+// depends on all of its resources, so Pulumi runs it before destroying any of them.
+func RegisterSharedENICleanupHandlers(ctx *pulumi.Context) ([]*local.Command, error) {
+	sharedScans.Lock()
+	defer sharedScans.Unlock()
+
+	var commands []*local.Command
+	var remaining []sharedScanKey
+	for _, key := range sharedScans.order {
+		if key.ctx != ctx {
+			remaining = append(remaining, key)
+			continue
+		}
+		scan := sharedScans.byKey[key]
+		delete(sharedScans.byKey, key)
+
+		// Name the command after its regions and options so it is stable across updates
+		hash := fnv.New32a()
+		fmt.Fprintf(hash, "%s|%t|%s", key.regions, key.logOutput, key.options)
+		cleanupName := fmt.Sprintf("eni-cleanup-shared-%08x", hash.Sum32())
+
+		commandArgs, err := cleanupCommandArgs(scan.regions, scan.options)
+		if err != nil {
+			return nil, err
+		}
+
+		cleanupCommand, err := local.NewCommand(ctx, cleanupName, commandArgs,
+			pulumi.DependsOn(scan.resources),
+			pulumi.DeleteBeforeReplace(true),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.logOutput {
+			exportCleanupOutput(ctx, cleanupCommand, cleanupName)
+		}
+		commands = append(commands, cleanupCommand)
+	}
+	sharedScans.order = remaining
+
+	return commands, nil
+}
\ No newline at end of file
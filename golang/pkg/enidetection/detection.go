@@ -19,10 +19,37 @@ type OrphanedENI struct {
 	Tags             map[string]string
 }
 
-// DetectOrphanedENIs detects orphaned ENIs across specified AWS regions
+// DetectOrphanedENIs lists the detached ENIs visible to the provider. It only
+// reads, through invokes, so it is safe to call during preview. The regions
+// are only used to label results, since the provider determines the region.
 func DetectOrphanedENIs(ctx *pulumi.Context, regions []string, provider *aws.Provider) ([]OrphanedENI, error) {
-	// To be implemented
-	return []OrphanedENI{}, nil
+	var opts []pulumi.InvokeOption
+	if provider != nil {
+		opts = append(opts, pulumi.Provider(provider))
+	}
+
+	result, err := ec2.GetNetworkInterfaces(ctx, &ec2.GetNetworkInterfacesArgs{
+		Filters: []ec2.GetNetworkInterfacesFilter{
+			{
+				Name:   "status",
+				Values: []string{"available"},
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	region := ""
+	if len(regions) == 1 {
+		region = regions[0]
+	}
+
+	enis := make([]OrphanedENI, 0, len(result.Ids))
+	for _, id := range result.Ids {
+		enis = append(enis, OrphanedENI{ID: id, Region: region})
+	}
+	return enis, nil
 }
 
 // IsLikelyOrphaned checks if an ENI is likely orphaned based on its description,
@@ -36,4 +63,4 @@ func IsLikelyOrphaned(eni *ec2.NetworkInterface) bool {
 func LogOrphanedENIsOnDestroy(ctx *pulumi.Context, resourceName string, provider *aws.Provider) (pulumi.Resource, error) {
 	// To be implemented
 	return nil, nil
-}
\ No newline at end of file
+}
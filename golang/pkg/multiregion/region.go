@@ -1,8 +1,13 @@
 package multiregion
 
 import (
+	"fmt"
+	"os"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 )
 
 // RegionConfig represents configuration for multi-region AWS access
@@ -35,8 +40,108 @@ func ConfigureRegions(ctx *pulumi.Context, regions []string, profile *string) (m
 	return providers, nil
 }
 
-// GetAllAwsRegions retrieves a list of all available AWS regions
+// FallbackRegion is used when no region is configured anywhere
+const FallbackRegion = "us-east-1"
+
+// DefaultRegions resolves the regions to clean up. It returns, in order of
+// precedence: regions when not empty, the stack's "regions" config,
+// AWS_REGION, AWS_DEFAULT_REGION, the region of the default AWS config such
+// as the shared config profile's, and finally FallbackRegion with a warning.
+func DefaultRegions(ctx *pulumi.Context, regions []string) []string {
+	if len(regions) > 0 {
+		return regions
+	}
+
+	var configured []string
+	if err := config.New(ctx, "").TryObject("regions", &configured); err == nil && len(configured) > 0 {
+		return configured
+	}
+
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(env); region != "" {
+			return []string{region}
+		}
+	}
+
+	if cfg, err := awsconfig.LoadDefaultConfig(ctx.Context()); err == nil && cfg.Region != "" {
+		return []string{cfg.Region}
+	}
+
+	ctx.Log.Warn(fmt.Sprintf("No region is configured; cleaning up ENIs in %s. Set the regions config, AWS_REGION or a profile region to choose one", FallbackRegion), nil)
+	return []string{FallbackRegion}
+}
+
+// Region sentinels that expand at runtime to the account's regions
+const (
+	// AllEnabledRegions expands to every region enabled for the account,
+	// including opt-in regions the account has opted into
+	AllEnabledRegions = "all-enabled"
+	// AllOptInRegions expands to only the opt-in regions the account has opted into
+	AllOptInRegions = "all-optin"
+)
+
+// regionSentinelStatuses maps each sentinel to the opt-in statuses it includes
+var regionSentinelStatuses = map[string][]string{
+	AllEnabledRegions: {"opt-in-not-required", "opted-in"},
+	AllOptInRegions:   {"opted-in"},
+}
+
+// GetAllAwsRegions retrieves a list of all AWS regions enabled for the account
 func GetAllAwsRegions(ctx *pulumi.Context, provider *aws.Provider) ([]string, error) {
-	// To be implemented
-	return []string{}, nil
+	return getRegionsByOptInStatus(ctx, regionSentinelStatuses[AllEnabledRegions], provider)
+}
+
+// ExpandRegions replaces the "all-enabled" and "all-optin" sentinels with the
+// matching regions, leaving explicit regions untouched. Disabled regions are
+// never included, and the result is de-duplicated in input order.
+func ExpandRegions(ctx *pulumi.Context, regions []string) ([]string, error) {
+	var expanded []string
+	seen := make(map[string]bool)
+	add := func(region string) {
+		if !seen[region] {
+			seen[region] = true
+			expanded = append(expanded, region)
+		}
+	}
+
+	for _, region := range regions {
+		statuses, ok := regionSentinelStatuses[region]
+		if !ok {
+			add(region)
+			continue
+		}
+
+		names, err := getRegionsByOptInStatus(ctx, statuses, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			add(name)
+		}
+	}
+
+	return expanded, nil
+}
+
+// getRegionsByOptInStatus lists the regions with one of the given opt-in statuses
+func getRegionsByOptInStatus(ctx *pulumi.Context, statuses []string, provider *aws.Provider) ([]string, error) {
+	var opts []pulumi.InvokeOption
+	if provider != nil {
+		opts = append(opts, pulumi.Provider(provider))
+	}
+
+	result, err := aws.GetRegions(ctx, &aws.GetRegionsArgs{
+		AllRegions: pulumi.BoolRef(true),
+		Filters: []aws.GetRegionsFilter{
+			{
+				Name:   "opt-in-status",
+				Values: statuses,
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Names, nil
 }
\ No newline at end of file